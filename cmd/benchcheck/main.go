@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aquasecurity/trivy-db/pkg/utils/benchcheck"
+)
+
+// benchcheck reads the output of "go test -bench" from stdin and fails if a benchmark is slower than the baseline
+// by more than the ratio, e.g. "go test -run '^$' -bench . ./... | go run ./cmd/benchcheck -baseline benchmarks/baseline.txt"
+func main() {
+	baselinePath := flag.String("baseline", "benchmarks/baseline.txt", "output of go test -bench to compare against")
+	maxRatio := flag.Float64("max-ratio", 2, "fail if ns/op exceeds the baseline times the ratio")
+	flag.Parse()
+
+	f, err := os.Open(*baselinePath)
+	if err != nil {
+		log.Fatalf("baseline open error: %s", err)
+	}
+	defer f.Close()
+
+	baseline, err := benchcheck.Parse(f)
+	if err != nil {
+		log.Fatalf("baseline parse error: %s", err)
+	}
+	current, err := benchcheck.Parse(os.Stdin)
+	if err != nil {
+		log.Fatalf("benchmark parse error: %s", err)
+	}
+
+	regressions := benchcheck.Compare(baseline, current, *maxRatio)
+	for _, r := range regressions {
+		fmt.Println(r)
+	}
+	if len(regressions) > 0 {
+		log.Fatalf("%d benchmarks regressed by more than x%.2f", len(regressions), *maxRatio)
+	}
+	fmt.Printf("%d benchmarks within x%.2f of the baseline\n", len(current), *maxRatio)
+}
@@ -3,6 +3,7 @@ package pkg
 import (
 	"time"
 
+	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/utils"
 
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc"
@@ -28,7 +29,7 @@ func (ac *AppConfig) NewApp(version string) *cli.App {
 					Usage: "update db only specified distribution",
 					Value: func() *cli.StringSlice {
 						var targets cli.StringSlice
-						for _, v := range vulnsrc.All {
+						for _, v := range vulnsrc.NewAll(db.Config{}) {
 							targets = append(targets, string(v.Name()))
 						}
 						return &targets
@@ -45,8 +46,152 @@ func (ac *AppConfig) NewApp(version string) *cli.App {
 					Value:  24 * time.Hour,
 					EnvVar: "UPDATE_INTERVAL",
 				},
+				cli.BoolFlag{
+					Name:  "nvd-enrichment",
+					Usage: "fill vulnerabilities lacking a title from the NVD feed in the cache directory",
+				},
+				cli.StringFlag{
+					Name:  "plugin-refs",
+					Usage: "CSV file mapping vulnerability IDs to plugin IDs of vulnerability scanners such as Tenable",
+				},
+				cli.DurationFlag{
+					Name:  "stale-after",
+					Usage: "warn about data sources not modified within the duration (0 to disable)",
+				},
+				cli.BoolFlag{
+					Name:  "text-index",
+					Usage: "index titles and descriptions for keyword search (grows the DB)",
+				},
+				cli.BoolFlag{
+					Name:  "cpe-index",
+					Usage: "index the CPEs of vulnerabilities for lookups by CPE (grows the DB)",
+				},
+				cli.DurationFlag{
+					Name:  "source-timeout",
+					Usage: "abort the update of a data source taking longer than the duration and build the others (0 to disable)",
+				},
+				cli.BoolFlag{
+					Name:  "fail-on-source-error",
+					Usage: "fail the build if any data source fails or times out, after updating the others",
+				},
+				cli.BoolFlag{
+					Name:  "force",
+					Usage: "update every source even if the previous build failed after completing some of them",
+				},
+				cli.BoolFlag{
+					Name:  "coalesce-ranges",
+					Usage: "union the vulnerable versions of a vulnerability split across records for the same package",
+				},
+				cli.IntFlag{
+					Name:  "batch-size",
+					Usage: "commit every N records in separate transactions to lower memory (0 for a single transaction per source)",
+				},
+				cli.BoolFlag{
+					Name:  "advisories-only",
+					Usage: "skip vulnerability details such as titles, descriptions and references",
+				},
+				cli.Int64Flag{
+					Name:   "source-date-epoch",
+					Usage:  "record the unix time as the time advisories were fetched (0 to disable)",
+					EnvVar: "SOURCE_DATE_EPOCH",
+				},
+				cli.IntFlag{
+					Name:  "cvss-precision",
+					Usage: "round CVSS scores to N decimal places (0 to keep them as given)",
+				},
+				cli.StringFlag{
+					Name:  "cvss-merge-policy",
+					Usage: "how CVSS scores given more than once or by several sources are reconciled: last-write, max or nvd-first",
+					Value: string(db.CvssLastWrite),
+				},
+				cli.BoolFlag{
+					Name:  "severity-conflicts",
+					Usage: "record vulnerabilities whose vendor severities disagree by more than one level",
+				},
+				cli.StringSliceFlag{
+					Name:  "exclude-id",
+					Usage: "vulnerability ID not to store, e.g. a disputed CVE (can be repeated)",
+				},
+				cli.StringSliceFlag{
+					Name:  "include-id",
+					Usage: "vulnerability ID to store, excluding all the others (can be repeated)",
+				},
+				cli.StringSliceFlag{
+					Name:  "deny-reference-domain",
+					Usage: "drop references to the domain and its subdomains, e.g. example.com (can be repeated)",
+				},
+				cli.BoolFlag{
+					Name:  "bucket-hashes",
+					Usage: "store the hash of each namespace to detect modifications after the build",
+				},
+			},
+		},
+		{
+			Name:   "rebuild-indexes",
+			Usage:  "regenerate the secondary indexes from the advisories",
+			Action: rebuildIndexes,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cache-dir",
+					Usage: "cache directory path",
+					Value: utils.CacheDir(),
+				},
 			},
 		},
+		{
+			Name:   "renormalize-severities",
+			Usage:  "recompute the severities of the stored vulnerabilities with the current normalizer",
+			Action: renormalizeSeverities,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cache-dir",
+					Usage: "cache directory path",
+					Value: utils.CacheDir(),
+				},
+			},
+		},
+		{
+			Name:   "extract",
+			Usage:  "copy the data of a single source into a new database file",
+			Action: extract,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cache-dir",
+					Usage: "cache directory path",
+					Value: utils.CacheDir(),
+				},
+				cli.StringFlag{
+					Name:  "source",
+					Usage: "source ID such as nodejs-security-wg",
+				},
+				cli.StringFlag{
+					Name:  "output",
+					Usage: "output database file path",
+				},
+			},
+		},
+		{
+			Name:   "downgrade",
+			Usage:  "drop buckets and fields unknown to an older schema version",
+			Action: downgrade,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cache-dir",
+					Usage: "cache directory path",
+					Value: utils.CacheDir(),
+				},
+				cli.IntFlag{
+					Name:  "schema-version",
+					Usage: "target schema version",
+					Value: 1,
+				},
+			},
+		},
+		{
+			Name:   "self-test",
+			Usage:  "build a database from embedded fixtures and check the result",
+			Action: selfTest,
+		},
 	}
 
 	return app
@@ -1,27 +1,161 @@
 package pkg
 
 import (
+	"time"
+
 	"github.com/urfave/cli"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
 	"github.com/aquasecurity/trivy-db/pkg/vulndb"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/nvd"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/pluginref"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 )
 
 func build(c *cli.Context) error {
 	cacheDir := c.String("cache-dir")
-	if err := db.Init(cacheDir); err != nil {
+	targets := c.StringSlice("only-update")
+	updateInterval := c.Duration("update-interval")
+
+	dbc := db.Config{
+		OutputPath:     db.Path(cacheDir),
+		StaleAfter:     c.Duration("stale-after"),
+		BuildTextIndex: c.Bool("text-index"),
+		BuildCPEIndex:  c.Bool("cpe-index"),
+		AdvisoriesOnly: c.Bool("advisories-only"),
+		BatchSize:      c.Int("batch-size"),
+		BucketHashes:   c.Bool("bucket-hashes"),
+		CvssPrecision:  c.Int("cvss-precision"),
+		ExcludeIDs:     c.StringSlice("exclude-id"),
+		IncludeIDs:     c.StringSlice("include-id"),
+		CoalesceRanges: c.Bool("coalesce-ranges"),
+		SourceTimeout:  c.Duration("source-timeout"),
+
+		DetectSeverityConflicts: c.Bool("severity-conflicts"),
+		ReferenceDomainDenylist: c.StringSlice("deny-reference-domain"),
+		FailOnSourceError:       c.Bool("fail-on-source-error"),
+	}
+	policy, err := db.ParseCvssMergePolicy(c.String("cvss-merge-policy"))
+	if err != nil {
+		return xerrors.Errorf("invalid option: %w", err)
+	}
+	dbc.CvssMergePolicy = policy
+	if epoch := c.Int64("source-date-epoch"); epoch > 0 {
+		dbc.SourceDate = time.Unix(epoch, 0).UTC()
+	}
+
+	var opts []vulndb.Option
+	if path := c.String("plugin-refs"); path != "" {
+		refs, err := pluginref.Load(path)
+		if err != nil {
+			return xerrors.Errorf("plugin refs load error: %w", err)
+		}
+		opts = append(opts, vulndb.WithPluginRefs(refs))
+	}
+	if c.Bool("force") {
+		opts = append(opts, vulndb.WithForce())
+	}
+	if c.Bool("nvd-enrichment") {
+		details, err := nvd.Load(cacheDir)
+		if err != nil {
+			return xerrors.Errorf("NVD load error: %w", err)
+		}
+		opts = append(opts, vulndb.WithNVDEnrichment(details))
+	}
+
+	// Each build writes its own DB rather than the global one, so that builds to different cache dirs don't clash
+	dbc, err = dbc.Open()
+	if err != nil {
 		return xerrors.Errorf("db initialize error: %w", err)
 	}
+	opts = append(opts, vulndb.WithDBConfig(dbc))
 
-	targets := c.StringSlice("only-update")
-	updateInterval := c.Duration("update-interval")
+	vdb := vulndb.New(cacheDir, updateInterval, opts...)
+	buildErr := vdb.Build(targets)
+
+	// The DB is moved to the cache dir even on failure, so that the next build resumes from the checkpoints
+	if err = dbc.Finalize(); err != nil {
+		return xerrors.Errorf("db finalize error: %w", err)
+	}
+	if buildErr != nil {
+		return xerrors.Errorf("build error: %w", buildErr)
+	}
+
+	return nil
+
+}
+
+func rebuildIndexes(c *cli.Context) error {
+	if err := db.Init(c.String("cache-dir")); err != nil {
+		return xerrors.Errorf("db initialize error: %w", err)
+	}
+	defer db.Close()
+
+	if err := (db.Config{}).RebuildIndexes(); err != nil {
+		return xerrors.Errorf("rebuild error: %w", err)
+	}
+	return nil
+}
+
+func renormalizeSeverities(c *cli.Context) error {
+	if err := db.Init(c.String("cache-dir")); err != nil {
+		return xerrors.Errorf("db initialize error: %w", err)
+	}
+	defer db.Close()
+
+	dbc := db.Config{SeverityNormalizer: vulnerability.New(db.Config{}).Normalize}
+	if err := dbc.RenormalizeSeverities(); err != nil {
+		return xerrors.Errorf("renormalize error: %w", err)
+	}
+	return nil
+}
+
+func extract(c *cli.Context) error {
+	name, output := c.String("source"), c.String("output")
+	if name == "" || output == "" {
+		return xerrors.New("--source and --output are required")
+	}
+
+	if err := db.Init(c.String("cache-dir")); err != nil {
+		return xerrors.Errorf("db initialize error: %w", err)
+	}
+	defer db.Close()
 
-	vdb := vulndb.New(cacheDir, updateInterval)
-	if err := vdb.Build(targets); err != nil {
-		return xerrors.Errorf("build error: %w", err)
+	if err := (db.Config{}).ExtractSource(name, output); err != nil {
+		return xerrors.Errorf("extract error: %w", err)
 	}
+	return nil
+}
+
+func downgrade(c *cli.Context) error {
+	cacheDir := c.String("cache-dir")
+	if err := db.Init(cacheDir); err != nil {
+		return xerrors.Errorf("db initialize error: %w", err)
+	}
+	defer db.Close()
 
+	version := c.Int("schema-version")
+	if err := db.DowngradeTo(version); err != nil {
+		return xerrors.Errorf("downgrade error: %w", err)
+	}
+
+	client := metadata.NewClient(cacheDir)
+	md, err := client.Get()
+	if err != nil {
+		return xerrors.Errorf("metadata error: %w", err)
+	}
+	md.Version = version
+	if err = client.Update(md); err != nil {
+		return xerrors.Errorf("metadata update error: %w", err)
+	}
 	return nil
+}
 
+func selfTest(_ *cli.Context) error {
+	if err := vulndb.SelfTest(); err != nil {
+		return xerrors.Errorf("self-test error: %w", err)
+	}
+	return nil
 }
@@ -2,6 +2,8 @@ package db
 
 import (
 	"encoding/json"
+	"sort"
+	"strings"
 
 	"github.com/aquasecurity/trivy-db/pkg/types"
 	bolt "go.etcd.io/bbolt"
@@ -12,6 +14,9 @@ func (dbc Config) PutAdvisory(tx *bolt.Tx, bktNames []string, key string, adviso
 	if err := dbc.put(tx, bktNames, key, advisory); err != nil {
 		return xerrors.Errorf("failed to put advisory: %w", err)
 	}
+	if len(bktNames) == 2 {
+		dbc.cacheInvalidate(tx, cacheKey("advisories", bktNames[0], bktNames[1]))
+	}
 	return nil
 }
 
@@ -20,18 +25,207 @@ func (dbc Config) ForEachAdvisory(sources []string, pkgName string) (map[string]
 }
 
 func (dbc Config) GetAdvisories(source, pkgName string) ([]types.Advisory, error) {
-	advisories, err := dbc.ForEachAdvisory([]string{source}, pkgName)
+	key := cacheKey("advisories", source, pkgName)
+	var cached []types.Advisory
+	if dbc.cacheGet(key, &cached) {
+		return cached, nil
+	}
+
+	var (
+		advisories []types.Advisory
+		exact      bool
+	)
+	generation := dbc.cacheGeneration()
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		var err error
+		advisories, exact, err = dbc.readAdvisories(tx, source, pkgName)
+		return err
+	})
 	if err != nil {
-		return nil, xerrors.Errorf("advisory foreach error: %w", err)
+		return nil, xerrors.Errorf("failed to get advisories: %w", err)
 	}
+
+	// PutAdvisory invalidates only the entry of the bucket it writes
+	if exact {
+		dbc.cacheSet(key, advisories, generation)
+	}
+	return advisories, nil
+}
+
+func (dbc Config) getAdvisories(tx *bolt.Tx, source, pkgName string) ([]types.Advisory, error) {
+	advisories, _, err := dbc.readAdvisories(tx, source, pkgName)
+	return advisories, err
+}
+
+// readAdvisories returns the advisories of the package, and true if they were read only from the bucket
+// of the namespace and the package, not through a namespace prefix or aliases.
+func (dbc Config) readAdvisories(tx *bolt.Tx, source, pkgName string) ([]types.Advisory, bool, error) {
+	advisories, err := dbc.forEachAdvisoryTx(tx, source, pkgName)
+	if err != nil {
+		return nil, false, err
+	}
+	aliases, err := dbc.getPackageAliases(tx, source, pkgName)
+	if err != nil {
+		return nil, false, xerrors.Errorf("package alias error: %w", err)
+	}
+	exact := len(aliases) == 0 && singleNamespace(tx, source)
+
+	// The namespace may share advisories with another one, e.g. "oracle linux 8.6" => "oracle linux 8"
 	if len(advisories) == 0 {
+		namespace, err := dbc.getNamespaceAlias(tx, source)
+		if err != nil {
+			return nil, false, xerrors.Errorf("namespace alias error: %w", err)
+		} else if namespace != "" {
+			if advisories, err = dbc.forEachAdvisoryTx(tx, namespace, pkgName); err != nil {
+				return nil, false, err
+			}
+			exact = false
+		}
+	}
+
+	results, err := toAdvisories(advisories)
+	if err != nil {
+		return nil, false, err
+	}
+	if !dbc.IncludeObsolete {
+		results = dropObsolete(results)
+	}
+	if !dbc.IncludeDisputed {
+		if results, err = dropDisputed(txStore{dbc: dbc, tx: tx}, results); err != nil {
+			return nil, false, err
+		}
+	}
+	return results, exact, nil
+}
+
+// singleNamespace returns true if the source matches only the namespace of the same name,
+// not e.g. every "pip::" namespace
+func singleNamespace(tx *bolt.Tx, source string) bool {
+	if !strings.Contains(source, "::") {
+		return true
+	}
+	c := tx.Cursor()
+	k, _ := c.Seek([]byte(source))
+	if string(k) != source {
+		return false
+	}
+	k, _ = c.Next()
+	return !strings.HasPrefix(string(k), source)
+}
+
+// dropDisputed removes the advisories of vulnerabilities flagged as disputed in the vulnerability bucket
+func dropDisputed(s store, advisories []types.Advisory) ([]types.Advisory, error) {
+	var filtered []types.Advisory
+	for _, adv := range advisories {
+		if v := s.value([]string{vulnerabilityBucket}, adv.VulnerabilityID); v != nil {
+			var vuln struct {
+				Disputed bool
+			}
+			if err := json.Unmarshal(v, &vuln); err != nil {
+				return nil, xerrors.Errorf("JSON unmarshal error (%s): %w", adv.VulnerabilityID, err)
+			}
+			if vuln.Disputed {
+				continue
+			}
+		}
+		filtered = append(filtered, adv)
+	}
+	return filtered, nil
+}
+
+// GetAdvisoriesPaged returns at most limit advisories of the package from offset, ordered by vulnerability ID,
+// and the total number of the advisories, e.g. for UIs listing hundreds of kernel advisories.
+// A limit of zero or less returns all the advisories from offset.
+func (dbc Config) GetAdvisoriesPaged(source, pkgName string, offset, limit int) ([]types.Advisory, int, error) {
+	if offset < 0 {
+		return nil, 0, xerrors.Errorf("negative offset: %d", offset)
+	}
+	advisories, err := dbc.GetAdvisories(source, pkgName)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("failed to get paged advisories: %w", err)
+	}
+	page, total := paginate(advisories, offset, limit)
+	return page, total, nil
+}
+
+// paginate sorts the advisories by vulnerability ID and returns the page and the total number
+func paginate(advisories []types.Advisory, offset, limit int) ([]types.Advisory, int) {
+	sort.Slice(advisories, func(i, j int) bool {
+		return advisories[i].VulnerabilityID < advisories[j].VulnerabilityID
+	})
+
+	total := len(advisories)
+	if offset >= total {
+		return nil, total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return advisories[offset:end], total
+}
+
+// GetFixableAdvisories returns the advisories of the package having a fixed version, ordered by vulnerability ID,
+// e.g. for remediation dashboards showing only actionable vulnerabilities.
+func (dbc Config) GetFixableAdvisories(namespace, pkgName string) ([]types.Advisory, error) {
+	advisories, err := dbc.GetAdvisories(namespace, pkgName)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get fixable advisories: %w", err)
+	}
+	return fixableAdvisories(advisories), nil
+}
+
+// fixableAdvisories returns the advisories having a fixed version, sorted by vulnerability ID
+func fixableAdvisories(advisories []types.Advisory) []types.Advisory {
+	var fixable []types.Advisory
+	for _, adv := range advisories {
+		if adv.HasFix() {
+			fixable = append(fixable, adv)
+		}
+	}
+	sort.Slice(fixable, func(i, j int) bool {
+		return fixable[i].VulnerabilityID < fixable[j].VulnerabilityID
+	})
+	return fixable
+}
+
+// GetTaggedAdvisories returns the advisories of the package having the tag, ordered by vulnerability ID,
+// e.g. "kev" for the vulnerabilities known to be exploited.
+func (dbc Config) GetTaggedAdvisories(namespace, pkgName, tag string) ([]types.Advisory, error) {
+	advisories, err := dbc.GetAdvisories(namespace, pkgName)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get tagged advisories: %w", err)
+	}
+	return taggedAdvisories(advisories, tag), nil
+}
+
+// taggedAdvisories returns the advisories having the tag, sorted by vulnerability ID
+func taggedAdvisories(advisories []types.Advisory, tag string) []types.Advisory {
+	var tagged []types.Advisory
+	for _, adv := range advisories {
+		for _, t := range adv.Tags {
+			if t == tag {
+				tagged = append(tagged, adv)
+				break
+			}
+		}
+	}
+	sort.Slice(tagged, func(i, j int) bool {
+		return tagged[i].VulnerabilityID < tagged[j].VulnerabilityID
+	})
+	return tagged
+}
+
+// toAdvisories decodes the values keyed by vulnerability ID
+func toAdvisories(values map[string]Value) ([]types.Advisory, error) {
+	if len(values) == 0 {
 		return nil, nil
 	}
 
 	var results []types.Advisory
-	for vulnID, v := range advisories {
+	for vulnID, v := range values {
 		var advisory types.Advisory
-		if err = json.Unmarshal(v.Content, &advisory); err != nil {
+		if err := json.Unmarshal(v.Content, &advisory); err != nil {
 			return nil, xerrors.Errorf("failed to unmarshal advisory JSON: %w", err)
 		}
 
@@ -48,3 +242,179 @@ func (dbc Config) GetAdvisories(source, pkgName string) ([]types.Advisory, error
 	}
 	return results, nil
 }
+
+// FindByPackage returns the advisories of the package in every namespace, grouped by namespace,
+// for callers not knowing the ecosystem, e.g. "requests" in "pip::" and "rubygems::" namespaces.
+// Advisories stored under package aliases are included, but namespace aliases are not followed
+// since the aliased namespace is searched as well.
+func (dbc Config) FindByPackage(name string) (map[string][]types.Advisory, error) {
+	results := map[string][]types.Advisory{}
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(ns []byte, _ *bolt.Bucket) error {
+			if _, ok := internalBuckets[string(ns)]; ok {
+				return nil
+			}
+			values, err := dbc.forEachAdvisoryTx(tx, string(ns), name)
+			if err != nil {
+				return err
+			}
+			advisories, err := toAdvisories(values)
+			if err != nil {
+				return err
+			}
+			if !dbc.IncludeObsolete {
+				advisories = dropObsolete(advisories)
+			}
+			if !dbc.IncludeDisputed {
+				if advisories, err = dropDisputed(txStore{dbc: dbc, tx: tx}, advisories); err != nil {
+					return err
+				}
+			}
+			if len(advisories) == 0 {
+				return nil
+			}
+			sort.Slice(advisories, func(i, j int) bool {
+				return advisories[i].VulnerabilityID < advisories[j].VulnerabilityID
+			})
+			results[string(ns)] = advisories
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to find advisories of %s: %w", name, err)
+	}
+	return results, nil
+}
+
+// GetAllOSAdvisories returns the advisories of the OS package in every OS namespace, grouped by namespace,
+// e.g. "openssl" in "debian 10" and "alpine 3.15". Language namespaces such as "npm::" are excluded.
+func (dbc Config) GetAllOSAdvisories(pkgName string) (map[string][]types.Advisory, error) {
+	results := map[string][]types.Advisory{}
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(ns []byte, _ *bolt.Bucket) error {
+			if _, ok := internalBuckets[string(ns)]; ok || isLanguageNamespace(string(ns)) {
+				return nil
+			}
+			advisories, err := dbc.getAdvisories(tx, string(ns), pkgName)
+			if err != nil {
+				return err
+			} else if len(advisories) == 0 {
+				return nil
+			}
+			sort.Slice(advisories, func(i, j int) bool {
+				return advisories[i].VulnerabilityID < advisories[j].VulnerabilityID
+			})
+			results[string(ns)] = advisories
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get OS advisories of %s: %w", pkgName, err)
+	}
+	return results, nil
+}
+
+// isLanguageNamespace returns true if the namespace is prefixed by a language ecosystem, e.g. "pip::GitHub Security Advisory pip"
+func isLanguageNamespace(namespace string) bool {
+	return strings.Index(namespace, "::") > 0
+}
+
+// forEachAdvisoryTx returns the advisories of the package in the source including those stored under its aliases
+func (dbc Config) forEachAdvisoryTx(tx *bolt.Tx, source, pkgName string) (map[string]Value, error) {
+	advisories, err := dbc.forEachTx(tx, []string{source, pkgName})
+	if err != nil {
+		return nil, xerrors.Errorf("advisory foreach error: %w", err)
+	}
+
+	// Advisories may be stored under another name of the package
+	aliases, err := dbc.getPackageAliases(tx, source, pkgName)
+	if err != nil {
+		return nil, xerrors.Errorf("package alias error: %w", err)
+	}
+	for _, alias := range aliases {
+		aliased, err := dbc.forEachTx(tx, []string{source, alias})
+		if err != nil {
+			return nil, xerrors.Errorf("advisory foreach error: %w", err)
+		}
+		for vulnID, v := range aliased {
+			if _, ok := advisories[vulnID]; !ok {
+				advisories[vulnID] = v
+			}
+		}
+	}
+	return advisories, nil
+}
+
+// AdvisoryWithDetail is an advisory joined with the vulnerability it is for
+type AdvisoryWithDetail struct {
+	types.Advisory
+	PkgName       string
+	Severity      types.Severity
+	Vulnerability types.Vulnerability
+}
+
+// GetAdvisoriesBySeverity returns the advisories in the namespace with the severity at or above min.
+// The severity given by the data source of the namespace takes precedence over the NVD one.
+// Advisories with unknown severity are returned only when min is SeverityUnknown.
+func (dbc Config) GetAdvisoriesBySeverity(namespace string, min types.Severity) ([]AdvisoryWithDetail, error) {
+	var results []AdvisoryWithDetail
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(namespace))
+		if root == nil {
+			return nil
+		}
+
+		source, err := dbc.getDataSource(tx, namespace)
+		if err != nil {
+			return xerrors.Errorf("data source error: %w", err)
+		}
+
+		return root.ForEach(func(pkgName, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			advisories, err := dbc.getAdvisories(tx, namespace, string(pkgName))
+			if err != nil {
+				return err
+			}
+
+			for _, adv := range advisories {
+				var vuln types.Vulnerability
+				if b := dbc.getTx(tx, []string{vulnerabilityBucket}, adv.VulnerabilityID); b != nil {
+					if err = json.Unmarshal(b, &vuln); err != nil {
+						return xerrors.Errorf("failed to unmarshal the vulnerability %s: %w", adv.VulnerabilityID, err)
+					}
+				}
+
+				severity, ok := vuln.VendorSeverity[source.ID]
+				if !ok {
+					severity, _ = types.NewSeverity(vuln.Severity)
+				}
+				if severity == types.SeverityUnknown && min != types.SeverityUnknown {
+					continue
+				} else if severity < min {
+					continue
+				}
+
+				results = append(results, AdvisoryWithDetail{
+					Advisory:      adv,
+					PkgName:       string(pkgName),
+					Severity:      severity,
+					Vulnerability: vuln,
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get advisories by severity: %w", err)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].PkgName != results[j].PkgName {
+			return results[i].PkgName < results[j].PkgName
+		}
+		return results[i].VulnerabilityID < results[j].VulnerabilityID
+	})
+	return results, nil
+}
@@ -1,25 +1,169 @@
 package db
 
 import (
+	"bytes"
 	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/go-version"
 	bolt "go.etcd.io/bbolt"
 
 	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
 )
 
 const (
 	advisoryDetailBucket = "advisory-detail"
 )
 
+// e.g. "1.2.3" in ">= 1.2.3, < 1.3.0"
+var constraintVersionRegexp = regexp.MustCompile(`v?(\d[^\s,|]*)`)
+
 func (dbc Config) PutAdvisoryDetail(tx *bolt.Tx, vulnID, pkgName string, nestedBktNames []string, advisory interface{}) error {
-	bktNames := append([]string{advisoryDetailBucket, vulnID}, nestedBktNames...)
-	if err := dbc.put(tx, bktNames, pkgName, advisory); err != nil {
-		return xerrors.Errorf("failed to put advisory detail: %w", err)
+	written, err := dbc.putAdvisoryDetail(txStore{dbc: dbc, tx: tx}, vulnID, pkgName, nestedBktNames, advisory)
+	if err != nil {
+		return err
+	}
+	if written && len(nestedBktNames) > 0 {
+		dbc.countAdvisory(tx, nestedBktNames[0])
 	}
 	return nil
 }
 
+// putAdvisoryDetail filters, normalizes and merges the advisory with the stored one, and returns true if it is written.
+// It is shared with MemoryDB.
+func (dbc Config) putAdvisoryDetail(s store, vulnID, pkgName string, nestedBktNames []string, advisory interface{}) (bool, error) {
+	vulnID = normalizeVulnID(vulnID)
+	if dbc.filteredOut(vulnID) {
+		return false, nil
+	}
+	bktNames := append([]string{advisoryDetailBucket, vulnID}, nestedBktNames...)
+	advisory, err := dbc.checkRangeOverlap(vulnID, pkgName, advisory)
+	if err != nil {
+		return false, err
+	}
+	b, err := json.Marshal(canonicalize(advisory))
+	if err != nil {
+		return false, xerrors.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	existing := s.value(bktNames, pkgName)
+	if existing != nil && dbc.CoalesceRanges {
+		if err = putRangeFragment(s, bktNames, pkgName, existing, b); err != nil {
+			return false, err
+		}
+	}
+	if existing != nil {
+		switch dbc.AdvisoryMergeStrategy {
+		case MergeKeepFirst:
+			return false, nil
+		case MergeUnionRanges:
+			if b, err = unionRanges(existing, b); err != nil {
+				return false, xerrors.Errorf("failed to merge advisories: %w", err)
+			}
+		}
+	}
+
+	// The same advisory often repeats, e.g. across OVAL releases.
+	// Skip rewriting it so that pages are not dirtied for nothing.
+	if !dbc.DisableAdvisoryDedup && bytes.Equal(existing, b) {
+		return false, nil
+	}
+
+	if err = s.putValue(bktNames, pkgName, b); err != nil {
+		return false, xerrors.Errorf("failed to put advisory detail: %w", err)
+	}
+	return true, nil
+}
+
+// unionRanges combines VulnerableVersions and PatchedVersions of the two advisories.
+// The other fields are taken from the new advisory.
+func unionRanges(existing, b []byte) ([]byte, error) {
+	var oldAdv, newAdv map[string]interface{}
+	if err := json.Unmarshal(existing, &oldAdv); err != nil {
+		return nil, xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+	if err := json.Unmarshal(b, &newAdv); err != nil {
+		return nil, xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+
+	for _, key := range []string{"VulnerableVersions", "PatchedVersions"} {
+		var versions []string
+		for _, adv := range []map[string]interface{}{oldAdv, newAdv} {
+			vs, _ := adv[key].([]interface{})
+			for _, v := range vs {
+				if s, ok := v.(string); ok {
+					versions = append(versions, s)
+				}
+			}
+		}
+		if len(versions) > 0 {
+			newAdv[key] = sortConstraints(ustrings.Unique(versions))
+		}
+	}
+	return json.Marshal(newAdv)
+}
+
+// canonicalize sorts the version constraints of the advisory so that the DB doesn't depend on the input order.
+// Other types than types.Advisory are returned as is.
+func canonicalize(advisory interface{}) interface{} {
+	switch adv := advisory.(type) {
+	case types.Advisory:
+		adv.VulnerableVersions = sortConstraints(adv.VulnerableVersions)
+		adv.PatchedVersions = sortConstraints(adv.PatchedVersions)
+		adv.UnaffectedVersions = sortConstraints(adv.UnaffectedVersions)
+		return adv
+	case *types.Advisory:
+		if adv == nil {
+			return advisory
+		}
+		return canonicalize(*adv)
+	}
+	return advisory
+}
+
+// sortConstraints returns the sorted copy of the constraints, ordered by the first version in each constraint,
+// then lexically. Constraints without a parsable version come last, e.g. ">= 1.2, < 1.3", "<2.0", "*".
+func sortConstraints(constraints []string) []string {
+	if len(constraints) < 2 {
+		return constraints
+	}
+
+	type constraint struct {
+		s   string
+		ver *version.Version
+	}
+	var cs []constraint
+	for _, c := range constraints {
+		var ver *version.Version
+		if m := constraintVersionRegexp.FindStringSubmatch(c); m != nil {
+			ver, _ = version.NewVersion(m[1])
+		}
+		cs = append(cs, constraint{s: c, ver: ver})
+	}
+	sort.SliceStable(cs, func(i, j int) bool {
+		switch {
+		case cs[i].ver != nil && cs[j].ver != nil:
+			if c := cs[i].ver.Compare(cs[j].ver); c != 0 {
+				return c < 0
+			}
+		case cs[i].ver != nil || cs[j].ver != nil:
+			return cs[i].ver != nil
+		}
+		return cs[i].s < cs[j].s
+	})
+
+	sorted := make([]string, 0, len(cs))
+	for _, c := range cs {
+		sorted = append(sorted, c.s)
+	}
+	return sorted
+}
+
 // SaveAdvisoryDetails Extract advisories from 'advisory-detail' bucket and copy them in each
 func (dbc Config) SaveAdvisoryDetails(tx *bolt.Tx, vulnID string) error {
 	root := tx.Bucket([]byte(advisoryDetailBucket))
@@ -60,8 +204,8 @@ func (dbc Config) saveAdvisories(tx *bolt.Tx, bkt *bolt.Bucket, bktNames []strin
 
 			// Put the advisory in vendor's bucket such as Debian and Ubuntu
 			bkts := append(bktNames, string(k))
-			if err := dbc.put(tx, bkts, vulnID, detail); err != nil {
-				return xerrors.Errorf("database put error: %w", err)
+			if err := dbc.saveAdvisory(txStore{dbc: dbc, tx: tx}, bkts, vulnID, detail); err != nil {
+				return err
 			}
 		}
 
@@ -74,6 +218,33 @@ func (dbc Config) saveAdvisories(tx *bolt.Tx, bkt *bolt.Bucket, bktNames []strin
 	return nil
 }
 
+// saveAdvisory puts the advisory detail in the bucket of the namespace unless it is below MinSeverity.
+// It is shared with MemoryDB.
+func (dbc Config) saveAdvisory(s store, bktNames []string, vulnID string, detail map[string]interface{}) error {
+	if below, err := dbc.belowMinSeverity(s, bktNames[0], vulnID, detail); err != nil {
+		return xerrors.Errorf("severity threshold error: %w", err)
+	} else if below {
+		return nil
+	}
+	if err := putJSON(s, bktNames, vulnID, detail); err != nil {
+		return xerrors.Errorf("database put error: %w", err)
+	}
+	return dbc.checkAdvisoryLimit(s, bktNames)
+}
+
+// checkAdvisoryLimit returns an error if the package bucket has more advisories than MaxAdvisoriesPerPackage,
+// which usually means the source was parsed incorrectly.
+func (dbc Config) checkAdvisoryLimit(s store, bktNames []string) error {
+	if dbc.MaxAdvisoriesPerPackage <= 0 {
+		return nil
+	}
+	if s.countKeys(bktNames, dbc.MaxAdvisoriesPerPackage) > dbc.MaxAdvisoriesPerPackage {
+		return xerrors.Errorf("too many advisories for %s (more than %d)", strings.Join(bktNames, "/"),
+			dbc.MaxAdvisoriesPerPackage)
+	}
+	return nil
+}
+
 func (dbc Config) DeleteAdvisoryDetailBucket() error {
 	return dbc.deleteBucket(advisoryDetailBucket)
 }
@@ -1,6 +1,7 @@
 package db_test
 
 import (
+	"fmt"
 	"testing"
 
 	bolt "go.etcd.io/bbolt"
@@ -83,3 +84,376 @@ func TestConfig_SaveAdvisoryDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_SaveAdvisoryDetailsMinSeverity(t *testing.T) {
+	type saved struct {
+		key   []string
+		value types.Advisory
+	}
+	debian := saved{
+		key:   []string{"debian 10", "node-minimist", "CVE-2020-7598"},
+		value: types.Advisory{FixedVersion: "1.2.0-1+deb10u1", Severity: types.SeverityLow},
+	}
+	npm := saved{
+		key: []string{"npm::Node.js Ecosystem Security Working Group", "minimist", "CVE-2020-7598"},
+		value: types.Advisory{
+			PatchedVersions:    []string{">=1.2.3"},
+			VulnerableVersions: []string{"<1.2.3"},
+		},
+	}
+	tests := []struct {
+		name    string
+		dbc     db.Config
+		want    []saved
+		skipped []saved
+	}{
+		{
+			name: "no threshold",
+			dbc:  db.Config{},
+			want: []saved{debian, npm},
+		},
+		{
+			name:    "global threshold",
+			dbc:     db.Config{MinSeverity: types.SeverityCritical},
+			skipped: []saved{debian, npm},
+		},
+		{
+			name: "all severities for npm",
+			dbc: db.Config{
+				MinSeverity: types.SeverityCritical,
+				MinSeverityPerNamespace: map[string]types.Severity{
+					"npm::Node.js Ecosystem Security Working Group": types.SeverityUnknown,
+				},
+			},
+			want:    []saved{npm},
+			skipped: []saved{debian},
+		},
+		{
+			// The LOW advisory in Debian is skipped though NVD says HIGH
+			name: "advisory severity preferred",
+			dbc: db.Config{
+				MinSeverityPerNamespace: map[string]types.Severity{
+					"debian 10": types.SeverityMedium,
+					"npm::Node.js Ecosystem Security Working Group": types.SeverityHigh,
+				},
+			},
+			want:    []saved{npm},
+			skipped: []saved{debian},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := dbtest.InitDB(t, []string{"testdata/fixtures/severity-threshold.yaml"})
+			defer db.Close()
+
+			err := tt.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				return tt.dbc.SaveAdvisoryDetails(tx, "CVE-2020-7598")
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, db.Close())
+			for _, w := range tt.want {
+				dbtest.JSONEq(t, db.Path(tmpDir), w.key, w.value)
+			}
+			for _, s := range tt.skipped {
+				// The namespace has nothing but the skipped advisory
+				dbtest.NoBucket(t, db.Path(tmpDir), s.key[:1])
+			}
+		})
+	}
+}
+
+func TestConfig_PutAdvisoryDetail(t *testing.T) {
+	base := types.Advisory{FixedVersion: "2.9.3-r0"}
+	tests := []struct {
+		name    string
+		dbc     db.Config
+		second  types.Advisory
+		want    types.Advisory
+		written bool
+	}{
+		{
+			name:    "identical advisory is skipped",
+			second:  base,
+			want:    base,
+			written: false,
+		},
+		{
+			name:    "differing field is written",
+			second:  types.Advisory{FixedVersion: "2.9.3-r0", Severity: types.SeverityHigh},
+			want:    types.Advisory{FixedVersion: "2.9.3-r0", Severity: types.SeverityHigh},
+			written: true,
+		},
+		{
+			name:    "dedup disabled",
+			dbc:     db.Config{DisableAdvisoryDedup: true},
+			second:  base,
+			want:    base,
+			written: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			update := func(fn func(tx *bolt.Tx) error) int {
+				before := tt.dbc.Connection().Stats().TxStats.Write
+				require.NoError(t, tt.dbc.BatchUpdate(fn))
+				return tt.dbc.Connection().Stats().TxStats.Write - before
+			}
+			put := func(adv types.Advisory) int {
+				return update(func(tx *bolt.Tx) error {
+					return tt.dbc.PutAdvisoryDetail(tx, "CVE-2019-14904", "ansible", []string{"alpine 3.14"}, adv)
+				})
+			}
+			put(base)
+			writes := put(tt.second)
+
+			// Only the pages written by any commit, e.g. the meta page, are written when nothing changes
+			empty := update(func(tx *bolt.Tx) error { return nil })
+			assert.Equal(t, tt.written, writes > empty, "writes: %d, empty commit: %d", writes, empty)
+
+			require.NoError(t, db.Close())
+			dbtest.JSONEq(t, db.Path(tmpDir), []string{"advisory-detail", "CVE-2019-14904", "alpine 3.14", "ansible"}, tt.want)
+		})
+	}
+}
+
+func TestConfig_PutAdvisoryDetailMergeStrategy(t *testing.T) {
+	first := types.Advisory{
+		VulnerableVersions: []string{"<1.2.6"},
+		PatchedVersions:    []string{">=1.2.6"},
+	}
+	second := types.Advisory{
+		VulnerableVersions: []string{">=2.0.0 <2.0.5"},
+		PatchedVersions:    []string{">=2.0.5"},
+		Severity:           types.SeverityHigh,
+	}
+	tests := []struct {
+		name     string
+		strategy db.AdvisoryMergeStrategy
+		want     types.Advisory
+	}{
+		{
+			name: "default",
+			want: second,
+		},
+		{
+			name:     "overwrite",
+			strategy: db.MergeOverwrite,
+			want:     second,
+		},
+		{
+			name:     "keep-first",
+			strategy: db.MergeKeepFirst,
+			want:     first,
+		},
+		{
+			name:     "union-ranges",
+			strategy: db.MergeUnionRanges,
+			want: types.Advisory{
+				VulnerableVersions: []string{"<1.2.6", ">=2.0.0 <2.0.5"},
+				PatchedVersions:    []string{">=1.2.6", ">=2.0.5"},
+				Severity:           types.SeverityHigh,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{AdvisoryMergeStrategy: tt.strategy}
+			bktNames := []string{"npm::Node.js Ecosystem Security Working Group"}
+			for _, adv := range []types.Advisory{first, second} {
+				err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+					return dbc.PutAdvisoryDetail(tx, "CVE-2020-7598", "minimist", bktNames, adv)
+				})
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, db.Close())
+			key := append([]string{"advisory-detail", "CVE-2020-7598"}, append(bktNames, "minimist")...)
+			dbtest.JSONEq(t, db.Path(tmpDir), key, tt.want)
+		})
+	}
+}
+
+func TestConfig_PutAdvisoryDetailRangeOverlap(t *testing.T) {
+	// 1.2.3 is both vulnerable and patched
+	overlapping := types.Advisory{
+		VulnerableVersions: []string{">=1.0.0, <1.2.6"},
+		PatchedVersions:    []string{"<1.0.0", ">=1.2.3"},
+	}
+	tests := []struct {
+		name     string
+		policy   db.RangeOverlapPolicy
+		advisory types.Advisory
+		want     types.Advisory
+		wantErr  string
+	}{
+		{
+			name:     "default",
+			advisory: overlapping,
+			want:     overlapping,
+		},
+		{
+			name:     "ignore",
+			policy:   db.RangeOverlapIgnore,
+			advisory: overlapping,
+			want:     overlapping,
+		},
+		{
+			name:     "strict",
+			policy:   db.RangeOverlapStrict,
+			advisory: overlapping,
+			wantErr:  `1.2.3 is both vulnerable and patched (">=1.2.3")`,
+		},
+		{
+			name:     "vulnerable-wins",
+			policy:   db.RangeOverlapVulnerableWins,
+			advisory: overlapping,
+			want: types.Advisory{
+				VulnerableVersions: []string{">=1.0.0, <1.2.6"},
+				PatchedVersions:    []string{"<1.0.0"},
+			},
+		},
+		{
+			name:   "strict without overlap",
+			policy: db.RangeOverlapStrict,
+			advisory: types.Advisory{
+				VulnerableVersions: []string{">=1.0.0 <1.2.6"},
+				PatchedVersions:    []string{"<1.0.0", ">=1.2.6"},
+			},
+			want: types.Advisory{
+				VulnerableVersions: []string{">=1.0.0 <1.2.6"},
+				PatchedVersions:    []string{"<1.0.0", ">=1.2.6"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{RangeOverlapPolicy: tt.policy}
+			bktNames := []string{"npm::Node.js Ecosystem Security Working Group"}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				return dbc.PutAdvisoryDetail(tx, "CVE-2020-7598", "minimist", bktNames, tt.advisory)
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NoError(t, db.Close())
+			key := append([]string{"advisory-detail", "CVE-2020-7598"}, append(bktNames, "minimist")...)
+			dbtest.JSONEq(t, db.Path(tmpDir), key, tt.want)
+		})
+	}
+}
+
+func BenchmarkConfig_PutAdvisoryDetail(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		dbc  db.Config
+	}{
+		{name: "dedup", dbc: db.Config{}},
+		{name: "no dedup", dbc: db.Config{DisableAdvisoryDedup: true}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			require.NoError(b, db.Init(b.TempDir()))
+			defer db.Close()
+
+			// The same advisories repeated across releases
+			advisory := types.Advisory{FixedVersion: "1.1.1k-r0", VendorIDs: []string{"ALSA-2021-0001"}}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				err := bm.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+					for j := 0; j < 100; j++ {
+						pkgName := fmt.Sprintf("pkg-%d", j)
+						if err := bm.dbc.PutAdvisoryDetail(tx, "CVE-2021-3449", pkgName, []string{"alpine 3.14"}, advisory); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+// BenchmarkPutAdvisoryDetail measures writing distinct advisories as sources do, unlike the repeated ones above.
+// See benchmarks/baseline.txt for the baseline.
+func BenchmarkPutAdvisoryDetail(b *testing.B) {
+	require.NoError(b, db.Init(b.TempDir()))
+	defer db.Close()
+
+	dbc := db.Config{}
+	bktNames := []string{"npm::Node.js Ecosystem Security Working Group"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+			for j := 0; j < 100; j++ {
+				advisory := types.Advisory{
+					VulnerableVersions: []string{fmt.Sprintf("<1.%d.%d", i, j)},
+					PatchedVersions:    []string{fmt.Sprintf(">=1.%d.%d", i, j)},
+				}
+				vulnID := fmt.Sprintf("CVE-2021-%d", 10000+j)
+				if err := dbc.PutAdvisoryDetail(tx, vulnID, fmt.Sprintf("pkg-%d", j), bktNames, advisory); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(b, err)
+	}
+}
+
+func TestConfig_PutAdvisoryDetailCanonicalOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		advisory interface{}
+		want     types.Advisory
+	}{
+		{
+			name: "unsorted patched versions",
+			advisory: types.Advisory{
+				VulnerableVersions: []string{">=2.0.0, <2.0.3", "<1.10.2"},
+				PatchedVersions:    []string{">=2.0.3", "^1.10.2", ">= 1.9.5, < 1.10.0"},
+			},
+			want: types.Advisory{
+				VulnerableVersions: []string{"<1.10.2", ">=2.0.0, <2.0.3"},
+				PatchedVersions:    []string{">= 1.9.5, < 1.10.0", "^1.10.2", ">=2.0.3"},
+			},
+		},
+		{
+			name: "same version sorted lexically",
+			advisory: &types.Advisory{
+				PatchedVersions: []string{">=1.2.0", "<1.2.0", "*"},
+			},
+			want: types.Advisory{
+				PatchedVersions: []string{"<1.2.0", ">=1.2.0", "*"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := dbtest.InitDB(t, nil)
+
+			dbc := db.Config{}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				return dbc.PutAdvisoryDetail(tx, "CVE-2014-7205", "bassmaster", []string{"npm::Node.js Ecosystem Security Working Group"}, tt.advisory)
+			})
+			require.NoError(t, err)
+			require.NoError(t, db.Close())
+
+			dbtest.JSONEq(t, db.Path(tmpDir), []string{"advisory-detail", "CVE-2014-7205", "npm::Node.js Ecosystem Security Working Group", "bassmaster"}, tt.want)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"encoding/json"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+const (
+	advisoryGroupBucket = "advisory-group"
+)
+
+// GroupedAdvisory is a package-level advisory bundled in a vendor advisory such as DSA-5122-1 and GHSA
+type GroupedAdvisory struct {
+	types.Advisory
+	Namespace string
+	PkgName   string
+}
+
+// PutAdvisoryGroup ties the advisory of the package in the namespace to the parent vendor advisory,
+// i.e. "advisory-group" => parent ID => namespace => package name => vulnerability ID
+func (dbc Config) PutAdvisoryGroup(tx *bolt.Tx, parentID, namespace, pkgName, vulnID string) error {
+	if dbc.filteredOut(vulnID) {
+		return nil
+	}
+	if err := dbc.putBytes(tx, []string{advisoryGroupBucket, parentID, namespace, pkgName}, vulnID, []byte{}); err != nil {
+		return xerrors.Errorf("failed to put advisory group: %w", err)
+	}
+	return nil
+}
+
+// GetAdvisoryGroup returns the package-level advisories tied to the vendor advisory, e.g. "DSA-5122-1",
+// sorted by namespace, package name and vulnerability ID. It works both before and after the DB is optimized.
+func (dbc Config) GetAdvisoryGroup(id string) ([]GroupedAdvisory, error) {
+	var advisories []GroupedAdvisory
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(advisoryGroupBucket))
+		if root == nil {
+			return nil
+		}
+		group := root.Bucket([]byte(id))
+		if group == nil {
+			return nil
+		}
+
+		return group.ForEach(func(ns, _ []byte) error {
+			nsBkt := group.Bucket(ns)
+			return nsBkt.ForEach(func(pkgName, _ []byte) error {
+				return nsBkt.Bucket(pkgName).ForEach(func(vulnID, _ []byte) error {
+					b := dbc.getTx(tx, []string{string(ns), string(pkgName)}, string(vulnID))
+					if b == nil {
+						// Before the DB is optimized
+						b = dbc.getTx(tx, []string{advisoryDetailBucket, string(vulnID), string(ns)}, string(pkgName))
+					}
+					if b == nil {
+						return nil
+					}
+
+					var adv types.Advisory
+					if err := json.Unmarshal(b, &adv); err != nil {
+						return xerrors.Errorf("JSON unmarshal error (%s): %w", vulnID, err)
+					}
+					adv.VulnerabilityID = string(vulnID)
+					advisories = append(advisories, GroupedAdvisory{
+						Advisory:  adv,
+						Namespace: string(ns),
+						PkgName:   string(pkgName),
+					})
+					return nil
+				})
+			})
+		})
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get the advisory group: %w", err)
+	}
+
+	sort.Slice(advisories, func(i, j int) bool {
+		switch {
+		case advisories[i].Namespace != advisories[j].Namespace:
+			return advisories[i].Namespace < advisories[j].Namespace
+		case advisories[i].PkgName != advisories[j].PkgName:
+			return advisories[i].PkgName < advisories[j].PkgName
+		}
+		return advisories[i].VulnerabilityID < advisories[j].VulnerabilityID
+	})
+	return advisories, nil
+}
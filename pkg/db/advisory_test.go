@@ -221,3 +221,441 @@ func TestConfig_GetAdvisories(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_GetAdvisoriesPaged(t *testing.T) {
+	tests := []struct {
+		name    string
+		offset  int
+		limit   int
+		wantIDs []string
+		wantErr string
+	}{
+		{
+			name:    "first page",
+			offset:  0,
+			limit:   2,
+			wantIDs: []string{"CVE-2013-7445", "CVE-2019-3016"},
+		},
+		{
+			name:    "second page",
+			offset:  2,
+			limit:   2,
+			wantIDs: []string{"CVE-2020-0543", "CVE-2021-3444"},
+		},
+		{
+			name:    "last page",
+			offset:  4,
+			limit:   2,
+			wantIDs: []string{"CVE-2022-0001"},
+		},
+		{
+			name:   "out of range",
+			offset: 5,
+			limit:  2,
+		},
+		{
+			name:    "no limit",
+			offset:  1,
+			wantIDs: []string{"CVE-2019-3016", "CVE-2020-0543", "CVE-2021-3444", "CVE-2022-0001"},
+		},
+		{
+			name:    "negative offset",
+			offset:  -1,
+			wantErr: "negative offset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/paged.yaml"})
+			defer db.Close()
+
+			got, total, err := db.Config{}.GetAdvisoriesPaged("debian 10", "linux", tt.offset, tt.limit)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 5, total)
+
+			var gotIDs []string
+			for _, adv := range got {
+				gotIDs = append(gotIDs, adv.VulnerabilityID)
+			}
+			assert.Equal(t, tt.wantIDs, gotIDs)
+		})
+	}
+}
+
+func TestConfig_GetFixableAdvisories(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		pkgName   string
+		want      []types.Advisory
+	}{
+		{
+			name:      "library advisories",
+			namespace: "npm::Node.js Ecosystem Security Working Group",
+			pkgName:   "bassmaster",
+			want: []types.Advisory{
+				{
+					VulnerabilityID:    "CVE-2014-7205",
+					PatchedVersions:    []string{">=1.5.2"},
+					VulnerableVersions: []string{"<=1.5.1"},
+				},
+			},
+		},
+		{
+			name:      "os package advisories",
+			namespace: "debian 10",
+			pkgName:   "openssl",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-3711",
+					FixedVersion:    "1.1.1d-0+deb10u7",
+				},
+				{
+					VulnerabilityID: "CVE-2021-3712",
+					FixedVersion:    "1.1.1d-0+deb10u7",
+				},
+			},
+		},
+		{
+			name:      "unknown package",
+			namespace: "debian 10",
+			pkgName:   "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/fixable.yaml"})
+			defer db.Close()
+
+			got, err := db.Config{}.GetFixableAdvisories(tt.namespace, tt.pkgName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_GetTaggedAdvisories(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want []types.Advisory
+	}{
+		{
+			name: "kev",
+			tag:  "kev",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-3449",
+					FixedVersion:    "1.1.1d-0+deb10u6",
+					Tags:            []string{"internet-facing-risk", "kev"},
+				},
+				{
+					VulnerabilityID: "CVE-2021-3711",
+					FixedVersion:    "1.1.1d-0+deb10u7",
+					Tags:            []string{"kev"},
+				},
+			},
+		},
+		{
+			name: "internet-facing-risk",
+			tag:  "internet-facing-risk",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-3449",
+					FixedVersion:    "1.1.1d-0+deb10u6",
+					Tags:            []string{"internet-facing-risk", "kev"},
+				},
+			},
+		},
+		{
+			name: "unknown tag",
+			tag:  "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/tags.yaml"})
+			defer db.Close()
+
+			got, err := db.Config{}.GetTaggedAdvisories("debian 10", "openssl", tt.tag)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_GetAdvisoriesBySeverity(t *testing.T) {
+	type result struct {
+		PkgName         string
+		VulnerabilityID string
+		Severity        types.Severity
+	}
+	tests := []struct {
+		name      string
+		namespace string
+		min       types.Severity
+		want      []result
+	}{
+		{
+			name:      "high and above",
+			namespace: "debian 11",
+			min:       types.SeverityHigh,
+			want: []result{
+				{PkgName: "curl", VulnerabilityID: "CVE-2021-22945", Severity: types.SeverityCritical},
+				{PkgName: "openssl", VulnerabilityID: "CVE-2021-3711", Severity: types.SeverityHigh},
+			},
+		},
+		{
+			name:      "critical",
+			namespace: "debian 11",
+			min:       types.SeverityCritical,
+			want: []result{
+				{PkgName: "curl", VulnerabilityID: "CVE-2021-22945", Severity: types.SeverityCritical},
+			},
+		},
+		{
+			name:      "low and above excludes unknown",
+			namespace: "debian 11",
+			min:       types.SeverityLow,
+			want: []result{
+				{PkgName: "curl", VulnerabilityID: "CVE-2021-22945", Severity: types.SeverityCritical},
+				{PkgName: "curl", VulnerabilityID: "CVE-2021-22946", Severity: types.SeverityLow},
+				{PkgName: "openssl", VulnerabilityID: "CVE-2021-3711", Severity: types.SeverityHigh},
+				{PkgName: "openssl", VulnerabilityID: "CVE-2021-3712", Severity: types.SeverityMedium},
+			},
+		},
+		{
+			name:      "unknown includes everything",
+			namespace: "debian 11",
+			min:       types.SeverityUnknown,
+			want: []result{
+				{PkgName: "curl", VulnerabilityID: "CVE-2021-22945", Severity: types.SeverityCritical},
+				{PkgName: "curl", VulnerabilityID: "CVE-2021-22946", Severity: types.SeverityLow},
+				{PkgName: "curl", VulnerabilityID: "CVE-2021-99999", Severity: types.SeverityUnknown},
+				{PkgName: "openssl", VulnerabilityID: "CVE-2021-3711", Severity: types.SeverityHigh},
+				{PkgName: "openssl", VulnerabilityID: "CVE-2021-3712", Severity: types.SeverityMedium},
+			},
+		},
+		{
+			name:      "unknown namespace",
+			namespace: "debian 12",
+			min:       types.SeverityUnknown,
+		},
+	}
+
+	_ = dbtest.InitDB(t, []string{"testdata/fixtures/severity.yaml"})
+	defer db.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbc := db.Config{}
+			got, err := dbc.GetAdvisoriesBySeverity(tt.namespace, tt.min)
+			require.NoError(t, err)
+
+			var results []result
+			for _, adv := range got {
+				results = append(results, result{
+					PkgName:         adv.PkgName,
+					VulnerabilityID: adv.VulnerabilityID,
+					Severity:        adv.Severity,
+				})
+			}
+			assert.Equal(t, tt.want, results)
+		})
+	}
+
+	t.Run("joined detail", func(t *testing.T) {
+		got, err := db.Config{}.GetAdvisoriesBySeverity("debian 11", types.SeverityCritical)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "7.74.0-1.3+deb11u1", got[0].FixedVersion)
+		assert.Equal(t, "CRITICAL", got[0].Vulnerability.Severity)
+		assert.Equal(t, "debian", string(got[0].DataSource.ID))
+	})
+}
+
+func TestConfig_FindByPackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures []string
+		dbc      db.Config
+		pkgName  string
+		want     map[string][]types.Advisory
+	}{
+		{
+			name:     "multiple ecosystems",
+			fixtures: []string{"testdata/fixtures/find-by-package.yaml"},
+			pkgName:  "requests",
+			want: map[string][]types.Advisory{
+				"pip::GitHub Security Advisory pip": {
+					{
+						VulnerabilityID:    "CVE-2018-18074",
+						PatchedVersions:    []string{"2.20.0"},
+						VulnerableVersions: []string{"<2.20.0"},
+					},
+				},
+				"rubygems::GitHub Security Advisory RubyGems": {
+					{
+						VulnerabilityID: "CVE-2020-0001",
+						PatchedVersions: []string{">= 1.0.1"},
+					},
+				},
+			},
+		},
+		{
+			name:     "single ecosystem",
+			fixtures: []string{"testdata/fixtures/find-by-package.yaml"},
+			pkgName:  "curl",
+			want: map[string][]types.Advisory{
+				"debian 10": {
+					{
+						VulnerabilityID: "CVE-2021-22876",
+						FixedVersion:    "7.64.0-4+deb10u2",
+					},
+				},
+			},
+		},
+		{
+			name:     "disputed vulnerability is dropped",
+			fixtures: []string{"testdata/fixtures/disputed.yaml"},
+			pkgName:  "curl",
+			want: map[string][]types.Advisory{
+				"debian 10": {
+					{
+						VulnerabilityID: "CVE-2021-22876",
+						FixedVersion:    "7.64.0-4+deb10u2",
+					},
+				},
+			},
+		},
+		{
+			name:     "disputed vulnerability is included",
+			fixtures: []string{"testdata/fixtures/disputed.yaml"},
+			dbc:      db.Config{IncludeDisputed: true},
+			pkgName:  "curl",
+			want: map[string][]types.Advisory{
+				"debian 10": {
+					{
+						VulnerabilityID: "CVE-2020-19909",
+						State:           "will_not_fix",
+					},
+					{
+						VulnerabilityID: "CVE-2021-22876",
+						FixedVersion:    "7.64.0-4+deb10u2",
+					},
+				},
+			},
+		},
+		{
+			name:     "unknown package",
+			fixtures: []string{"testdata/fixtures/find-by-package.yaml"},
+			pkgName:  "unknown",
+			want:     map[string][]types.Advisory{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, tt.fixtures)
+			defer db.Close()
+
+			got, err := tt.dbc.FindByPackage(tt.pkgName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_GetAllOSAdvisories(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgName string
+		want    map[string][]types.Advisory
+	}{
+		{
+			name:    "multiple distros",
+			pkgName: "openssl",
+			want: map[string][]types.Advisory{
+				"debian 10": {
+					{
+						VulnerabilityID: "CVE-2021-3449",
+						FixedVersion:    "1.1.1d-0+deb10u6",
+					},
+					{
+						VulnerabilityID: "CVE-2021-3711",
+						FixedVersion:    "1.1.1d-0+deb10u7",
+					},
+				},
+				"alpine 3.15": {
+					{
+						VulnerabilityID: "CVE-2021-3711",
+						FixedVersion:    "1.1.1l-r0",
+					},
+				},
+			},
+		},
+		{
+			name:    "unknown package",
+			pkgName: "unknown",
+			want:    map[string][]types.Advisory{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/os-advisories.yaml"})
+			defer db.Close()
+
+			dbc := db.Config{}
+			got, err := dbc.GetAllOSAdvisories(tt.pkgName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_GetAdvisoriesDisputed(t *testing.T) {
+	tests := []struct {
+		name string
+		dbc  db.Config
+		want []types.Advisory
+	}{
+		{
+			name: "disputed excluded by default",
+			dbc:  db.Config{},
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-22876",
+					FixedVersion:    "7.64.0-4+deb10u2",
+				},
+			},
+		},
+		{
+			name: "disputed included",
+			dbc:  db.Config{IncludeDisputed: true},
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2020-19909",
+					State:           "will_not_fix",
+				},
+				{
+					VulnerabilityID: "CVE-2021-22876",
+					FixedVersion:    "7.64.0-4+deb10u2",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/disputed.yaml"})
+			defer db.Close()
+
+			got, err := tt.dbc.GetAdvisories("debian 10", "curl")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
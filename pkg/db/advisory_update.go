@@ -0,0 +1,71 @@
+package db
+
+import (
+	"encoding/json"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// UpdateAdvisory replaces the stored advisory of the package in a short transaction, e.g. to hotfix a bad version range
+// without rebuilding the DB. The advisory must already exist. The affected-package index and the hash of the namespace
+// are kept in sync if the DB has them, so that VerifyBucketHashes doesn't report the hotfixed namespace.
+func (dbc Config) UpdateAdvisory(namespace, pkgName, vulnID string, adv types.Advisory) error {
+	vulnID = normalizeVulnID(vulnID)
+
+	// They are filled from the keys and the data-source bucket on reads
+	adv.VulnerabilityID = ""
+	adv.DataSource = nil
+
+	err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+		bktNames := []string{namespace, pkgName}
+		if dbc.getTx(tx, bktNames, vulnID) == nil {
+			return xerrors.Errorf("no advisory for %s in %s", vulnID, namespace)
+		}
+		if err := dbc.PutAdvisory(tx, bktNames, vulnID, canonicalize(adv)); err != nil {
+			return err
+		}
+		if err := dbc.putAffectedPackage(tx, vulnID, AffectedPackage{Namespace: namespace, PkgName: pkgName}); err != nil {
+			return xerrors.Errorf("affected package index error: %w", err)
+		}
+		if err := updateBucketHash(tx, namespace); err != nil {
+			return xerrors.Errorf("bucket hash error: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to update the advisory of %s: %w", pkgName, err)
+	}
+	return nil
+}
+
+// putAffectedPackage adds the package to the affected-package index of the vulnerability if the index exists
+func (dbc Config) putAffectedPackage(tx *bolt.Tx, vulnID string, pkg AffectedPackage) error {
+	if tx.Bucket([]byte(affectedPackageBucket)) == nil {
+		return nil
+	}
+
+	var pkgs []AffectedPackage
+	if b := dbc.getTx(tx, []string{affectedPackageBucket}, vulnID); b != nil {
+		if err := json.Unmarshal(b, &pkgs); err != nil {
+			return xerrors.Errorf("JSON unmarshal error: %w", err)
+		}
+	}
+	for _, p := range pkgs {
+		if p == pkg {
+			return nil
+		}
+	}
+
+	pkgs = append(pkgs, pkg)
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Namespace != pkgs[j].Namespace {
+			return pkgs[i].Namespace < pkgs[j].Namespace
+		}
+		return pkgs[i].PkgName < pkgs[j].PkgName
+	})
+	return dbc.put(tx, []string{affectedPackageBucket}, vulnID, pkgs)
+}
@@ -0,0 +1,91 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_UpdateAdvisory(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		pkgName   string
+		vulnID    string
+		want      map[string][]types.Advisory
+		wantErr   string
+	}{
+		{
+			name:      "hotfix",
+			namespace: "debian 10",
+			pkgName:   "openssl",
+			vulnID:    "CVE-2021-3711",
+			want: map[string][]types.Advisory{
+				"debian 10": {
+					{
+						VulnerabilityID: "CVE-2021-3449",
+						FixedVersion:    "1.1.1d-0+deb10u6",
+					},
+					{
+						VulnerabilityID: "CVE-2021-3711",
+						FixedVersion:    "1.1.1d-0+deb10u8",
+					},
+				},
+				"alpine 3.15": {
+					{
+						VulnerabilityID: "CVE-2021-3711",
+						FixedVersion:    "1.1.1l-r0",
+					},
+				},
+			},
+		},
+		{
+			name:      "no such advisory",
+			namespace: "alpine 3.15",
+			pkgName:   "openssl",
+			vulnID:    "CVE-2021-3449",
+			wantErr:   "no advisory for CVE-2021-3449 in alpine 3.15",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/os-advisories.yaml"})
+			defer db.Close()
+
+			dbc := db.Config{}
+			require.NoError(t, dbc.RebuildIndexes())
+			require.NoError(t, dbc.PutBucketHashes())
+
+			err := dbc.UpdateAdvisory(tt.namespace, tt.pkgName, tt.vulnID, types.Advisory{
+				FixedVersion: "1.1.1d-0+deb10u8",
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := dbc.GetAllOSAdvisories(tt.pkgName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// The hotfixed namespace still matches its hash
+			tampered, err := dbc.VerifyBucketHashes()
+			require.NoError(t, err)
+			assert.Empty(t, tampered)
+
+			pkgs, err := dbc.GetAffectedPackages(tt.vulnID)
+			require.NoError(t, err)
+			assert.Equal(t, []db.AffectedPackage{
+				{Namespace: "alpine 3.15", PkgName: "openssl"},
+				{Namespace: "debian 10", PkgName: "openssl"},
+			}, pkgs)
+		})
+	}
+}
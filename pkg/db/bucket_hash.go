@@ -0,0 +1,163 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+const (
+	bucketHashBucket = "bucket-hash"
+)
+
+// PutBucketHashes stores the SHA-256 hash of the content of each namespace in the bucket-hash bucket,
+// so that VerifyBucketHashes can detect namespaces modified after the build.
+// Existing hashes are replaced.
+func (dbc Config) PutBucketHashes() error {
+	err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+		if err := deleteBucketIfExists(tx, bucketHashBucket); err != nil {
+			return err
+		}
+
+		hashes, err := namespaceHashes(tx)
+		if err != nil {
+			return err
+		}
+
+		bkt, err := tx.CreateBucket([]byte(bucketHashBucket))
+		if err != nil {
+			return xerrors.Errorf("failed to create a bucket: %w", err)
+		}
+		for ns, h := range hashes {
+			if err = bkt.Put([]byte(ns), []byte(h)); err != nil {
+				return xerrors.Errorf("failed to put the hash of %s: %w", ns, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to put bucket hashes: %w", err)
+	}
+	return nil
+}
+
+// VerifyBucketHashes returns the sorted namespaces whose content doesn't match the hash stored by PutBucketHashes.
+// Namespaces added or removed after the build are returned as well.
+// It returns an error if the DB has no hashes.
+func (dbc Config) VerifyBucketHashes() ([]string, error) {
+	var tampered []string
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(bucketHashBucket))
+		if bkt == nil {
+			return xerrors.New("no bucket hashes")
+		}
+
+		hashes, err := namespaceHashes(tx)
+		if err != nil {
+			return err
+		}
+
+		err = bkt.ForEach(func(ns, want []byte) error {
+			if got, ok := hashes[string(ns)]; !ok || got != string(want) {
+				tampered = append(tampered, string(ns))
+			}
+			delete(hashes, string(ns))
+			return nil
+		})
+		if err != nil {
+			return xerrors.Errorf("bucket hash error: %w", err)
+		}
+
+		// Namespaces without a hash
+		for ns := range hashes {
+			tampered = append(tampered, ns)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to verify bucket hashes: %w", err)
+	}
+
+	sort.Strings(tampered)
+	return tampered, nil
+}
+
+// namespaceHashes returns the hex-encoded hashes keyed by the namespace
+func namespaceHashes(tx *bolt.Tx) (map[string]string, error) {
+	hashes := map[string]string{}
+	err := tx.ForEach(func(ns []byte, nsBkt *bolt.Bucket) error {
+		if _, ok := internalBuckets[string(ns)]; ok {
+			return nil
+		}
+		h, err := namespaceHash(nsBkt)
+		if err != nil {
+			return xerrors.Errorf("hash error in %s: %w", ns, err)
+		}
+		hashes[string(ns)] = h
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("namespace walk error: %w", err)
+	}
+	return hashes, nil
+}
+
+// namespaceHash returns the hex-encoded hash of the namespace
+func namespaceHash(nsBkt *bolt.Bucket) (string, error) {
+	h := sha256.New()
+	if err := hashBucket(h, nsBkt); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// updateBucketHash replaces the stored hash of the namespace modified after the build.
+// It is a no-op if the DB has no hash of the namespace.
+func updateBucketHash(tx *bolt.Tx, namespace string) error {
+	bkt := tx.Bucket([]byte(bucketHashBucket))
+	if bkt == nil || bkt.Get([]byte(namespace)) == nil {
+		return nil
+	}
+	nsBkt := tx.Bucket([]byte(namespace))
+	if nsBkt == nil {
+		return xerrors.Errorf("no such bucket: %s", namespace)
+	}
+	h, err := namespaceHash(nsBkt)
+	if err != nil {
+		return xerrors.Errorf("hash error in %s: %w", namespace, err)
+	}
+	return bkt.Put([]byte(namespace), []byte(h))
+}
+
+// hashBucket writes the keys and values of the bucket into the hash recursively.
+// Keys are iterated in byte order by bolt, and every field is length-prefixed
+// so that different contents never produce the same input.
+func hashBucket(h hash.Hash, bkt *bolt.Bucket) error {
+	return bkt.ForEach(func(k, v []byte) error {
+		writeField(h, k)
+		// Nested bucket
+		if v == nil {
+			h.Write([]byte{1})
+			if err := hashBucket(h, bkt.Bucket(k)); err != nil {
+				return err
+			}
+			h.Write([]byte{2})
+			return nil
+		}
+		h.Write([]byte{0})
+		writeField(h, v)
+		return nil
+	})
+}
+
+func writeField(h hash.Hash, b []byte) {
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(len(b)))
+	h.Write(size[:])
+	h.Write(b)
+}
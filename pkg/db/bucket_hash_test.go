@@ -0,0 +1,77 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+)
+
+func TestConfig_VerifyBucketHashes(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(tx *bolt.Tx) error
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "intact",
+			tamper: func(tx *bolt.Tx) error { return nil },
+		},
+		{
+			name: "modified advisory",
+			tamper: func(tx *bolt.Tx) error {
+				bkt := tx.Bucket([]byte("composer::php-security-advisories")).Bucket([]byte("symfony/symfony"))
+				return bkt.Put([]byte("CVE-2020-5275"), []byte(`{"VulnerableVersions":[">= 4.4.0, < 4.4.5"]}`))
+			},
+			want: []string{"composer::php-security-advisories"},
+		},
+		{
+			name: "removed package",
+			tamper: func(tx *bolt.Tx) error {
+				return tx.Bucket([]byte("composer::GitHub Security Advisory Composer")).DeleteBucket([]byte("symfony/symfony"))
+			},
+			want: []string{"composer::GitHub Security Advisory Composer"},
+		},
+		{
+			name: "added and removed namespaces",
+			tamper: func(tx *bolt.Tx) error {
+				if _, err := tx.CreateBucket([]byte("npm::evil")); err != nil {
+					return err
+				}
+				return tx.DeleteBucket([]byte("composer::php-security-advisories"))
+			},
+			want: []string{"composer::php-security-advisories", "npm::evil"},
+		},
+		{
+			name: "removed hashes",
+			tamper: func(tx *bolt.Tx) error {
+				return tx.DeleteBucket([]byte("bucket-hash"))
+			},
+			wantErr: "no bucket hashes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/multiple-buckets.yaml"})
+			defer db.Close()
+
+			dbc := db.Config{}
+			require.NoError(t, dbc.PutBucketHashes())
+			require.NoError(t, dbc.BatchUpdate(tt.tamper))
+
+			got, err := dbc.VerifyBucketHashes()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// cacheInvalidation counts the invalidations committed, so that values read before a commit
+// are not put back into the cache after the commit invalidated them.
+var cacheInvalidation struct {
+	sync.Mutex
+	generation uint64
+}
+
+// Cache holds the values read from the DB so that services scanning at high QPS don't hit BoltDB every time.
+// It may be backed by an external store such as Redis or memcached. Implementations must be safe for concurrent use.
+// Failures of the store should be reported as misses, since the DB is always consulted then.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Delete(key string)
+}
+
+// InProcessCache is the default Cache kept in the memory of the process.
+// The least recently used entry is evicted when the number of entries exceeds the capacity.
+type InProcessCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // the front is the most recently used
+	entries  map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key   string
+	value []byte
+}
+
+// NewInProcessCache returns the in-process cache holding at most capacity entries. Zero or less means no limit.
+func NewInProcessCache(capacity int) *InProcessCache {
+	return &InProcessCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (c *InProcessCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).value, true
+}
+
+func (c *InProcessCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*cacheEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value})
+
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *InProcessCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// cacheKey returns the key of the value read by the API, e.g. `["advisories","alpine 3.14","openssl"]`.
+// JSON keeps the key unambiguous even if namespaces or package names contain separators.
+func cacheKey(api string, args ...string) string {
+	b, _ := json.Marshal(append([]string{api}, args...))
+	return string(b)
+}
+
+// cacheGet decodes the cached value into v. It returns false on a miss or if the cached value is broken.
+func (dbc Config) cacheGet(key string, v interface{}) bool {
+	if dbc.Cache == nil {
+		return false
+	}
+	b, ok := dbc.Cache.Get(key)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(b, v) == nil
+}
+
+// cacheGeneration returns the generation to be passed to cacheSet. It must be taken before reading the DB.
+func (dbc Config) cacheGeneration() uint64 {
+	cacheInvalidation.Lock()
+	defer cacheInvalidation.Unlock()
+	return cacheInvalidation.generation
+}
+
+// cacheSet caches the value read from the DB unless an invalidation has been committed since the generation was taken
+func (dbc Config) cacheSet(key string, v interface{}, generation uint64) {
+	if dbc.Cache == nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	cacheInvalidation.Lock()
+	defer cacheInvalidation.Unlock()
+	if cacheInvalidation.generation != generation {
+		return
+	}
+	dbc.Cache.Set(key, b)
+}
+
+// cacheInvalidate deletes the entry once the transaction is committed. Deleting it before the commit would let
+// concurrent readers cache the old value again.
+func (dbc Config) cacheInvalidate(tx *bolt.Tx, key string) {
+	if dbc.Cache == nil {
+		return
+	}
+	tx.OnCommit(func() {
+		cacheInvalidation.Lock()
+		defer cacheInvalidation.Unlock()
+		cacheInvalidation.generation++
+		dbc.Cache.Delete(key)
+	})
+}
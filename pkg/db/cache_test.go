@@ -0,0 +1,198 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// stubCache records the calls, e.g. standing in for Redis
+type stubCache struct {
+	values map[string][]byte
+	hits   int
+	sets   int
+}
+
+func (c *stubCache) Get(key string) ([]byte, bool) {
+	v, ok := c.values[key]
+	if ok {
+		c.hits++
+	}
+	return v, ok
+}
+
+func (c *stubCache) Set(key string, value []byte) {
+	c.sets++
+	c.values[key] = value
+}
+
+func (c *stubCache) Delete(key string) {
+	delete(c.values, key)
+}
+
+func TestConfig_Cache(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	cache := &stubCache{values: map[string][]byte{}}
+	dbc := db.Config{Cache: cache}
+
+	// Written without the cache so that only the reads touch it
+	uncached := db.Config{}
+	err := uncached.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := uncached.PutVulnerability(tx, "CVE-2021-0001", types.Vulnerability{Title: "original"}); err != nil {
+			return err
+		}
+		return uncached.PutAdvisory(tx, []string{"alpine 3.14", "openssl"}, "CVE-2021-0001",
+			types.Advisory{FixedVersion: "1.1.1k-r0"})
+	})
+	require.NoError(t, err)
+
+	t.Run("GetVulnerability", func(t *testing.T) {
+		// The first read populates the cache
+		got, err := dbc.GetVulnerability("CVE-2021-0001")
+		require.NoError(t, err)
+		assert.Equal(t, "original", got.Title)
+		assert.Equal(t, 1, cache.sets)
+		assert.Equal(t, 0, cache.hits)
+
+		// The second read hits the cache, not seeing the change in BoltDB
+		err = uncached.BatchUpdate(func(tx *bolt.Tx) error {
+			return tx.Bucket([]byte("vulnerability")).Put([]byte("CVE-2021-0001"), []byte(`{"Title":"changed"}`))
+		})
+		require.NoError(t, err)
+
+		got, err = dbc.GetVulnerability("CVE-2021-0001")
+		require.NoError(t, err)
+		assert.Equal(t, "original", got.Title)
+		assert.Equal(t, 1, cache.sets)
+		assert.Equal(t, 1, cache.hits)
+
+		// Writes through the config invalidate the entry
+		err = dbc.BatchUpdate(func(tx *bolt.Tx) error {
+			return dbc.PutVulnerability(tx, "CVE-2021-0001", types.Vulnerability{Title: "updated"})
+		})
+		require.NoError(t, err)
+
+		got, err = dbc.GetVulnerability("CVE-2021-0001")
+		require.NoError(t, err)
+		assert.Equal(t, "updated", got.Title)
+	})
+
+	t.Run("GetAdvisories", func(t *testing.T) {
+		cache.hits, cache.sets = 0, 0
+		want := []types.Advisory{{VulnerabilityID: "CVE-2021-0001", FixedVersion: "1.1.1k-r0"}}
+		for i := 0; i < 2; i++ {
+			got, err := dbc.GetAdvisories("alpine 3.14", "openssl")
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+		assert.Equal(t, 1, cache.sets)
+		assert.Equal(t, 1, cache.hits)
+	})
+}
+
+func TestConfig_CacheInvalidateOnCommit(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	cache := &stubCache{values: map[string][]byte{}}
+	dbc := db.Config{Cache: cache}
+
+	bktNames := []string{"alpine 3.14", "openssl"}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.PutAdvisory(tx, bktNames, "CVE-2021-0001", types.Advisory{FixedVersion: "1.1.1k-r0"})
+	})
+	require.NoError(t, err)
+
+	// A concurrent read between the write and the commit sees the old advisory
+	err = dbc.Connection().Update(func(tx *bolt.Tx) error {
+		if err := dbc.PutAdvisory(tx, bktNames, "CVE-2021-0001", types.Advisory{FixedVersion: "1.1.1l-r0"}); err != nil {
+			return err
+		}
+		done := make(chan error)
+		go func() {
+			got, err := dbc.GetAdvisories("alpine 3.14", "openssl")
+			if err == nil {
+				assert.Equal(t, "1.1.1k-r0", got[0].FixedVersion)
+			}
+			done <- err
+		}()
+		return <-done
+	})
+	require.NoError(t, err)
+
+	// The old advisory cached by the concurrent read is invalidated on commit
+	got, err := dbc.GetAdvisories("alpine 3.14", "openssl")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "1.1.1l-r0", got[0].FixedVersion)
+}
+
+func TestConfig_CacheAliases(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	cache := &stubCache{values: map[string][]byte{}}
+	dbc := db.Config{Cache: cache}
+
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := dbc.PutNamespaceAlias(tx, "oracle linux 8.6", "oracle linux 8"); err != nil {
+			return err
+		}
+		if err := dbc.PutAdvisory(tx, []string{"oracle linux 8", "curl"}, "CVE-2021-0001",
+			types.Advisory{FixedVersion: "7.61.1-18"}); err != nil {
+			return err
+		}
+		return dbc.PutAdvisory(tx, []string{"pip::GitHub Security Advisory pip", "requests"}, "CVE-2021-0002",
+			types.Advisory{PatchedVersions: []string{"2.20.0"}})
+	})
+	require.NoError(t, err)
+
+	// Neither the fallback to the namespace nor the namespace prefix is cached,
+	// since writes to "oracle linux 8" and "pip::GitHub Security Advisory pip" don't invalidate them
+	for _, q := range [][2]string{{"oracle linux 8.6", "curl"}, {"pip::", "requests"}} {
+		got, err := dbc.GetAdvisories(q[0], q[1])
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+	}
+	assert.Equal(t, 0, cache.sets)
+
+	err = dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.PutAdvisory(tx, []string{"oracle linux 8", "curl"}, "CVE-2021-0001",
+			types.Advisory{FixedVersion: "7.61.1-22"})
+	})
+	require.NoError(t, err)
+
+	got, err := dbc.GetAdvisories("oracle linux 8.6", "curl")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "7.61.1-22", got[0].FixedVersion)
+}
+
+func TestInProcessCache(t *testing.T) {
+	c := db.NewInProcessCache(2)
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+
+	// "a" becomes the most recently used, so "b" is evicted
+	_, ok := c.Get("a")
+	require.True(t, ok)
+	c.Set("c", []byte("3"))
+
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	got, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), got)
+
+	c.Delete("a")
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+}
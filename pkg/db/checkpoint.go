@@ -0,0 +1,51 @@
+package db
+
+import (
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+const (
+	// buildCheckpointBucket holds the time each data source was completely written in the current build
+	buildCheckpointBucket = "build-checkpoint"
+)
+
+// PutCheckpoint records that the data source has been completely written,
+// so that a build restarted after a failure can skip it.
+func (dbc Config) PutCheckpoint(source types.SourceID) error {
+	err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+		return dbc.put(tx, []string{buildCheckpointBucket}, string(source), time.Now().UTC())
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to put the checkpoint of %s: %w", source, err)
+	}
+	return nil
+}
+
+// Checkpointed returns true if PutCheckpoint has been called for the data source since the last DeleteCheckpoints
+func (dbc Config) Checkpointed(source types.SourceID) (bool, error) {
+	var found bool
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		found = dbc.getTx(tx, []string{buildCheckpointBucket}, string(source)) != nil
+		return nil
+	})
+	if err != nil {
+		return false, xerrors.Errorf("failed to get the checkpoint of %s: %w", source, err)
+	}
+	return found, nil
+}
+
+// DeleteCheckpoints deletes all the checkpoints once the build completes, so that the next build updates every source
+func (dbc Config) DeleteCheckpoints() error {
+	err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+		return deleteBucketIfExists(tx, buildCheckpointBucket)
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to delete checkpoints: %w", err)
+	}
+	return nil
+}
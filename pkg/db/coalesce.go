@@ -0,0 +1,115 @@
+package db
+
+import (
+	"encoding/json"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
+)
+
+const (
+	// advisoryFragmentBucket holds the vulnerable versions of advisories stored more than once
+	// for the same vulnerability, namespace and package until CoalesceAdvisoryRanges consumes them.
+	advisoryFragmentBucket = "advisory-fragment"
+)
+
+// putRangeFragment records the vulnerable versions of the stored and the new advisory
+// so that CoalesceAdvisoryRanges can union them whatever AdvisoryMergeStrategy keeps in the meantime.
+func putRangeFragment(s store, bktNames []string, pkgName string, existing, b []byte) error {
+	fragmentBkts := append([]string{advisoryFragmentBucket}, bktNames[1:]...)
+
+	var versions []string
+	if fragment := s.value(fragmentBkts, pkgName); fragment != nil {
+		if err := json.Unmarshal(fragment, &versions); err != nil {
+			return xerrors.Errorf("JSON unmarshal error: %w", err)
+		}
+	} else {
+		vs, err := vulnerableVersions(existing)
+		if err != nil {
+			return err
+		}
+		versions = vs
+	}
+
+	vs, err := vulnerableVersions(b)
+	if err != nil {
+		return err
+	}
+	versions = ustrings.Unique(append(versions, vs...))
+
+	if err = putJSON(s, fragmentBkts, pkgName, versions); err != nil {
+		return xerrors.Errorf("failed to put range fragment: %w", err)
+	}
+	return nil
+}
+
+// vulnerableVersions returns VulnerableVersions of the advisory in JSON
+func vulnerableVersions(advisory []byte) ([]string, error) {
+	var adv struct {
+		VulnerableVersions []string
+	}
+	if err := json.Unmarshal(advisory, &adv); err != nil {
+		return nil, xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+	return adv.VulnerableVersions, nil
+}
+
+// CoalesceAdvisoryRanges is a post-ingest pass replacing VulnerableVersions of each advisory stored more than once
+// for the same vulnerability, namespace and package with the union of the fragments, e.g. when a source splits
+// the affected versions of a CVE across records. The other fields are kept as is.
+// It must run before the advisory-detail bucket is deleted and is a no-op unless CoalesceRanges is set while ingesting.
+func (dbc Config) CoalesceAdvisoryRanges() error {
+	err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(advisoryFragmentBucket))
+		if root == nil {
+			return nil
+		}
+		if err := dbc.coalesceFragments(tx, root, []string{advisoryDetailBucket}); err != nil {
+			return err
+		}
+		return deleteBucketIfExists(tx, advisoryFragmentBucket)
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to coalesce advisory ranges: %w", err)
+	}
+	return nil
+}
+
+// coalesceFragments walks the fragment bucket recursively and rewrites the advisories at the same path
+func (dbc Config) coalesceFragments(tx *bolt.Tx, bkt *bolt.Bucket, bktNames []string) error {
+	return bkt.ForEach(func(k, v []byte) error {
+		if v == nil {
+			bkts := append(append([]string{}, bktNames...), string(k))
+			return dbc.coalesceFragments(tx, bkt.Bucket(k), bkts)
+		}
+
+		existing := dbc.getTx(tx, bktNames, string(k))
+		if existing == nil {
+			return nil
+		}
+
+		var versions []string
+		if err := json.Unmarshal(v, &versions); err != nil {
+			return xerrors.Errorf("JSON unmarshal error (%s): %w", k, err)
+		} else if len(versions) == 0 {
+			return nil
+		}
+
+		adv := map[string]interface{}{}
+		if err := json.Unmarshal(existing, &adv); err != nil {
+			return xerrors.Errorf("JSON unmarshal error (%s): %w", k, err)
+		}
+		adv["VulnerableVersions"] = sortConstraints(versions)
+
+		b, err := json.Marshal(adv)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal JSON: %w", err)
+		}
+		if err = dbc.putBytes(tx, bktNames, string(k), b); err != nil {
+			return xerrors.Errorf("failed to put advisory detail: %w", err)
+		}
+		return nil
+	})
+}
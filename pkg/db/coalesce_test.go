@@ -0,0 +1,76 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_CoalesceAdvisoryRanges(t *testing.T) {
+	// A source splitting the affected versions of the CVE across two records
+	fragments := []types.Advisory{
+		{
+			VulnerableVersions: []string{">=2.0.0 <2.1.0"},
+			PatchedVersions:    []string{">=2.1.0"},
+		},
+		{
+			VulnerableVersions: []string{">=1.0.0 <1.2.6"},
+			PatchedVersions:    []string{">=1.2.6"},
+		},
+	}
+	tests := []struct {
+		name string
+		dbc  db.Config
+		want types.Advisory
+	}{
+		{
+			name: "coalesce",
+			dbc:  db.Config{CoalesceRanges: true},
+			want: types.Advisory{
+				VulnerableVersions: []string{">=1.0.0 <1.2.6", ">=2.0.0 <2.1.0"},
+				PatchedVersions:    []string{">=1.2.6"},
+			},
+		},
+		{
+			name: "coalesce with keep-first",
+			dbc:  db.Config{CoalesceRanges: true, AdvisoryMergeStrategy: db.MergeKeepFirst},
+			want: types.Advisory{
+				VulnerableVersions: []string{">=1.0.0 <1.2.6", ">=2.0.0 <2.1.0"},
+				PatchedVersions:    []string{">=2.1.0"},
+			},
+		},
+		{
+			name: "disabled",
+			dbc:  db.Config{},
+			want: fragments[1],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			bktNames := []string{"npm::Node.js Ecosystem Security Working Group"}
+			err := tt.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				for _, adv := range fragments {
+					if err := tt.dbc.PutAdvisoryDetail(tx, "CVE-2020-7598", "minimist", bktNames, adv); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			require.NoError(t, tt.dbc.CoalesceAdvisoryRanges())
+
+			require.NoError(t, db.Close())
+			key := append([]string{"advisory-detail", "CVE-2020-7598"}, append(bktNames, "minimist")...)
+			dbtest.JSONEq(t, db.Path(tmpDir), key, tt.want)
+			dbtest.NoBucket(t, db.Path(tmpDir), []string{"advisory-fragment"})
+		})
+	}
+}
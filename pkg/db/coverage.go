@@ -0,0 +1,98 @@
+package db
+
+import (
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+// CoverageStats is the number of advisories and distinct packages in a namespace
+type CoverageStats struct {
+	Advisories int
+	Packages   int
+}
+
+// coverage collects the vulnerability IDs per package of a namespace
+type coverage map[string]map[string]struct{}
+
+func (c coverage) add(pkgName, vulnID string) {
+	if c[pkgName] == nil {
+		c[pkgName] = map[string]struct{}{}
+	}
+	c[pkgName][vulnID] = struct{}{}
+}
+
+// Coverage returns the number of advisories and distinct packages keyed by the namespace,
+// e.g. "npm::Node.js Ecosystem Security Working Group" and "debian 10".
+// Advisories still in the advisory-detail bucket are counted as well, so that it works before the DB is optimized.
+// Packages stored under aliases are counted separately.
+func (dbc Config) Coverage() (map[string]CoverageStats, error) {
+	namespaces := map[string]coverage{}
+	nsCoverage := func(ns string) coverage {
+		if namespaces[ns] == nil {
+			namespaces[ns] = coverage{}
+		}
+		return namespaces[ns]
+	}
+
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		// namespace => package name => vulnerability ID
+		err := tx.ForEach(func(ns []byte, nsBkt *bolt.Bucket) error {
+			if _, ok := internalBuckets[string(ns)]; ok {
+				return nil
+			}
+			return nsBkt.ForEach(func(pkgName, v []byte) error {
+				if v != nil {
+					return nil
+				}
+				return nsBkt.Bucket(pkgName).ForEach(func(vulnID, _ []byte) error {
+					nsCoverage(string(ns)).add(string(pkgName), string(vulnID))
+					return nil
+				})
+			})
+		})
+		if err != nil {
+			return xerrors.Errorf("namespace walk error: %w", err)
+		}
+
+		// advisory-detail => vulnerability ID => namespace => package name
+		bkt := tx.Bucket([]byte(advisoryDetailBucket))
+		if bkt == nil {
+			return nil
+		}
+		err = bkt.ForEach(func(vulnID, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			return bkt.Bucket(vulnID).ForEach(func(ns, v []byte) error {
+				if v != nil {
+					return nil
+				}
+				return bkt.Bucket(vulnID).Bucket(ns).ForEach(func(pkgName, v []byte) error {
+					// Nested buckets such as Red Hat modules are not packages
+					if v != nil {
+						nsCoverage(string(ns)).add(string(pkgName), string(vulnID))
+					}
+					return nil
+				})
+			})
+		})
+		if err != nil {
+			return xerrors.Errorf("advisory detail error: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to compute the coverage: %w", err)
+	}
+
+	stats := map[string]CoverageStats{}
+	for ns, c := range namespaces {
+		var s CoverageStats
+		for _, vulnIDs := range c {
+			s.Packages++
+			s.Advisories += len(vulnIDs)
+		}
+		stats[ns] = s
+	}
+	return stats, nil
+}
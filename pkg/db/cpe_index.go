@@ -0,0 +1,49 @@
+package db
+
+import (
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+const (
+	cpeIndexBucket = "cpe-index"
+)
+
+// putCPEIndex adds the CPEs to the index, i.e. "cpe-index" => CPE => vulnerability ID
+func (dbc Config) putCPEIndex(tx *bolt.Tx, vulnID string, cpes []string) error {
+	for _, cpe := range cpes {
+		if err := dbc.putBytes(tx, []string{cpeIndexBucket, cpe}, vulnID, []byte{}); err != nil {
+			return xerrors.Errorf("failed to put CPE index: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetByCPE returns the sorted IDs of vulnerabilities affecting the CPE,
+// e.g. "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*". The CPE must match the one given by the source exactly.
+// It requires the index built with BuildCPEIndex.
+func (dbc Config) GetByCPE(cpe string) ([]string, error) {
+	var vulnIDs []string
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(cpeIndexBucket))
+		if root == nil {
+			return nil
+		}
+		bkt := root.Bucket([]byte(cpe))
+		if bkt == nil {
+			return nil
+		}
+		return bkt.ForEach(func(vulnID, _ []byte) error {
+			vulnIDs = append(vulnIDs, string(vulnID))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get vulnerabilities by CPE: %w", err)
+	}
+
+	sort.Strings(vulnIDs)
+	return vulnIDs, nil
+}
@@ -0,0 +1,77 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_GetByCPE(t *testing.T) {
+	details := map[string]types.VulnerabilityDetail{
+		"CVE-2021-44228": {
+			CPEs: []string{
+				"cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*",
+				"cpe:2.3:a:siemens:sppa-t3000_ses3000_firmware:*:*:*:*:*:*:*:*",
+			},
+		},
+		"CVE-2021-45046": {
+			CPEs: []string{"cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		buildCPEIndex bool
+		cpe           string
+		want          []string
+	}{
+		{
+			name:          "shared CPE",
+			buildCPEIndex: true,
+			cpe:           "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*",
+			want:          []string{"CVE-2021-44228", "CVE-2021-45046"},
+		},
+		{
+			name:          "second CPE",
+			buildCPEIndex: true,
+			cpe:           "cpe:2.3:a:siemens:sppa-t3000_ses3000_firmware:*:*:*:*:*:*:*:*",
+			want:          []string{"CVE-2021-44228"},
+		},
+		{
+			name:          "no match",
+			buildCPEIndex: true,
+			cpe:           "cpe:2.3:a:apache:struts:*:*:*:*:*:*:*:*",
+		},
+		{
+			name: "no index",
+			cpe:  "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{BuildCPEIndex: tt.buildCPEIndex}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				for vulnID, detail := range details {
+					if err := dbc.PutVulnerabilityDetail(tx, vulnID, "nvd", detail); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			got, err := dbc.GetByCPE(tt.cpe)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,11 +2,15 @@ package db
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
 	"golang.org/x/xerrors"
@@ -28,12 +32,34 @@ type Operation interface {
 	BatchUpdate(fn func(*bolt.Tx) error) (err error)
 
 	GetVulnerabilityDetail(cveID string) (detail map[types.SourceID]types.VulnerabilityDetail, err error)
+	GetVulnerabilityFields(cveID string, fields []string) (detail map[types.SourceID]types.VulnerabilityDetail, err error)
 	PutVulnerabilityDetail(tx *bolt.Tx, vulnerabilityID string, source types.SourceID,
 		vulnerability types.VulnerabilityDetail) (err error)
 	DeleteVulnerabilityDetailBucket() (err error)
 
+	PutVulnerabilityAlias(tx *bolt.Tx, vulnID, alias string) (err error)
+	CanonicalID(vulnID string) (canonicalID string, err error)
+
 	ForEachAdvisory(sources []string, pkgName string) (value map[string]Value, err error)
 	GetAdvisories(source string, pkgName string) (advisories []types.Advisory, err error)
+	GetAdvisoriesPaged(source, pkgName string, offset, limit int) (advisories []types.Advisory, total int, err error)
+	GetFixableAdvisories(namespace, pkgName string) (advisories []types.Advisory, err error)
+	GetTaggedAdvisories(namespace, pkgName, tag string) (advisories []types.Advisory, err error)
+	MinimalFix(namespace, pkgName, installed string) (fixedVersion string, err error)
+	GetAdvisoriesBySeverity(namespace string, min types.Severity) (advisories []AdvisoryWithDetail, err error)
+	FindByPackage(name string) (advisories map[string][]types.Advisory, err error)
+	GetAllOSAdvisories(pkgName string) (advisories map[string][]types.Advisory, err error)
+	GetAdvisoriesByPURL(purl string) (advisories []types.Advisory, err error)
+	UpdateAdvisory(namespace, pkgName, vulnID string, adv types.Advisory) (err error)
+	GetAdvisoryGroup(id string) (advisories []GroupedAdvisory, err error)
+	WithNamespace(source string) (reader NamespaceReader, err error)
+	ExportVEX(components []Component, w io.Writer) (err error)
+	ExportOSV(dir string) (err error)
+	ExtractSource(name, dstPath string) (err error)
+	SearchText(query string) (vulnIDs []string, err error)
+	GetByCPE(cpe string) (vulnIDs []string, err error)
+	VerifyBucketHashes() (tampered []string, err error)
+	SeverityConflicts() (conflicts map[string]types.VendorSeverity, err error)
 
 	PutVulnerabilityID(tx *bolt.Tx, vulnerabilityID string) (err error)
 	ForEachVulnerabilityID(fn func(tx *bolt.Tx, cveID string) error) (err error)
@@ -44,8 +70,18 @@ type Operation interface {
 	SaveAdvisoryDetails(tx *bolt.Tx, cveID string) (err error)
 	PutAdvisoryDetail(tx *bolt.Tx, vulnerabilityID, pkgName string, nestedBktNames []string, advisory interface{}) (err error)
 	DeleteAdvisoryDetailBucket() error
+	PutAdvisoryGroup(tx *bolt.Tx, parentID, namespace, pkgName, vulnerabilityID string) (err error)
 
 	PutDataSource(tx *bolt.Tx, bktName string, source types.DataSource) (err error)
+	PutPackageAliases(tx *bolt.Tx, bktName string, aliases types.PackageAliases) (err error)
+	PutNamespaceAlias(tx *bolt.Tx, alias, namespace string) (err error)
+
+	ListNamespaces() (namespaces []string, err error)
+	Coverage() (stats map[string]CoverageStats, err error)
+
+	GetAffectedPackages(vulnID string) (pkgs []AffectedPackage, err error)
+	RebuildIndexes() (err error)
+	RenormalizeSeverities() (err error)
 
 	// For Red Hat
 	PutRedHatRepositories(tx *bolt.Tx, repository string, cpeIndices []int) (err error)
@@ -55,7 +91,224 @@ type Operation interface {
 	RedHatNVRToCPEs(nvr string) (cpeIndices []int, err error)
 }
 
+// AdvisoryMergeStrategy is how to handle advisories for the same vulnerability, namespace and package
+type AdvisoryMergeStrategy string
+
+const (
+	MergeOverwrite   AdvisoryMergeStrategy = "overwrite"    // the last one wins
+	MergeKeepFirst   AdvisoryMergeStrategy = "keep-first"   // the first one wins
+	MergeUnionRanges AdvisoryMergeStrategy = "union-ranges" // version constraints are combined, other fields are overwritten
+)
+
+// CvssMergePolicy is how PutVulnerabilityDetail reconciles CVSS scores with those already stored
+// for the same vulnerability and source, and how PutVulnerability selects the severity from the scores of all the sources
+type CvssMergePolicy string
+
+const (
+	CvssLastWrite CvssMergePolicy = "last-write" // the last one wins, and the severity is selected as normalized
+	CvssMax       CvssMergePolicy = "max"        // the highest score wins, for v2 and v3 respectively, across sources as well
+	CvssNVDFirst  CvssMergePolicy = "nvd-first"  // the NVD scores stored first are kept and win over the other sources
+)
+
+// ParseCvssMergePolicy returns the policy of the name. An empty name means CvssLastWrite.
+func ParseCvssMergePolicy(name string) (CvssMergePolicy, error) {
+	switch p := CvssMergePolicy(name); p {
+	case "":
+		return CvssLastWrite, nil
+	case CvssLastWrite, CvssMax, CvssNVDFirst:
+		return p, nil
+	}
+	return "", xerrors.Errorf("unknown CVSS merge policy: %s", name)
+}
+
+// RangeOverlapPolicy is how PutAdvisoryDetail handles an advisory listing a version as both vulnerable and patched
+type RangeOverlapPolicy string
+
+const (
+	RangeOverlapIgnore         RangeOverlapPolicy = "ignore"          // both ranges are stored as is
+	RangeOverlapStrict         RangeOverlapPolicy = "strict"          // the advisory is rejected with an error
+	RangeOverlapVulnerableWins RangeOverlapPolicy = "vulnerable-wins" // the overlapping patched ranges are dropped with a warning
+)
+
 type Config struct {
+	// ProgressFn is called by data sources while they are being updated so that
+	// callers can render the build progress. It may be nil.
+	ProgressFn func(source string, done, total int)
+
+	// DisableAdvisoryDedup makes PutAdvisoryDetail rewrite an advisory even if the identical one is already stored.
+	DisableAdvisoryDedup bool
+
+	// AdvisoryMergeStrategy decides how PutAdvisoryDetail handles an advisory already stored
+	// for the same vulnerability, namespace and package. The default is MergeOverwrite.
+	AdvisoryMergeStrategy AdvisoryMergeStrategy
+
+	// CoalesceRanges makes PutAdvisoryDetail keep the vulnerable versions of advisories stored more than once
+	// for the same vulnerability, namespace and package so that CoalesceAdvisoryRanges can union them after ingestion.
+	CoalesceRanges bool
+
+	// CvssMergePolicy decides how PutVulnerabilityDetail handles CVSS scores already stored for the same vulnerability
+	// and source, e.g. when a source lists a vulnerability several times, and how PutVulnerability selects the severity
+	// when sources disagree, e.g. NVD 9.8 and Red Hat 5.3. The default is CvssLastWrite.
+	CvssMergePolicy CvssMergePolicy
+
+	// RangeOverlapPolicy decides how PutAdvisoryDetail handles an advisory whose VulnerableVersions and PatchedVersions
+	// overlap, e.g. in a malformed upstream advisory. The default is RangeOverlapIgnore.
+	RangeOverlapPolicy RangeOverlapPolicy
+
+	// MaxAdvisoriesPerPackage makes the build fail when a package has more advisories than the limit in a namespace.
+	// It catches runaway data produced by a parser bug. Zero means no limit.
+	MaxAdvisoriesPerPackage int
+
+	// MaxReferences caps the number of references per vulnerability detail to keep the DB small.
+	// Sources with typed references keep advisory and fix references first. See SelectReferences.
+	// Zero means no limit.
+	MaxReferences int
+
+	// ReferenceDomainDenylist drops the references and exploit references whose host is one of the domains
+	// or their subdomains, e.g. "example.com" drops "https://www.example.com/advisory",
+	// for reports that must not link to certain sites. It is applied by PutVulnerabilityDetail and PutVulnerability.
+	ReferenceDomainDenylist []string
+
+	// StaleAfter makes the build warn about a data source whose latest modified date is older than the duration,
+	// since the upstream feed may have stopped updating. Zero disables the check.
+	StaleAfter time.Duration
+
+	// BuildTextIndex makes PutVulnerabilityDetail index the words of titles and descriptions for SearchText.
+	// It is opt-in since it grows the DB.
+	BuildTextIndex bool
+
+	// BuildCPEIndex makes PutVulnerabilityDetail index the CPEs of vulnerabilities for GetByCPE.
+	// It is opt-in since it grows the DB.
+	BuildCPEIndex bool
+
+	// TrackProvenance makes data sources record the upstream file of each advisory in Advisory.Provenance,
+	// e.g. for auditing a suspicious advisory.
+	TrackProvenance bool
+
+	// SourceDate makes data sources record it in Advisory.FetchedAt as the time the advisories were fetched.
+	// It is given rather than taken from the clock so that builds are reproducible, e.g. from SOURCE_DATE_EPOCH.
+	SourceDate time.Time
+
+	// BatchSize makes data sources commit every BatchSize records in separate transactions,
+	// trading the atomicity of a source update for lower memory. Zero means a single transaction per source.
+	BatchSize int
+
+	// AdvisoriesOnly makes PutVulnerabilityDetail a no-op for a minimal DB telling only whether packages are affected.
+	// Advisories and vulnerability IDs are still written.
+	AdvisoriesOnly bool
+
+	// ExcludeIDs are vulnerability IDs never written by PutVulnerabilityID, PutVulnerabilityDetail and PutAdvisoryDetail,
+	// e.g. disputed CVEs. If IncludeIDs is set, only those IDs are written. ExcludeIDs take precedence.
+	ExcludeIDs []string
+	IncludeIDs []string
+
+	// MinSeverity makes SaveAdvisoryDetails skip advisories less severe than it, e.g. to keep only CRITICAL ones.
+	// MinSeverityPerNamespace overrides it for the namespaces, e.g. SeverityUnknown for "npm::" namespaces
+	// to keep all the advisories of the application dependencies. Advisories of unknown severity are always kept.
+	MinSeverity             types.Severity
+	MinSeverityPerNamespace map[string]types.Severity
+
+	// IncludeDisputed makes GetAdvisories and the other advisory lookups return the advisories of vulnerabilities
+	// flagged as disputed by any source. They are left out by default.
+	IncludeDisputed bool
+
+	// IncludeObsolete makes GetAdvisories and the other advisory lookups return the advisories
+	// no longer given by upstream. See MarkObsoleteAdvisories.
+	IncludeObsolete bool
+
+	// BucketHashes makes the build store the hash of each namespace, so that VerifyBucketHashes
+	// can detect namespaces modified after the build.
+	BucketHashes bool
+
+	// CvssPrecision makes PutVulnerabilityDetail and PutVulnerability round CVSS scores to the number of decimal places,
+	// e.g. 9.832 is stored as 9.8 with 1, so that scores computed from vectors match the ones given by sources.
+	// Zero keeps the scores as given.
+	CvssPrecision int
+
+	// DetectSeverityConflicts makes PutVulnerability record vulnerabilities whose vendor severities disagree
+	// by more than one level, e.g. for analysts reviewing them. See SeverityConflicts.
+	DetectSeverityConflicts bool
+
+	// SeverityNormalizer merges the details given by the sources into a vulnerability for RenormalizeSeverities,
+	// e.g. Normalize of the vulnerability package, which cannot be imported here.
+	SeverityNormalizer func(details map[types.SourceID]types.VulnerabilityDetail) types.Vulnerability
+
+	// VersionComparer compares two versions of a package in the namespace and returns -1, 0 or 1 for MinimalFix,
+	// e.g. CompareInNamespace of the vulnerability package, which cannot be imported here.
+	VersionComparer func(namespace, a, b string) int
+
+	// PostProcessors are keyed by the source ID, e.g. "nodejs-security-wg", and called after the source ingests
+	// its advisories within the same transaction, e.g. to layer internal data on top of public feeds.
+	// Sources call them through PostProcess.
+	PostProcessors map[string]func(tx *bolt.Tx) error
+
+	// Cache is consulted by GetVulnerability and GetAdvisories before reading the DB, e.g. NewInProcessCache.
+	// PutVulnerability and PutAdvisory invalidate the entries they modify on commit. Advisories read through
+	// namespace prefixes such as "pip::" or aliases are not cached. Nil disables caching.
+	Cache Cache
+
+	// MetricsRegistry receives the numbers of advisories and vulnerability details written per data source
+	// and the durations of the build, e.g. NewInProcessMetrics. Nil disables metrics.
+	MetricsRegistry MetricsRegistry
+
+	// SourceTimeout aborts the update of a data source taking longer than it, so that a hung source doesn't block
+	// the build. The aborted source is logged and the other sources are still built. The writes of a source implementing
+	// vulnsrc.ContextUpdater, such as the built-in ones, fail from then on, so its open transaction is rolled back.
+	// Zero means no timeout.
+	SourceTimeout time.Duration
+
+	// FailOnSourceError fails the build if any data source fails, including the ones aborted on SourceTimeout,
+	// rather than producing a partial DB. The other sources are still updated, so that all the errors are reported at once.
+	FailOnSourceError bool
+
+	// OutputPath is the DB file written by a Config returned by Open, e.g. "/tmp/build1/trivy.db".
+	OutputPath string
+
+	// handle is the DB opened by Open. The global one opened by Init is used if nil.
+	handle *bolt.DB
+
+	// ctx is set by WithContext. Writes fail once it is done.
+	ctx context.Context
+
+	// HTTPClient is used by sources downloading feeds, e.g. to go through a proxy or trust a custom CA.
+	// See utils.NewHTTPClient. If nil, a client honoring HTTP_PROXY and NO_PROXY is used.
+	HTTPClient *http.Client
+}
+
+// Client returns the HTTP client for downloading feeds.
+func (dbc Config) Client() *http.Client {
+	if dbc.HTTPClient != nil {
+		return dbc.HTTPClient
+	}
+	return &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}}
+}
+
+// Progress reports the progress of the given source if ProgressFn is set.
+func (dbc Config) Progress(source string, done, total int) {
+	if dbc.ProgressFn != nil {
+		dbc.ProgressFn(source, done, total)
+	}
+}
+
+// FetchedAt returns SourceDate for Advisory.FetchedAt, or nil if it is not set.
+func (dbc Config) FetchedAt() *time.Time {
+	if dbc.SourceDate.IsZero() {
+		return nil
+	}
+	t := dbc.SourceDate.UTC()
+	return &t
+}
+
+// PostProcess calls the post-processor of the given source if it is registered.
+func (dbc Config) PostProcess(tx *bolt.Tx, source string) error {
+	fn, ok := dbc.PostProcessors[source]
+	if !ok {
+		return nil
+	}
+	if err := fn(tx); err != nil {
+		return xerrors.Errorf("post-processor error (%s): %w", source, err)
+	}
+	return nil
 }
 
 func Init(cacheDir string) (err error) {
@@ -101,12 +354,120 @@ func Close() error {
 	return nil
 }
 
+// Connection returns the DB opened by Open, or the global one opened by Init.
 func (dbc Config) Connection() *bolt.DB {
+	if dbc.handle != nil {
+		return dbc.handle
+	}
 	return db
 }
 
+// Open returns a copy of the config with its own DB, so that builds to different OutputPaths can run concurrently.
+// The DB is built into a temporary file next to OutputPath, and Finalize renames it to OutputPath
+// so that readers never see a partial DB. The temporary file starts as a copy of the DB at OutputPath if any,
+// so that the state of the previous build such as FirstSeen and checkpoints is kept as with Init.
+// The returned config must be given to the build, e.g. with vulndb.WithDBConfig, which passes it to the built-in
+// data sources. Sources creating their own config keep using the global DB opened by Init.
+func (dbc Config) Open() (Config, error) {
+	if dbc.OutputPath == "" {
+		return Config{}, xerrors.New("output path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(dbc.OutputPath), 0700); err != nil {
+		return Config{}, xerrors.Errorf("failed to mkdir: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(dbc.OutputPath), filepath.Base(dbc.OutputPath)+".*.tmp")
+	if err != nil {
+		return Config{}, xerrors.Errorf("failed to create a temp file: %w", err)
+	}
+	if err = copyFile(f, dbc.OutputPath); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return Config{}, xerrors.Errorf("failed to copy the previous DB: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return Config{}, xerrors.Errorf("failed to close the temp file: %w", err)
+	}
+
+	if dbc.handle, err = bolt.Open(f.Name(), 0600, nil); err != nil {
+		_ = os.Remove(f.Name())
+		return Config{}, xerrors.Errorf("failed to open db: %w", err)
+	}
+	return dbc, nil
+}
+
+// copyFile writes the content of the file at path to w. A missing file is copied as empty.
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return xerrors.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(w, f); err != nil {
+		return xerrors.Errorf("failed to copy %s: %w", path, err)
+	}
+	return nil
+}
+
+// Finalize closes the DB opened by Open and moves it to OutputPath, replacing the existing file.
+func (dbc Config) Finalize() error {
+	if dbc.handle == nil {
+		return xerrors.New("not opened")
+	}
+	tmpPath := dbc.handle.Path()
+	if err := dbc.handle.Close(); err != nil {
+		return xerrors.Errorf("failed to close DB: %w", err)
+	}
+	if err := os.Rename(tmpPath, dbc.OutputPath); err != nil {
+		return xerrors.Errorf("failed to rename %s: %w", tmpPath, err)
+	}
+	return nil
+}
+
+// Discard closes and removes the DB opened by Open, e.g. when the build fails. OutputPath is left untouched.
+func (dbc Config) Discard() error {
+	if dbc.handle == nil {
+		return xerrors.New("not opened")
+	}
+	tmpPath := dbc.handle.Path()
+	if err := dbc.handle.Close(); err != nil {
+		return xerrors.Errorf("failed to close DB: %w", err)
+	}
+	if err := os.Remove(tmpPath); err != nil {
+		return xerrors.Errorf("failed to remove %s: %w", tmpPath, err)
+	}
+	return nil
+}
+
+// WithContext returns a copy of the config whose writes fail once the context is done,
+// so that a data source aborted on SourceTimeout rolls back instead of writing after the build moved on.
+func (dbc Config) WithContext(ctx context.Context) Config {
+	dbc.ctx = ctx
+	return dbc
+}
+
+// aborted returns the error of the context given by WithContext, or nil if it is not done.
+func (dbc Config) aborted() error {
+	if dbc.ctx == nil {
+		return nil
+	}
+	return dbc.ctx.Err()
+}
+
 func (dbc Config) BatchUpdate(fn func(tx *bolt.Tx) error) error {
-	err := db.Batch(fn)
+	if err := dbc.aborted(); err != nil {
+		return xerrors.Errorf("batch update aborted: %w", err)
+	}
+	err := dbc.Connection().Batch(func(tx *bolt.Tx) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		// Roll back if the context was done while fn didn't write
+		return dbc.aborted()
+	})
 	if err != nil {
 		return xerrors.Errorf("error in batch update: %w", err)
 	}
@@ -114,31 +475,57 @@ func (dbc Config) BatchUpdate(fn func(tx *bolt.Tx) error) error {
 }
 
 func (dbc Config) put(tx *bolt.Tx, bktNames []string, key string, value interface{}) error {
+	v, err := json.Marshal(value)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	return dbc.putBytes(tx, bktNames, key, v)
+}
+
+func (dbc Config) putBytes(tx *bolt.Tx, bktNames []string, key string, value []byte) error {
 	if len(bktNames) == 0 {
 		return xerrors.Errorf("empty bucket name")
 	}
+	if err := dbc.aborted(); err != nil {
+		return xerrors.Errorf("write aborted: %w", err)
+	}
 
 	bkt, err := tx.CreateBucketIfNotExists([]byte(bktNames[0]))
 	if err != nil {
-		return xerrors.Errorf("failed to create '%s' bucket: %w", bktNames[0], err)
+		return xerrors.Errorf("failed to create '%s' bucket: %w", bktNames[0], NewError(ErrWrite, err))
 	}
 
 	for _, bktName := range bktNames[1:] {
 		bkt, err = bkt.CreateBucketIfNotExists([]byte(bktName))
 		if err != nil {
-			return xerrors.Errorf("failed to create a bucket: %w", err)
+			return xerrors.Errorf("failed to create a bucket: %w", NewError(ErrWrite, err))
 		}
 	}
-	v, err := json.Marshal(value)
-	if err != nil {
-		return xerrors.Errorf("failed to unmarshal JSON: %w", err)
-	}
 
-	return bkt.Put([]byte(key), v)
+	return NewError(ErrWrite, bkt.Put([]byte(key), value))
+}
+
+// getTx returns the value in the nested buckets, or nil if it doesn't exist.
+func (dbc Config) getTx(tx *bolt.Tx, bktNames []string, key string) []byte {
+	if len(bktNames) == 0 {
+		return nil
+	}
+	bkt := tx.Bucket([]byte(bktNames[0]))
+	for _, bktName := range bktNames[1:] {
+		if bkt == nil {
+			return nil
+		}
+		bkt = bkt.Bucket([]byte(bktName))
+	}
+	if bkt == nil {
+		return nil
+	}
+	return bkt.Get([]byte(key))
 }
 
 func (dbc Config) get(bktNames []string, key string) (value []byte, err error) {
-	err = db.View(func(tx *bolt.Tx) error {
+	err = dbc.Connection().View(func(tx *bolt.Tx) error {
 		if len(bktNames) == 0 {
 			return xerrors.Errorf("empty bucket name")
 		}
@@ -168,70 +555,77 @@ type Value struct {
 }
 
 func (dbc Config) forEach(bktNames []string) (map[string]Value, error) {
+	var values map[string]Value
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		var err error
+		values, err = dbc.forEachTx(tx, bktNames)
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get all key/value in the specified bucket: %w", err)
+	}
+	return values, nil
+}
+
+func (dbc Config) forEachTx(tx *bolt.Tx, bktNames []string) (map[string]Value, error) {
 	if len(bktNames) < 2 {
 		return nil, xerrors.Errorf("bucket must be nested: %v", bktNames)
 	}
 	rootBucket, nestedBuckets := bktNames[0], bktNames[1:]
 
 	values := map[string]Value{}
-	err := db.View(func(tx *bolt.Tx) error {
-		var rootBuckets []string
-
-		if strings.Contains(rootBucket, "::") {
-			// e.g. "pip::", "rubygems::"
-			prefix := []byte(rootBucket)
-			c := tx.Cursor()
-			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
-				rootBuckets = append(rootBuckets, string(k))
-			}
-		} else {
-			// e.g. "GitHub Security Advisory Composer"
-			rootBuckets = append(rootBuckets, rootBucket)
+	var rootBuckets []string
+
+	if strings.Contains(rootBucket, "::") {
+		// e.g. "pip::", "rubygems::"
+		prefix := []byte(rootBucket)
+		c := tx.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			rootBuckets = append(rootBuckets, string(k))
 		}
+	} else {
+		// e.g. "GitHub Security Advisory Composer"
+		rootBuckets = append(rootBuckets, rootBucket)
+	}
 
-		for _, r := range rootBuckets {
-			root := tx.Bucket([]byte(r))
-			if root == nil {
-				continue
-			}
+	for _, r := range rootBuckets {
+		root := tx.Bucket([]byte(r))
+		if root == nil {
+			continue
+		}
 
-			source, err := dbc.getDataSource(tx, r)
-			if err != nil {
-				log.Logger.Debugf("Data source error: %s", err)
-			}
+		source, err := dbc.getDataSource(tx, r)
+		if err != nil {
+			log.Logger.Debugf("Data source error: %s", err)
+		}
 
-			bkt := root
-			for _, nestedBkt := range nestedBuckets {
-				bkt = bkt.Bucket([]byte(nestedBkt))
-				if bkt == nil {
-					break
-				}
-			}
+		bkt := root
+		for _, nestedBkt := range nestedBuckets {
+			bkt = bkt.Bucket([]byte(nestedBkt))
 			if bkt == nil {
-				continue
+				break
 			}
+		}
+		if bkt == nil {
+			continue
+		}
 
-			err = bkt.ForEach(func(k, v []byte) error {
-				values[string(k)] = Value{
-					Source:  source,
-					Content: v,
-				}
-				return nil
-			})
-			if err != nil {
-				return xerrors.Errorf("db foreach error: %w", err)
+		err = bkt.ForEach(func(k, v []byte) error {
+			values[string(k)] = Value{
+				Source:  source,
+				Content: v,
 			}
+			return nil
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("db foreach error: %w", err)
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get all key/value in the specified bucket: %w", err)
 	}
 	return values, nil
 }
 
 func (dbc Config) deleteBucket(bucketName string) error {
-	return db.Update(func(tx *bolt.Tx) error {
+	return dbc.Connection().Update(func(tx *bolt.Tx) error {
 		if err := tx.DeleteBucket([]byte(bucketName)); err != nil {
 			return xerrors.Errorf("failed to delete bucket: %w", err)
 		}
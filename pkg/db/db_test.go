@@ -1,14 +1,19 @@
 package db_test
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
 )
 
 func TestInit(t *testing.T) {
@@ -65,3 +70,100 @@ func copy(dstPath, srcPath string) error {
 	_, err = io.Copy(dst, src)
 	return err
 }
+
+func TestConfig_Client(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	dbc := db.Config{HTTPClient: client}
+	require.Same(t, client, dbc.Client())
+
+	resp, err := dbc.Client().Get(ts.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusTeapot, resp.StatusCode)
+
+	require.NotNil(t, db.Config{}.Client())
+}
+
+func TestConfig_Open(t *testing.T) {
+	_, err := db.Config{}.Open()
+	require.EqualError(t, err, "output path is required")
+
+	cacheDir := t.TempDir()
+	outputPath := db.Path(cacheDir)
+
+	dbc, err := db.Config{OutputPath: outputPath}.Open()
+	require.NoError(t, err)
+	require.NoError(t, dbc.PutCheckpoint("previous"))
+	require.NoError(t, dbc.Finalize())
+	previous, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	// The previous DB is kept on failure
+	dbc, err = db.Config{OutputPath: outputPath}.Open()
+	require.NoError(t, err)
+	require.NoError(t, dbc.PutCheckpoint("failed"))
+	require.NoError(t, dbc.Discard())
+
+	b, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	require.Equal(t, previous, b)
+
+	// The build starts from the previous DB, which is replaced on success
+	dbc, err = db.Config{OutputPath: outputPath}.Open()
+	require.NoError(t, err)
+	done, err := dbc.Checkpointed("previous")
+	require.NoError(t, err)
+	assert.True(t, done)
+	require.NoError(t, dbc.PutBucketHashes())
+	require.NoError(t, dbc.Finalize())
+
+	files, err := os.ReadDir(filepath.Dir(outputPath))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+	_, err = db.Config{}.VerifyBucketHashes()
+	require.NoError(t, err)
+	done, err = db.Config{}.Checkpointed("failed")
+	require.NoError(t, err)
+	assert.False(t, done)
+}
+
+func TestConfig_WithContext(t *testing.T) {
+	require.NoError(t, db.Init(t.TempDir()))
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dbc := db.Config{}.WithContext(ctx)
+
+	// Writes after the context is done are rolled back with the ones before
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := dbc.PutVulnerabilityID(tx, "CVE-2021-0001"); err != nil {
+			return err
+		}
+		cancel()
+		return dbc.PutAdvisoryDetail(tx, "CVE-2021-0001", "example", []string{"test"}, map[string]string{})
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "context canceled")
+
+	err = db.Config{}.Connection().View(func(tx *bolt.Tx) error {
+		assert.Nil(t, tx.Bucket([]byte("vulnerability-id")))
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Nothing is written once the context is done
+	err = dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		t.Fatal("unexpected call")
+		return nil
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "batch update aborted")
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"encoding/json"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+// schemaChange is what a schema version introduced
+type schemaChange struct {
+	buckets             []string
+	advisoryFields      []string
+	vulnerabilityFields []string
+}
+
+// schemaChanges is keyed by the schema version introducing the change. DowngradeTo drops them in reverse order.
+var schemaChanges = map[int]schemaChange{
+	2: {
+		buckets: []string{dataSourceBucket, packageAliasBucket, namespaceAliasBucket, affectedPackageBucket, textIndexBucket,
+			bucketHashBucket, severityConflictBucket, cpeIndexBucket, advisoryGroupBucket, vulnerabilityAliasBucket},
+		advisoryFields:      []string{"Status", "DebianUrgency", "Arches", "IntroducedVersion", "Provenance", "FetchedAt", "FixCommits", "GitRanges", "Tags", "Obsolete", "AffectedSymbols"},
+		vulnerabilityFields: []string{"FirstSeen", "RiskScore", "SeveritySource", "ExploitRefs", "SeverityRank", "Disputed", "PluginRefs", "ReplacedBy", "ReferenceTitles"},
+	},
+}
+
+// DowngradeTo drops the buckets and fields introduced after the given schema version,
+// so that clients pinned to the older schema can load the DB.
+// The version in the metadata file must be updated by the caller.
+func DowngradeTo(version int) error {
+	if version < 1 || version > SchemaVersion {
+		return xerrors.Errorf("unsupported schema version: %d", version)
+	}
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		for v := SchemaVersion; v > version; v-- {
+			change, ok := schemaChanges[v]
+			if !ok {
+				continue
+			}
+			if err := downgrade(tx, change); err != nil {
+				return xerrors.Errorf("schema %d error: %w", v, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to downgrade the DB to schema %d: %w", version, err)
+	}
+	return nil
+}
+
+func downgrade(tx *bolt.Tx, change schemaChange) error {
+	for _, name := range change.buckets {
+		if err := deleteBucketIfExists(tx, name); err != nil {
+			return err
+		}
+	}
+
+	if bkt := tx.Bucket([]byte(vulnerabilityBucket)); bkt != nil {
+		if err := dropFields(bkt, change.vulnerabilityFields); err != nil {
+			return xerrors.Errorf("vulnerability error: %w", err)
+		}
+	}
+
+	// Advisories are stored in namespace => package name => vulnerability ID
+	return tx.ForEach(func(ns []byte, nsBkt *bolt.Bucket) error {
+		if _, ok := internalBuckets[string(ns)]; ok {
+			return nil
+		}
+		return nsBkt.ForEach(func(pkgName, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			if err := dropFields(nsBkt.Bucket(pkgName), change.advisoryFields); err != nil {
+				return xerrors.Errorf("advisory error in %s: %w", ns, err)
+			}
+			return nil
+		})
+	})
+}
+
+// dropFields removes the fields from the JSON objects stored in the bucket
+func dropFields(bkt *bolt.Bucket, fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	updated := map[string][]byte{}
+	err := bkt.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(v, &obj); err != nil {
+			return xerrors.Errorf("JSON unmarshal error (%s): %w", k, err)
+		}
+
+		var changed bool
+		for _, field := range fields {
+			if _, ok := obj[field]; ok {
+				delete(obj, field)
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return xerrors.Errorf("JSON marshal error (%s): %w", k, err)
+		}
+		updated[string(k)] = b
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Values must not be modified during iteration
+	for k, v := range updated {
+		if err = bkt.Put([]byte(k), v); err != nil {
+			return xerrors.Errorf("failed to put %s: %w", k, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestDowngradeTo(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		wantErr string
+	}{
+		{
+			name:    "v2 to v1",
+			version: 1,
+		},
+		{
+			name:    "unknown version",
+			version: 3,
+			wantErr: "unsupported schema version: 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := dbtest.InitDB(t, []string{"testdata/fixtures/downgrade.yaml"})
+
+			err := db.DowngradeTo(tt.version)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				require.NoError(t, db.Close())
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, db.Close())
+
+			dbPath := db.Path(dir)
+			dbtest.JSONEq(t, dbPath, []string{"arch-linux", "openssl", "CVE-2021-3711"}, types.Advisory{
+				FixedVersion:    "1.1.1.l-1",
+				PatchedVersions: []string{"1.1.1.l-1"},
+			})
+			dbtest.JSONEq(t, dbPath, []string{"vulnerability", "CVE-2021-3711"}, types.Vulnerability{
+				Title:    "openssl: SM2 Decryption Buffer Overflow",
+				Severity: "CRITICAL",
+			})
+			dbtest.NoBucket(t, dbPath, []string{"data-source"})
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// Kinds of errors for callers to tell the failure modes apart with xerrors.Is, e.g. xerrors.Is(err, db.ErrParse)
+var (
+	// ErrParse is for upstream data that cannot be decoded, e.g. broken JSON
+	ErrParse = xerrors.New("parse error")
+
+	// ErrWrite is for failures to store data in the DB
+	ErrWrite = xerrors.New("write error")
+
+	// ErrUnsupportedFormat is for upstream data in a format the source doesn't support
+	ErrUnsupportedFormat = xerrors.New("unsupported format")
+)
+
+// Error attaches one of the kinds above to an error without changing its message.
+// The underlying error is still available through xerrors.As and xerrors.Unwrap.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+// NewError returns the error of the kind. It returns nil if err is nil.
+func NewError(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
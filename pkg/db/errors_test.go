@@ -0,0 +1,43 @@
+package db_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestNewError(t *testing.T) {
+	cause := &json.SyntaxError{}
+	err := xerrors.Errorf("failed to decode: %w", db.NewError(db.ErrParse, cause))
+
+	assert.ErrorIs(t, err, db.ErrParse)
+	assert.NotErrorIs(t, err, db.ErrWrite)
+
+	// The underlying error is kept
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+
+	assert.Nil(t, db.NewError(db.ErrParse, nil))
+}
+
+func TestConfig_PutAdvisoryDetailWriteError(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	dbc := db.Config{}
+	// Writes fail in a read-only transaction
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		return dbc.PutAdvisoryDetail(tx, "CVE-2021-0001", "example", []string{"npm::test"}, types.Advisory{})
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, db.ErrWrite)
+	assert.ErrorIs(t, err, bolt.ErrTxNotWritable)
+}
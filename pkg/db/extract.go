@@ -0,0 +1,228 @@
+package db
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// ExtractSource copies the data of the source, e.g. "nodejs-security-wg", into a new DB at dstPath.
+// The namespaces registered for the source in the data-source bucket are copied with their aliases,
+// along with the advisory details, vulnerabilities, vulnerability IDs and the source's own vulnerability details
+// of the vulnerabilities they refer to. It works both before and after the DB is optimized.
+// dstPath must not exist.
+func (dbc Config) ExtractSource(name, dstPath string) error {
+	if _, err := os.Stat(dstPath); err == nil {
+		return xerrors.Errorf("%s already exists", dstPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0700); err != nil {
+		return xerrors.Errorf("failed to mkdir: %w", err)
+	}
+
+	dst, err := bolt.Open(dstPath, 0600, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to open %s: %w", dstPath, err)
+	}
+
+	err = dbc.Connection().View(func(src *bolt.Tx) error {
+		return dst.Update(func(dstTx *bolt.Tx) error {
+			return extractSource(src, dstTx, types.SourceID(name))
+		})
+	})
+	if cerr := dst.Close(); err == nil && cerr != nil {
+		err = xerrors.Errorf("failed to close %s: %w", dstPath, cerr)
+	}
+	if err != nil {
+		// Don't leave a partial DB
+		_ = os.Remove(dstPath)
+		return xerrors.Errorf("failed to extract %s: %w", name, err)
+	}
+	return nil
+}
+
+func extractSource(src, dst *bolt.Tx, sourceID types.SourceID) error {
+	namespaces, err := sourceNamespaces(src, sourceID)
+	if err != nil {
+		return err
+	} else if len(namespaces) == 0 {
+		return xerrors.Errorf("no namespace of %s", sourceID)
+	}
+
+	vulnIDs := map[string]struct{}{}
+	for ns := range namespaces {
+		if err = copyEntry(src, dst, []string{dataSourceBucket}, ns); err != nil {
+			return err
+		}
+		if err = copyEntry(src, dst, []string{packageAliasBucket}, ns); err != nil {
+			return err
+		}
+
+		// namespace => package name => vulnerability ID
+		nsBkt := src.Bucket([]byte(ns))
+		if nsBkt == nil {
+			continue
+		}
+		if err = copyEntry(src, dst, nil, ns); err != nil {
+			return err
+		}
+		err = nsBkt.ForEach(func(pkgName, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			return nsBkt.Bucket(pkgName).ForEach(func(vulnID, _ []byte) error {
+				vulnIDs[string(vulnID)] = struct{}{}
+				return nil
+			})
+		})
+		if err != nil {
+			return xerrors.Errorf("namespace walk error: %w", err)
+		}
+	}
+
+	// Namespaces falling back to the namespaces of the source
+	if bkt := src.Bucket([]byte(namespaceAliasBucket)); bkt != nil {
+		err = bkt.ForEach(func(alias, v []byte) error {
+			var ns string
+			if err := json.Unmarshal(v, &ns); err != nil {
+				return xerrors.Errorf("JSON unmarshal error (%s): %w", alias, err)
+			}
+			if _, ok := namespaces[ns]; !ok {
+				return nil
+			}
+			return copyEntry(src, dst, []string{namespaceAliasBucket}, string(alias))
+		})
+		if err != nil {
+			return xerrors.Errorf("namespace alias error: %w", err)
+		}
+	}
+
+	// advisory-detail => vulnerability ID => namespace, before the DB is optimized
+	if bkt := src.Bucket([]byte(advisoryDetailBucket)); bkt != nil {
+		err = bkt.ForEach(func(vulnID, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			for ns := range namespaces {
+				if bkt.Bucket(vulnID).Bucket([]byte(ns)) == nil {
+					continue
+				}
+				vulnIDs[string(vulnID)] = struct{}{}
+				if err := copyEntry(src, dst, []string{advisoryDetailBucket, string(vulnID)}, ns); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return xerrors.Errorf("advisory detail error: %w", err)
+		}
+	}
+
+	for vulnID := range vulnIDs {
+		if err = copyEntry(src, dst, []string{vulnerabilityBucket}, vulnID); err != nil {
+			return err
+		}
+		if err = copyEntry(src, dst, []string{vulnerabilityIDBucket}, vulnID); err != nil {
+			return err
+		}
+		if err = copyEntry(src, dst, []string{vulnerabilityDetailBucket, vulnID}, string(sourceID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sourceNamespaces returns the namespaces registered for the source in the data-source bucket
+func sourceNamespaces(tx *bolt.Tx, sourceID types.SourceID) (map[string]struct{}, error) {
+	namespaces := map[string]struct{}{}
+	bkt := tx.Bucket([]byte(dataSourceBucket))
+	if bkt == nil {
+		return namespaces, nil
+	}
+	err := bkt.ForEach(func(ns, v []byte) error {
+		var source types.DataSource
+		if err := json.Unmarshal(v, &source); err != nil {
+			return xerrors.Errorf("JSON unmarshal error (%s): %w", ns, err)
+		}
+		if source.ID == sourceID {
+			namespaces[string(ns)] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("data source error: %w", err)
+	}
+	return namespaces, nil
+}
+
+// copyEntry copies the value or the bucket under the nested buckets as is. Missing entries are skipped.
+func copyEntry(src, dst *bolt.Tx, bktNames []string, key string) error {
+	var srcBkt, dstBkt *bolt.Bucket
+	for i, name := range bktNames {
+		if i == 0 {
+			srcBkt = src.Bucket([]byte(name))
+		} else if srcBkt != nil {
+			srcBkt = srcBkt.Bucket([]byte(name))
+		}
+		if srcBkt == nil {
+			return nil
+		}
+	}
+
+	var value []byte
+	var nested *bolt.Bucket
+	if srcBkt == nil {
+		nested = src.Bucket([]byte(key))
+	} else if value = srcBkt.Get([]byte(key)); value == nil {
+		nested = srcBkt.Bucket([]byte(key))
+	}
+	if value == nil && nested == nil {
+		return nil
+	}
+
+	var err error
+	for i, name := range bktNames {
+		if i == 0 {
+			dstBkt, err = dst.CreateBucketIfNotExists([]byte(name))
+		} else {
+			dstBkt, err = dstBkt.CreateBucketIfNotExists([]byte(name))
+		}
+		if err != nil {
+			return xerrors.Errorf("failed to create %s bucket: %w", name, NewError(ErrWrite, err))
+		}
+	}
+
+	if value != nil {
+		return NewError(ErrWrite, dstBkt.Put([]byte(key), value))
+	}
+
+	var dstNested *bolt.Bucket
+	if dstBkt == nil {
+		dstNested, err = dst.CreateBucketIfNotExists([]byte(key))
+	} else {
+		dstNested, err = dstBkt.CreateBucketIfNotExists([]byte(key))
+	}
+	if err != nil {
+		return xerrors.Errorf("failed to create %s bucket: %w", key, NewError(ErrWrite, err))
+	}
+	return copyBucket(nested, dstNested)
+}
+
+// copyBucket copies the keys and values of the bucket recursively
+func copyBucket(src, dst *bolt.Bucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return NewError(ErrWrite, dst.Put(k, v))
+		}
+		nested, err := dst.CreateBucketIfNotExists(k)
+		if err != nil {
+			return xerrors.Errorf("failed to create %s bucket: %w", k, NewError(ErrWrite, err))
+		}
+		return copyBucket(src.Bucket(k), nested)
+	})
+}
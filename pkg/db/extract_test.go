@@ -0,0 +1,56 @@
+package db_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_ExtractSource(t *testing.T) {
+	_ = dbtest.InitDB(t, []string{"testdata/fixtures/extract.yaml"})
+	defer db.Close()
+
+	dstPath := filepath.Join(t.TempDir(), "node.db")
+	dbc := db.Config{}
+	require.NoError(t, dbc.ExtractSource("nodejs-security-wg", dstPath))
+
+	nodeNamespace := "npm::Node.js Ecosystem Security Working Group"
+	dbtest.JSONEq(t, dstPath, []string{nodeNamespace, "bassmaster", "CVE-2014-7205"}, types.Advisory{
+		VulnerableVersions: []string{"<=1.5.1"},
+		PatchedVersions:    []string{">=1.5.2"},
+	})
+	dbtest.JSONEq(t, dstPath, []string{"data-source", nodeNamespace}, types.DataSource{
+		ID:   "nodejs-security-wg",
+		Name: "Node.js Ecosystem Security Working Group",
+		URL:  "https://github.com/nodejs/security-wg",
+	})
+	dbtest.JSONEq(t, dstPath, []string{"vulnerability", "CVE-2014-7205"}, types.Vulnerability{
+		Title: "Arbitrary JavaScript Execution",
+	})
+	dbtest.JSONEq(t, dstPath, []string{"vulnerability-detail", "CVE-2014-7205", "nodejs-security-wg"}, types.VulnerabilityDetail{
+		Title: "Arbitrary JavaScript Execution",
+	})
+
+	// The other sources are not copied
+	dbtest.NoBucket(t, dstPath, []string{"npm::GitHub Security Advisory npm"})
+	dbtest.NoKey(t, dstPath, []string{"data-source", "npm::GitHub Security Advisory npm"})
+	dbtest.NoKey(t, dstPath, []string{"vulnerability", "CVE-2021-23337"})
+	dbtest.NoKey(t, dstPath, []string{"vulnerability-detail", "CVE-2014-7205", "nvd"})
+	dbtest.NoBucket(t, dstPath, []string{"vulnerability-detail", "CVE-2021-23337"})
+
+	// The destination must not exist
+	err := dbc.ExtractSource("nodejs-security-wg", dstPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	// Unknown source
+	err = dbc.ExtractSource("unknown", filepath.Join(t.TempDir(), "unknown.db"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no namespace of unknown")
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"encoding/json"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
+)
+
+const (
+	affectedPackageBucket = "affected-package"
+)
+
+// AffectedPackage is a package having an advisory for a vulnerability
+type AffectedPackage struct {
+	Namespace string
+	PkgName   string
+}
+
+// GetAffectedPackages returns the packages having an advisory for the vulnerability.
+// It reads the index generated by RebuildIndexes.
+func (dbc Config) GetAffectedPackages(vulnID string) ([]AffectedPackage, error) {
+	value, err := dbc.get([]string{affectedPackageBucket}, vulnID)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get affected packages: %w", err)
+	} else if value == nil {
+		return nil, nil
+	}
+
+	var pkgs []AffectedPackage
+	if err = json.Unmarshal(value, &pkgs); err != nil {
+		return nil, xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+	return pkgs, nil
+}
+
+// RebuildIndexes clears and regenerates the secondary indexes from the primary data.
+//   - affected-package: vulnerability ID => packages, generated from advisories in all namespaces
+//   - package-alias: aliases are made symmetric, so a missing reverse entry is restored
+func (dbc Config) RebuildIndexes() error {
+	err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+		if err := dbc.rebuildAffectedPackages(tx); err != nil {
+			return xerrors.Errorf("affected package index error: %w", err)
+		}
+		if err := dbc.rebuildPackageAliases(tx); err != nil {
+			return xerrors.Errorf("package alias index error: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to rebuild indexes: %w", err)
+	}
+	return nil
+}
+
+func (dbc Config) rebuildAffectedPackages(tx *bolt.Tx) error {
+	affected := map[string][]AffectedPackage{}
+	err := tx.ForEach(func(ns []byte, nsBkt *bolt.Bucket) error {
+		if _, ok := internalBuckets[string(ns)]; ok {
+			return nil
+		}
+		return nsBkt.ForEach(func(pkgName, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			return nsBkt.Bucket(pkgName).ForEach(func(vulnID, _ []byte) error {
+				affected[string(vulnID)] = append(affected[string(vulnID)], AffectedPackage{
+					Namespace: string(ns),
+					PkgName:   string(pkgName),
+				})
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return xerrors.Errorf("namespace walk error: %w", err)
+	}
+
+	if err = deleteBucketIfExists(tx, affectedPackageBucket); err != nil {
+		return err
+	}
+	for vulnID, pkgs := range affected {
+		// Namespaces and packages are walked in the key order, so pkgs are already sorted.
+		if err = dbc.put(tx, []string{affectedPackageBucket}, vulnID, pkgs); err != nil {
+			return xerrors.Errorf("failed to put affected packages: %w", err)
+		}
+	}
+	return nil
+}
+
+func (dbc Config) rebuildPackageAliases(tx *bolt.Tx) error {
+	root := tx.Bucket([]byte(packageAliasBucket))
+	if root == nil {
+		return nil
+	}
+
+	// namespace => package name => aliases
+	aliases := map[string]map[string][]string{}
+	err := root.ForEach(func(ns, _ []byte) error {
+		nsAliases := map[string][]string{}
+		aliases[string(ns)] = nsAliases
+		return root.Bucket(ns).ForEach(func(pkgName, v []byte) error {
+			var names []string
+			if err := json.Unmarshal(v, &names); err != nil {
+				return xerrors.Errorf("JSON unmarshal error: %w", err)
+			}
+			for _, name := range names {
+				if name == string(pkgName) {
+					continue
+				}
+				nsAliases[string(pkgName)] = append(nsAliases[string(pkgName)], name)
+				nsAliases[name] = append(nsAliases[name], string(pkgName))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return xerrors.Errorf("package alias walk error: %w", err)
+	}
+
+	if err = deleteBucketIfExists(tx, packageAliasBucket); err != nil {
+		return err
+	}
+	for ns, nsAliases := range aliases {
+		for pkgName, names := range nsAliases {
+			names = ustrings.Unique(names)
+			sort.Strings(names)
+			if err = dbc.put(tx, []string{packageAliasBucket, ns}, pkgName, names); err != nil {
+				return xerrors.Errorf("failed to put package aliases: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func deleteBucketIfExists(tx *bolt.Tx, name string) error {
+	if tx.Bucket([]byte(name)) == nil {
+		return nil
+	}
+	if err := tx.DeleteBucket([]byte(name)); err != nil {
+		return xerrors.Errorf("failed to delete %s bucket: %w", name, err)
+	}
+	return nil
+}
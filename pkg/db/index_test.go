@@ -0,0 +1,53 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+)
+
+func TestConfig_RebuildIndexes(t *testing.T) {
+	cacheDir := dbtest.InitDB(t, []string{"testdata/fixtures/index.yaml"})
+	defer db.Close()
+
+	dbc := db.Config{}
+
+	// Corrupted before rebuilding
+	got, err := dbc.GetAffectedPackages("CVE-2021-3711")
+	require.NoError(t, err)
+	assert.Equal(t, []db.AffectedPackage{{Namespace: "debian 10", PkgName: "openssl"}}, got)
+
+	require.NoError(t, dbc.RebuildIndexes())
+
+	got, err = dbc.GetAffectedPackages("CVE-2021-3711")
+	require.NoError(t, err)
+	assert.Equal(t, []db.AffectedPackage{
+		{Namespace: "alpine 3.14", PkgName: "openssl"},
+		{Namespace: "debian 11", PkgName: "openssl"},
+	}, got)
+
+	got, err = dbc.GetAffectedPackages("CVE-2021-22945")
+	require.NoError(t, err)
+	assert.Equal(t, []db.AffectedPackage{{Namespace: "alpine 3.14", PkgName: "curl"}}, got)
+
+	// The stale entry is removed
+	got, err = dbc.GetAffectedPackages("CVE-2000-0001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// The index is not a namespace
+	namespaces, err := dbc.ListNamespaces()
+	require.NoError(t, err)
+	assert.NotContains(t, namespaces, "affected-package")
+
+	// The reverse alias is restored
+	require.NoError(t, db.Close())
+	dbPath := db.Path(cacheDir)
+	dbtest.JSONEq(t, dbPath, []string{"package-alias", "rpm::Example", "nodejs"}, []string{"node"})
+	dbtest.JSONEq(t, dbPath, []string{"package-alias", "rpm::Example", "node"}, []string{"nodejs"})
+	require.NoError(t, db.Init(cacheDir))
+}
@@ -0,0 +1,859 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
+)
+
+// ErrUnsupported is returned by MemoryDB for operations it doesn't implement
+var ErrUnsupported = xerrors.New("unsupported by the in-memory DB")
+
+// memBucket mirrors a bolt bucket
+type memBucket struct {
+	buckets map[string]*memBucket
+	values  map[string][]byte
+}
+
+func newMemBucket() *memBucket {
+	return &memBucket{
+		buckets: map[string]*memBucket{},
+		values:  map[string][]byte{},
+	}
+}
+
+func (b *memBucket) bucket(names ...string) *memBucket {
+	for _, name := range names {
+		if b == nil {
+			return nil
+		}
+		b = b.buckets[name]
+	}
+	return b
+}
+
+func (b *memBucket) createBucket(names ...string) *memBucket {
+	for _, name := range names {
+		child, ok := b.buckets[name]
+		if !ok {
+			child = newMemBucket()
+			b.buckets[name] = child
+		}
+		b = child
+	}
+	return b
+}
+
+// sortedKeys returns the keys of the values in the same order as bolt
+func (b *memBucket) sortedKeys() []string {
+	var keys []string
+	for k := range b.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MemoryDB is an Operation backed by maps, for tests and short-lived tooling which don't need a DB file.
+// The layout is the same as the bolt DB, and advisories are written by the same code as Config,
+// so that e.g. ExcludeIDs, AdvisoryMergeStrategy and MaxAdvisoriesPerPackage of the config apply.
+// The transaction passed to the BatchUpdate and ForEachVulnerabilityID callbacks is nil,
+// so the callbacks must not use it other than passing it to MemoryDB.
+// ExportVEX, ExportOSV, ExtractSource, GetAdvisoriesBySeverity, GetAffectedPackages, RebuildIndexes,
+// RenormalizeSeverities, SearchText, GetByCPE, GetAdvisoryGroup, VerifyBucketHashes, SeverityConflicts,
+// Coverage and MinimalFix return ErrUnsupported.
+type MemoryDB struct {
+	dbc Config
+
+	// batchMu serializes BatchUpdate like the single writer of bolt, and mu guards root
+	batchMu sync.Mutex
+	mu      sync.RWMutex
+	root    *memBucket
+}
+
+// NewMemoryDB returns an empty MemoryDB writing with the options of the config.
+// The DB of the config, e.g. opened by Init, is not used.
+func NewMemoryDB(dbc Config) *MemoryDB {
+	return &MemoryDB{dbc: dbc, root: newMemBucket()}
+}
+
+func (m *MemoryDB) BatchUpdate(fn func(*bolt.Tx) error) error {
+	m.batchMu.Lock()
+	defer m.batchMu.Unlock()
+
+	if err := fn(nil); err != nil {
+		return xerrors.Errorf("error in batch update: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) put(bktNames []string, key string, value interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return putJSON(m, bktNames, key, value)
+}
+
+// value, values, countKeys and putValue implement store. The caller must hold mu.
+func (m *MemoryDB) value(bktNames []string, key string) []byte {
+	bkt := m.root.bucket(bktNames...)
+	if bkt == nil {
+		return nil
+	}
+	return bkt.values[key]
+}
+
+func (m *MemoryDB) values(bktNames []string) map[string][]byte {
+	bkt := m.root.bucket(bktNames...)
+	if bkt == nil {
+		return nil
+	}
+	return bkt.values
+}
+
+func (m *MemoryDB) countKeys(bktNames []string, _ int) int {
+	bkt := m.root.bucket(bktNames...)
+	if bkt == nil {
+		return 0
+	}
+	return len(bkt.buckets) + len(bkt.values)
+}
+
+func (m *MemoryDB) putValue(bktNames []string, key string, value []byte) error {
+	if len(bktNames) == 0 {
+		return xerrors.Errorf("empty bucket name")
+	}
+	m.root.createBucket(bktNames...).values[key] = value
+	return nil
+}
+
+func (m *MemoryDB) get(bktNames []string, key string) []byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.value(bktNames, key)
+}
+
+func (m *MemoryDB) GetVulnerabilityDetail(cveID string) (map[types.SourceID]types.VulnerabilityDetail, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	chain := replacementChain{}
+	cveID = normalizeVulnID(cveID)
+	for {
+		details, err := m.getVulnerabilityDetail(cveID)
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := chain.next(cveID, detailReplacedBy(details))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to follow the replacement: %w", err)
+		} else if replacement == "" {
+			return details, nil
+		}
+		cveID = replacement
+	}
+}
+
+func (m *MemoryDB) getVulnerabilityDetail(cveID string) (map[types.SourceID]types.VulnerabilityDetail, error) {
+	bkt := m.root.bucket(vulnerabilityDetailBucket, cveID)
+	if bkt == nil || len(bkt.values) == 0 {
+		return nil, nil
+	}
+
+	details := map[types.SourceID]types.VulnerabilityDetail{}
+	for source, v := range bkt.values {
+		var detail types.VulnerabilityDetail
+		if err := json.Unmarshal(v, &detail); err != nil {
+			return nil, xerrors.Errorf("failed to unmarshal Vulnerability JSON: %w", err)
+		}
+		details[types.SourceID(source)] = detail
+	}
+	return details, nil
+}
+
+func (m *MemoryDB) GetVulnerabilityFields(cveID string, fields []string) (map[types.SourceID]types.VulnerabilityDetail, error) {
+	projection, err := newDetailProjection(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	chain := replacementChain{}
+	cveID = normalizeVulnID(cveID)
+	for {
+		values := map[string]Value{}
+		if bkt := m.root.bucket(vulnerabilityDetailBucket, cveID); bkt != nil {
+			for source, v := range bkt.values {
+				values[source] = Value{Content: v}
+			}
+		}
+		details, replacedBy, err := projection.apply(values)
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := chain.next(cveID, replacedBy)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to follow the replacement: %w", err)
+		} else if replacement == "" {
+			return details, nil
+		}
+		cveID = replacement
+	}
+}
+
+func (m *MemoryDB) PutVulnerabilityDetail(_ *bolt.Tx, cveID string, source types.SourceID, vuln types.VulnerabilityDetail) error {
+	if m.dbc.AdvisoriesOnly || m.dbc.filteredOut(normalizeVulnID(cveID)) {
+		return nil
+	}
+	if err := m.put([]string{vulnerabilityDetailBucket, normalizeVulnID(cveID)}, string(source), vuln); err != nil {
+		return xerrors.Errorf("failed to put vulnerability detail: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) PutVulnerabilityAlias(_ *bolt.Tx, vulnID, alias string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	put := func(id, p string) error {
+		return putJSON(m, []string{vulnerabilityAliasBucket}, id, p)
+	}
+	if err := unionAliases(m.aliasParent, put, vulnID, alias); err != nil {
+		return xerrors.Errorf("failed to put the vulnerability alias: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) CanonicalID(vulnID string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return findAlias(m.aliasParent, normalizeVulnID(vulnID))
+}
+
+func (m *MemoryDB) aliasParent(id string) (string, error) {
+	bkt := m.root.bucket(vulnerabilityAliasBucket)
+	if bkt == nil || bkt.values[id] == nil {
+		return "", nil
+	}
+	var p string
+	if err := json.Unmarshal(bkt.values[id], &p); err != nil {
+		return "", xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+	return p, nil
+}
+
+func (m *MemoryDB) DeleteVulnerabilityDetailBucket() error {
+	return m.deleteBucket(vulnerabilityDetailBucket)
+}
+
+func (m *MemoryDB) deleteBucket(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.root.buckets[name]; !ok {
+		return xerrors.Errorf("failed to delete bucket: %w", bolt.ErrBucketNotFound)
+	}
+	delete(m.root.buckets, name)
+	return nil
+}
+
+func (m *MemoryDB) ForEachAdvisory(sources []string, pkgName string) (map[string]Value, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.forEach(append(sources, pkgName))
+}
+
+// forEach behaves the same as Config.forEachTx
+func (m *MemoryDB) forEach(bktNames []string) (map[string]Value, error) {
+	if len(bktNames) < 2 {
+		return nil, xerrors.Errorf("bucket must be nested: %v", bktNames)
+	}
+	rootBucket, nestedBuckets := bktNames[0], bktNames[1:]
+
+	values := map[string]Value{}
+	for _, r := range m.rootBuckets(rootBucket) {
+		source, err := m.dataSource(r)
+		if err != nil {
+			return nil, xerrors.Errorf("data source error: %w", err)
+		}
+
+		bkt := m.root.bucket(append([]string{r}, nestedBuckets...)...)
+		if bkt == nil {
+			continue
+		}
+		for k, v := range bkt.values {
+			values[k] = Value{
+				Source:  source,
+				Content: v,
+			}
+		}
+	}
+	return values, nil
+}
+
+// rootBuckets resolves a prefix such as "pip::" to the matching buckets
+func (m *MemoryDB) rootBuckets(name string) []string {
+	return bucketsWithPrefix(m.root, name)
+}
+
+func (m *MemoryDB) dataSource(bktName string) (types.DataSource, error) {
+	b := m.root.bucket(dataSourceBucket)
+	if b == nil || b.values[bktName] == nil {
+		return types.DataSource{}, nil
+	}
+	var source types.DataSource
+	if err := json.Unmarshal(b.values[bktName], &source); err != nil {
+		return types.DataSource{}, xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+	return source, nil
+}
+
+func (m *MemoryDB) GetAdvisories(source, pkgName string) ([]types.Advisory, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.getAdvisories(source, pkgName)
+}
+
+func (m *MemoryDB) getAdvisories(source, pkgName string) ([]types.Advisory, error) {
+	advisories, err := m.forEachAdvisory(source, pkgName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The namespace may share advisories with another one, e.g. "oracle linux 8.6" => "oracle linux 8"
+	if len(advisories) == 0 {
+		if b := m.root.bucket(namespaceAliasBucket); b != nil && b.values[source] != nil {
+			var namespace string
+			if err = json.Unmarshal(b.values[source], &namespace); err != nil {
+				return nil, xerrors.Errorf("JSON unmarshal error: %w", err)
+			}
+			if advisories, err = m.forEachAdvisory(namespace, pkgName); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	results, err := toAdvisories(advisories)
+	if err != nil {
+		return nil, err
+	}
+	return m.dropHidden(results)
+}
+
+// dropHidden drops the obsolete and disputed advisories unless the config includes them, as Config does
+func (m *MemoryDB) dropHidden(advisories []types.Advisory) ([]types.Advisory, error) {
+	if !m.dbc.IncludeObsolete {
+		advisories = dropObsolete(advisories)
+	}
+	if !m.dbc.IncludeDisputed {
+		return dropDisputed(m, advisories)
+	}
+	return advisories, nil
+}
+
+func (m *MemoryDB) GetAdvisoriesPaged(source, pkgName string, offset, limit int) ([]types.Advisory, int, error) {
+	if offset < 0 {
+		return nil, 0, xerrors.Errorf("negative offset: %d", offset)
+	}
+	advisories, err := m.GetAdvisories(source, pkgName)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, total := paginate(advisories, offset, limit)
+	return page, total, nil
+}
+
+func (m *MemoryDB) GetFixableAdvisories(namespace, pkgName string) ([]types.Advisory, error) {
+	advisories, err := m.GetAdvisories(namespace, pkgName)
+	if err != nil {
+		return nil, err
+	}
+	return fixableAdvisories(advisories), nil
+}
+
+func (m *MemoryDB) GetTaggedAdvisories(namespace, pkgName, tag string) ([]types.Advisory, error) {
+	advisories, err := m.GetAdvisories(namespace, pkgName)
+	if err != nil {
+		return nil, err
+	}
+	return taggedAdvisories(advisories, tag), nil
+}
+
+func (m *MemoryDB) GetAdvisoriesByPURL(purl string) ([]types.Advisory, error) {
+	namespace, pkgName, err := resolvePURL(purl)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to resolve %s: %w", purl, err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.getAdvisories(namespace, pkgName)
+}
+
+func (m *MemoryDB) FindByPackage(name string) (map[string][]types.Advisory, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := map[string][]types.Advisory{}
+	for ns := range m.root.buckets {
+		if _, ok := internalBuckets[ns]; ok {
+			continue
+		}
+		values, err := m.forEachAdvisory(ns, name)
+		if err != nil {
+			return nil, err
+		}
+		advisories, err := toAdvisories(values)
+		if err != nil {
+			return nil, err
+		}
+		if advisories, err = m.dropHidden(advisories); err != nil {
+			return nil, err
+		} else if len(advisories) == 0 {
+			continue
+		}
+		sort.Slice(advisories, func(i, j int) bool {
+			return advisories[i].VulnerabilityID < advisories[j].VulnerabilityID
+		})
+		results[ns] = advisories
+	}
+	return results, nil
+}
+
+func (m *MemoryDB) GetAllOSAdvisories(pkgName string) (map[string][]types.Advisory, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := map[string][]types.Advisory{}
+	for ns := range m.root.buckets {
+		if _, ok := internalBuckets[ns]; ok || isLanguageNamespace(ns) {
+			continue
+		}
+		advisories, err := m.getAdvisories(ns, pkgName)
+		if err != nil {
+			return nil, err
+		} else if len(advisories) == 0 {
+			continue
+		}
+		sort.Slice(advisories, func(i, j int) bool {
+			return advisories[i].VulnerabilityID < advisories[j].VulnerabilityID
+		})
+		results[ns] = advisories
+	}
+	return results, nil
+}
+
+func (m *MemoryDB) packageAliases(source, pkgName string) ([]string, error) {
+	root := m.root.bucket(packageAliasBucket)
+	if root == nil {
+		return nil, nil
+	}
+
+	var aliases []string
+	for _, ns := range bucketsWithPrefix(root, source) {
+		b := root.bucket(ns)
+		if b == nil || b.values[pkgName] == nil {
+			continue
+		}
+		var names []string
+		if err := json.Unmarshal(b.values[pkgName], &names); err != nil {
+			return nil, xerrors.Errorf("JSON unmarshal error: %w", err)
+		}
+		aliases = append(aliases, names...)
+	}
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+	return ustrings.Unique(aliases), nil
+}
+
+func bucketsWithPrefix(b *memBucket, name string) []string {
+	if !strings.Contains(name, "::") {
+		return []string{name}
+	}
+	var names []string
+	for k := range b.buckets {
+		if strings.HasPrefix(k, name) {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *MemoryDB) forEachAdvisory(source, pkgName string) (map[string]Value, error) {
+	advisories, err := m.forEach([]string{source, pkgName})
+	if err != nil {
+		return nil, xerrors.Errorf("advisory foreach error: %w", err)
+	}
+
+	// Advisories may be stored under another name of the package
+	aliases, err := m.packageAliases(source, pkgName)
+	if err != nil {
+		return nil, xerrors.Errorf("package alias error: %w", err)
+	}
+	for _, alias := range aliases {
+		aliased, err := m.forEach([]string{source, alias})
+		if err != nil {
+			return nil, xerrors.Errorf("advisory foreach error: %w", err)
+		}
+		for vulnID, v := range aliased {
+			if _, ok := advisories[vulnID]; !ok {
+				advisories[vulnID] = v
+			}
+		}
+	}
+	return advisories, nil
+}
+
+func (m *MemoryDB) GetAdvisoriesBySeverity(string, types.Severity) ([]AdvisoryWithDetail, error) {
+	return nil, ErrUnsupported
+}
+
+type memNamespaceReader struct {
+	m      *MemoryDB
+	source string
+}
+
+func (r memNamespaceReader) GetAdvisories(pkgName string) ([]types.Advisory, error) {
+	return r.m.GetAdvisories(r.source, pkgName)
+}
+
+func (r memNamespaceReader) Close() error {
+	return nil
+}
+
+func (m *MemoryDB) WithNamespace(source string) (NamespaceReader, error) {
+	return memNamespaceReader{m: m, source: source}, nil
+}
+
+func (m *MemoryDB) ExportVEX([]Component, io.Writer) error {
+	return ErrUnsupported
+}
+
+func (m *MemoryDB) ExportOSV(string) error {
+	return ErrUnsupported
+}
+
+func (m *MemoryDB) ExtractSource(string, string) error {
+	return ErrUnsupported
+}
+
+func (m *MemoryDB) SearchText(string) ([]string, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *MemoryDB) GetByCPE(string) ([]string, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *MemoryDB) VerifyBucketHashes() ([]string, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *MemoryDB) SeverityConflicts() (map[string]types.VendorSeverity, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *MemoryDB) PutVulnerabilityID(_ *bolt.Tx, vulnID string) error {
+	vulnID = normalizeVulnID(vulnID)
+	if m.dbc.filteredOut(vulnID) {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.putValue([]string{vulnerabilityIDBucket}, vulnID, []byte("{}"))
+}
+
+// ForEachVulnerabilityID calls fn without holding the lock, so that fn can write, e.g. with SaveAdvisoryDetails
+func (m *MemoryDB) ForEachVulnerabilityID(fn func(tx *bolt.Tx, vulnID string) error) error {
+	m.mu.RLock()
+	bkt := m.root.bucket(vulnerabilityIDBucket)
+	var vulnIDs []string
+	if bkt != nil {
+		vulnIDs = bkt.sortedKeys()
+	}
+	m.mu.RUnlock()
+
+	if bkt == nil {
+		return xerrors.Errorf("no such bucket: %s", vulnerabilityIDBucket)
+	}
+	for _, vulnID := range vulnIDs {
+		if err := fn(nil, vulnID); err != nil {
+			return xerrors.Errorf("something wrong: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryDB) PutVulnerability(_ *bolt.Tx, cveID string, vuln types.Vulnerability) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cveID = normalizeVulnID(cveID)
+	severity, _ := types.NewSeverity(vuln.Severity)
+	vuln.SeverityRank = int(severity)
+
+	if b := m.root.bucket(vulnerabilityBucket); b != nil && b.values[cveID] != nil {
+		var stored types.Vulnerability
+		if err := json.Unmarshal(b.values[cveID], &stored); err != nil {
+			return xerrors.Errorf("failed to unmarshal the stored vulnerability: %w", err)
+		}
+		if stored.FirstSeen != nil {
+			vuln.FirstSeen = stored.FirstSeen
+		}
+	}
+	if err := putJSON(m, []string{vulnerabilityBucket}, cveID, vuln); err != nil {
+		return xerrors.Errorf("failed to put severity: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) GetVulnerability(cveID string) (types.Vulnerability, error) {
+	chain := replacementChain{}
+	cveID = normalizeVulnID(cveID)
+	for {
+		var vuln types.Vulnerability
+		if err := json.Unmarshal(m.get([]string{vulnerabilityBucket}, cveID), &vuln); err != nil {
+			return types.Vulnerability{}, xerrors.Errorf("failed to get the vulnerability: %w", err)
+		}
+		replacement, err := chain.next(cveID, vuln.ReplacedBy)
+		if err != nil {
+			return types.Vulnerability{}, xerrors.Errorf("failed to follow the replacement: %w", err)
+		} else if replacement == "" {
+			return vuln, nil
+		}
+		cveID = replacement
+	}
+}
+
+func (m *MemoryDB) SaveAdvisoryDetails(_ *bolt.Tx, cveID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cveBucket := m.root.bucket(advisoryDetailBucket, cveID)
+	if cveBucket == nil {
+		return nil
+	}
+	if err := m.saveAdvisories(cveBucket, nil, cveID); err != nil {
+		return xerrors.Errorf("walk advisories error: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) saveAdvisories(bkt *memBucket, bktNames []string, vulnID string) error {
+	for name, child := range bkt.buckets {
+		bkts := append(append([]string{}, bktNames...), name)
+		if err := m.saveAdvisories(child, bkts, vulnID); err != nil {
+			return err
+		}
+	}
+	for pkgName, v := range bkt.values {
+		detail := map[string]interface{}{}
+		if err := json.Unmarshal(v, &detail); err != nil {
+			return xerrors.Errorf("failed to unmarshall the advisory detail: %w", err)
+		}
+		bkts := append(append([]string{}, bktNames...), pkgName)
+		if err := m.dbc.saveAdvisory(m, bkts, vulnID, detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemoryDB) PutAdvisoryDetail(_ *bolt.Tx, vulnID, pkgName string, nestedBktNames []string, advisory interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := m.dbc.putAdvisoryDetail(m, vulnID, pkgName, nestedBktNames, advisory)
+	return err
+}
+
+func (m *MemoryDB) PutAdvisoryGroup(_ *bolt.Tx, parentID, namespace, pkgName, vulnID string) error {
+	if err := m.put([]string{advisoryGroupBucket, parentID, namespace, pkgName}, vulnID, struct{}{}); err != nil {
+		return xerrors.Errorf("failed to put advisory group: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) UpdateAdvisory(namespace, pkgName, vulnID string, adv types.Advisory) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	vulnID = normalizeVulnID(vulnID)
+	adv.VulnerabilityID = ""
+	adv.DataSource = nil
+
+	bkt := m.root.bucket(namespace, pkgName)
+	if bkt == nil || bkt.values[vulnID] == nil {
+		return xerrors.Errorf("no advisory for %s in %s", vulnID, namespace)
+	}
+	if err := putJSON(m, []string{namespace, pkgName}, vulnID, canonicalize(adv)); err != nil {
+		return xerrors.Errorf("failed to update the advisory of %s: %w", pkgName, err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) GetAdvisoryGroup(string) ([]GroupedAdvisory, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *MemoryDB) DeleteAdvisoryDetailBucket() error {
+	return m.deleteBucket(advisoryDetailBucket)
+}
+
+func (m *MemoryDB) PutDataSource(_ *bolt.Tx, bktName string, source types.DataSource) error {
+	if err := m.put([]string{dataSourceBucket}, bktName, source); err != nil {
+		return xerrors.Errorf("failed to put data source: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) PutPackageAliases(_ *bolt.Tx, bktName string, aliases types.PackageAliases) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for pkgName, names := range aliases {
+		for _, alias := range names {
+			if alias == pkgName {
+				continue
+			}
+			if err := m.putPackageAlias(bktName, alias, pkgName); err != nil {
+				return xerrors.Errorf("failed to put the alias %s: %w", alias, err)
+			}
+			if err := m.putPackageAlias(bktName, pkgName, alias); err != nil {
+				return xerrors.Errorf("failed to put the alias %s: %w", pkgName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *MemoryDB) PutNamespaceAlias(_ *bolt.Tx, alias, namespace string) error {
+	if err := m.put([]string{namespaceAliasBucket}, alias, namespace); err != nil {
+		return xerrors.Errorf("failed to put the namespace alias %s: %w", alias, err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) putPackageAlias(bktName, name, alias string) error {
+	var existing []string
+	if b := m.root.bucket(packageAliasBucket, bktName); b != nil && b.values[name] != nil {
+		if err := json.Unmarshal(b.values[name], &existing); err != nil {
+			return xerrors.Errorf("JSON unmarshal error: %w", err)
+		}
+	}
+	return putJSON(m, []string{packageAliasBucket, bktName}, name, ustrings.Unique(append(existing, alias)))
+}
+
+func (m *MemoryDB) ListNamespaces() ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	uniq := map[string]struct{}{}
+	for name := range m.root.buckets {
+		if _, ok := internalBuckets[name]; !ok {
+			uniq[name] = struct{}{}
+		}
+	}
+	if b := m.root.bucket(dataSourceBucket); b != nil {
+		for k := range b.values {
+			uniq[k] = struct{}{}
+		}
+	}
+	if b := m.root.bucket(advisoryDetailBucket); b != nil {
+		for _, cveBucket := range b.buckets {
+			for ns := range cveBucket.buckets {
+				uniq[ns] = struct{}{}
+			}
+		}
+	}
+
+	var namespaces []string
+	for ns := range uniq {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+func (m *MemoryDB) Coverage() (map[string]CoverageStats, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *MemoryDB) GetAffectedPackages(string) ([]AffectedPackage, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *MemoryDB) RebuildIndexes() error {
+	return ErrUnsupported
+}
+
+func (m *MemoryDB) RenormalizeSeverities() error {
+	return ErrUnsupported
+}
+
+func (m *MemoryDB) MinimalFix(_, _, _ string) (string, error) {
+	return "", ErrUnsupported
+}
+
+func (m *MemoryDB) PutRedHatRepositories(_ *bolt.Tx, repository string, cpeIndices []int) error {
+	if err := m.put([]string{redhatCPERootBucket, redhatRepoBucket}, repository, cpeIndices); err != nil {
+		return xerrors.Errorf("Red Hat CPE error: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) PutRedHatNVRs(_ *bolt.Tx, nvr string, cpeIndices []int) error {
+	if err := m.put([]string{redhatCPERootBucket, redhatNVRBucket}, nvr, cpeIndices); err != nil {
+		return xerrors.Errorf("Red Hat CPE error: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) PutRedHatCPEs(_ *bolt.Tx, cpeIndex int, cpe string) error {
+	if err := m.put([]string{redhatCPERootBucket, redhatCPEBucket}, fmt.Sprint(cpeIndex), cpe); err != nil {
+		return xerrors.Errorf("Red Hat CPE error: %w", err)
+	}
+	return nil
+}
+
+func (m *MemoryDB) RedHatRepoToCPEs(repository string) ([]int, error) {
+	return m.getCPEs(redhatRepoBucket, repository)
+}
+
+func (m *MemoryDB) RedHatNVRToCPEs(nvr string) ([]int, error) {
+	return m.getCPEs(redhatNVRBucket, nvr)
+}
+
+func (m *MemoryDB) getCPEs(bucket, key string) ([]int, error) {
+	value := m.get([]string{redhatCPERootBucket, bucket}, key)
+	if len(value) == 0 {
+		return nil, nil
+	}
+	var cpes []int
+	if err := json.Unmarshal(value, &cpes); err != nil {
+		return nil, xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+	return cpes, nil
+}
+
+var _ Operation = (*MemoryDB)(nil)
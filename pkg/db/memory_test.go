@@ -0,0 +1,180 @@
+package db_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestMemoryDB(t *testing.T) {
+	mdb := db.NewMemoryDB(db.Config{})
+	err := mdb.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := mdb.PutDataSource(tx, "rpm::Example", types.DataSource{ID: "example"}); err != nil {
+			return err
+		}
+		if err := mdb.PutAdvisoryDetail(tx, "CVE-2021-22930", "nodejs", []string{"rpm::Example"},
+			types.Advisory{FixedVersion: "14.17.4"}); err != nil {
+			return err
+		}
+		if err := mdb.PutPackageAliases(tx, "rpm::Example", types.PackageAliases{"nodejs": {"node"}}); err != nil {
+			return err
+		}
+		return mdb.PutVulnerabilityID(tx, "CVE-2021-22930")
+	})
+	require.NoError(t, err)
+
+	// Namespaces in advisory-detail are listed before optimization
+	namespaces, err := mdb.ListNamespaces()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rpm::Example"}, namespaces)
+
+	err = mdb.ForEachVulnerabilityID(func(tx *bolt.Tx, vulnID string) error {
+		return mdb.SaveAdvisoryDetails(tx, vulnID)
+	})
+	require.NoError(t, err)
+	require.NoError(t, mdb.DeleteAdvisoryDetailBucket())
+	assert.Error(t, mdb.DeleteAdvisoryDetailBucket())
+
+	// Found by the alias
+	r, err := mdb.WithNamespace("rpm::")
+	require.NoError(t, err)
+	defer r.Close()
+
+	got, err := r.GetAdvisories("node")
+	require.NoError(t, err)
+	assert.Equal(t, []types.Advisory{
+		{
+			VulnerabilityID: "CVE-2021-22930",
+			FixedVersion:    "14.17.4",
+			DataSource:      &types.DataSource{ID: "example"},
+		},
+	}, got)
+
+	err = mdb.RebuildIndexes()
+	assert.ErrorIs(t, err, db.ErrUnsupported)
+}
+
+func TestMemoryDB_SameAsConfig(t *testing.T) {
+	write := func(op db.Operation) error {
+		err := op.BatchUpdate(func(tx *bolt.Tx) error {
+			advisories := []struct {
+				vulnID string
+				adv    types.Advisory
+			}{
+				{vulnID: "CVE-2021-0001", adv: types.Advisory{VulnerableVersions: []string{">=2.0, <2.1", ">=1.0, <1.1"}}},
+				{vulnID: "CVE-2021-0001", adv: types.Advisory{VulnerableVersions: []string{">=3.0, <3.1"}}},
+				{vulnID: "cve-2021-0002", adv: types.Advisory{FixedVersion: "1.0.0"}},
+				{vulnID: "CVE-2021-0003", adv: types.Advisory{FixedVersion: "2.0.0"}},
+			}
+			for _, a := range advisories {
+				if err := op.PutAdvisoryDetail(tx, a.vulnID, "example", []string{"npm::Example"}, a.adv); err != nil {
+					return err
+				}
+				if err := op.PutVulnerabilityID(tx, a.vulnID); err != nil {
+					return err
+				}
+			}
+			return op.PutVulnerability(tx, "CVE-2021-0003", types.Vulnerability{Disputed: true})
+		})
+		if err != nil {
+			return err
+		}
+		return op.ForEachVulnerabilityID(func(tx *bolt.Tx, vulnID string) error {
+			return op.SaveAdvisoryDetails(tx, vulnID)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		dbc     db.Config
+		wantErr string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "filtered",
+			dbc:  db.Config{ExcludeIDs: []string{"CVE-2021-0002"}},
+		},
+		{
+			name: "keep first",
+			dbc:  db.Config{AdvisoryMergeStrategy: db.MergeKeepFirst},
+		},
+		{
+			name: "union ranges",
+			dbc:  db.Config{AdvisoryMergeStrategy: db.MergeUnionRanges},
+		},
+		{
+			name: "disputed included",
+			dbc:  db.Config{IncludeDisputed: true},
+		},
+		{
+			name:    "too many advisories",
+			dbc:     db.Config{MaxAdvisoriesPerPackage: 2},
+			wantErr: "too many advisories for npm::Example/example (more than 2)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, db.Init(t.TempDir()))
+			defer db.Close()
+
+			mdb := db.NewMemoryDB(tt.dbc)
+			for _, op := range []db.Operation{tt.dbc, mdb} {
+				err := write(op)
+				if tt.wantErr != "" {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), tt.wantErr)
+					continue
+				}
+				require.NoError(t, err)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+
+			want, err := tt.dbc.GetAdvisories("npm::Example", "example")
+			require.NoError(t, err)
+			require.NotEmpty(t, want)
+			got, err := mdb.GetAdvisories("npm::Example", "example")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, want, got)
+		})
+	}
+}
+
+func TestMemoryDB_Concurrent(t *testing.T) {
+	mdb := db.NewMemoryDB(db.Config{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			vulnID := fmt.Sprintf("CVE-2021-%04d", i)
+			assert.NoError(t, mdb.PutVulnerabilityID(nil, vulnID))
+			assert.NoError(t, mdb.PutDataSource(nil, vulnID, types.DataSource{ID: "example"}))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := mdb.ListNamespaces()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	var got []string
+	err := mdb.ForEachVulnerabilityID(func(_ *bolt.Tx, vulnID string) error {
+		got = append(got, vulnID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, got, 4)
+}
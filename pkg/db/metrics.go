@@ -0,0 +1,167 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+const (
+	// MetricAdvisoriesWritten counts the advisories written per data source
+	MetricAdvisoriesWritten = "trivy_db_advisories_written_total"
+
+	// MetricVulnerabilitiesWritten counts the vulnerability details written per data source
+	MetricVulnerabilitiesWritten = "trivy_db_vulnerabilities_written_total"
+
+	// MetricSourceDuration is the time taken to update each data source in seconds
+	MetricSourceDuration = "trivy_db_source_update_duration_seconds"
+
+	// MetricBuildDuration is the time taken by the last build in seconds
+	MetricBuildDuration = "trivy_db_build_duration_seconds"
+)
+
+// MetricsRegistry receives the metrics of the build, e.g. an adapter of a Prometheus registry.
+// Implementations must be safe for concurrent use.
+type MetricsRegistry interface {
+	AddCounter(name string, labels map[string]string, delta float64)
+	SetGauge(name string, labels map[string]string, value float64)
+}
+
+// InProcessMetrics is a MetricsRegistry kept in the memory of the process.
+// It serves the metrics in the Prometheus text format as an http.Handler.
+type InProcessMetrics struct {
+	mu       sync.Mutex
+	counters map[string]float64
+	gauges   map[string]float64
+}
+
+func NewInProcessMetrics() *InProcessMetrics {
+	return &InProcessMetrics{
+		counters: map[string]float64{},
+		gauges:   map[string]float64{},
+	}
+}
+
+func (m *InProcessMetrics) AddCounter(name string, labels map[string]string, delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[metricKey(name, labels)] += delta
+}
+
+func (m *InProcessMetrics) SetGauge(name string, labels map[string]string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gauges[metricKey(name, labels)] = value
+}
+
+// Counter returns the value of the counter with the labels
+func (m *InProcessMetrics) Counter(name string, labels map[string]string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[metricKey(name, labels)]
+}
+
+// Gauge returns the value of the gauge with the labels
+func (m *InProcessMetrics) Gauge(name string, labels map[string]string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.gauges[metricKey(name, labels)]
+}
+
+// WriteTo writes the metrics in the Prometheus text format, sorted by name and labels
+func (m *InProcessMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	type family struct {
+		typ     string
+		samples []string
+	}
+	families := map[string]*family{}
+	for typ, values := range map[string]map[string]float64{"counter": m.counters, "gauge": m.gauges} {
+		for key, value := range values {
+			name := key
+			if i := strings.Index(key, "{"); i >= 0 {
+				name = key[:i]
+			}
+			if families[name] == nil {
+				families[name] = &family{typ: typ}
+			}
+			families[name].samples = append(families[name].samples, fmt.Sprintf("%s %g", key, value))
+		}
+	}
+
+	var names []string
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		f := families[name]
+		sort.Strings(f.samples)
+		fmt.Fprintf(&sb, "# TYPE %s %s\n", name, f.typ)
+		for _, sample := range f.samples {
+			sb.WriteString(sample + "\n")
+		}
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	if err != nil {
+		return int64(n), xerrors.Errorf("write error: %w", err)
+	}
+	return int64(n), nil
+}
+
+func (m *InProcessMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = m.WriteTo(w)
+}
+
+// metricKey returns the sample name with the sorted labels, e.g. `name{source="nvd"}`
+func metricKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	var keys []string
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// countAdvisory increments the advisories written by the data source registered for the namespace.
+// The namespace is used as the label if no data source is registered.
+func (dbc Config) countAdvisory(tx *bolt.Tx, namespace string) {
+	if dbc.MetricsRegistry == nil {
+		return
+	}
+	source := namespace
+	if ds, err := dbc.getDataSource(tx, namespace); err == nil && ds.ID != "" {
+		source = string(ds.ID)
+	}
+	dbc.MetricsRegistry.AddCounter(MetricAdvisoriesWritten, map[string]string{"source": source}, 1)
+}
+
+// countVulnerability increments the vulnerability details written by the data source
+func (dbc Config) countVulnerability(source types.SourceID) {
+	if dbc.MetricsRegistry == nil {
+		return
+	}
+	dbc.MetricsRegistry.AddCounter(MetricVulnerabilitiesWritten, map[string]string{"source": string(source)}, 1)
+}
@@ -0,0 +1,31 @@
+package db_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+)
+
+func TestInProcessMetrics_ServeHTTP(t *testing.T) {
+	m := db.NewInProcessMetrics()
+	m.AddCounter(db.MetricAdvisoriesWritten, map[string]string{"source": "nvd"}, 2)
+	m.AddCounter(db.MetricAdvisoriesWritten, map[string]string{"source": "alpine"}, 1)
+	m.AddCounter(db.MetricAdvisoriesWritten, map[string]string{"source": "nvd"}, 1)
+	m.SetGauge(db.MetricBuildDuration, nil, 12.5)
+	m.SetGauge(db.MetricBuildDuration, nil, 1.5)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	want := `# TYPE trivy_db_advisories_written_total counter
+trivy_db_advisories_written_total{source="alpine"} 1
+trivy_db_advisories_written_total{source="nvd"} 3
+# TYPE trivy_db_build_duration_seconds gauge
+trivy_db_build_duration_seconds 1.5
+`
+	assert.Equal(t, want, rec.Body.String())
+	assert.Equal(t, "text/plain; version=0.0.4", rec.Header().Get("Content-Type"))
+}
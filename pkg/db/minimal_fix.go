@@ -0,0 +1,61 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// e.g. "1.5.2", ">=1.5.2", "^1.5.2" and ">= 1.5.2, < 2.0.0". Constraints starting with "<" have no lower bound.
+var fixVersionRegexp = regexp.MustCompile(`^(?:>=|>|==|=|\^|~)?\s*([^\s,<>=!~^]+)`)
+
+// MinimalFix returns the lowest fixed version above the installed version of the package in the namespace,
+// e.g. "1.5.2" for the installed 1.4.0 and the patched versions "1.5.2" and "2.0.1", to advise "upgrade to 1.5.2".
+// The lower bound of each PatchedVersions constraint and FixedVersion are candidates.
+// Versions are compared with VersionComparer.
+// An empty string is returned if no fix is above the installed version.
+func (dbc Config) MinimalFix(namespace, pkgName, installed string) (string, error) {
+	if dbc.VersionComparer == nil {
+		return "", xerrors.New("no version comparer")
+	}
+
+	advisories, err := dbc.GetAdvisories(namespace, pkgName)
+	if err != nil {
+		return "", xerrors.Errorf("failed to get the minimal fix: %w", err)
+	}
+
+	var minimal string
+	for _, adv := range advisories {
+		for _, fixed := range fixedVersions(adv) {
+			if dbc.VersionComparer(namespace, fixed, installed) <= 0 {
+				continue
+			}
+			if minimal == "" || dbc.VersionComparer(namespace, fixed, minimal) < 0 {
+				minimal = fixed
+			}
+		}
+	}
+	return minimal, nil
+}
+
+// fixedVersions returns FixedVersion and the lower bounds of PatchedVersions, e.g. "1.2.6" of ">=1.2.6"
+func fixedVersions(adv types.Advisory) []string {
+	var versions []string
+	if adv.FixedVersion != "" {
+		versions = append(versions, adv.FixedVersion)
+	}
+	for _, patched := range adv.PatchedVersions {
+		// e.g. ">=1.2.6 <2.0.0 || >=2.1.1"
+		for _, c := range strings.Split(patched, "||") {
+			m := fixVersionRegexp.FindStringSubmatch(strings.TrimSpace(c))
+			if m == nil || m[1] == types.AllVersions {
+				continue
+			}
+			versions = append(versions, m[1])
+		}
+	}
+	return versions
+}
@@ -0,0 +1,88 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+func TestConfig_MinimalFix(t *testing.T) {
+	tests := []struct {
+		name      string
+		dbc       db.Config
+		namespace string
+		pkgName   string
+		installed string
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "lowest fix above the installed version",
+			dbc:       db.Config{VersionComparer: vulnerability.CompareInNamespace},
+			namespace: "npm::Node.js Ecosystem Security Working Group",
+			pkgName:   "example",
+			installed: "1.4.0",
+			want:      "1.5.2",
+		},
+		{
+			name:      "versions compared in semver",
+			dbc:       db.Config{VersionComparer: vulnerability.CompareInNamespace},
+			namespace: "npm::Node.js Ecosystem Security Working Group",
+			pkgName:   "example",
+			installed: "1.6.0",
+			want:      "1.10.0",
+		},
+		{
+			name:      "no fix above the installed version",
+			dbc:       db.Config{VersionComparer: vulnerability.CompareInNamespace},
+			namespace: "npm::Node.js Ecosystem Security Working Group",
+			pkgName:   "example",
+			installed: "2.1.0",
+			want:      "",
+		},
+		{
+			name:      "fixed version of OS packages",
+			dbc:       db.Config{VersionComparer: vulnerability.CompareInNamespace},
+			namespace: "debian 10",
+			pkgName:   "openssl",
+			installed: "1.1.1d-0+deb10u5",
+			want:      "1.1.1d-0+deb10u6",
+		},
+		{
+			name:      "no such package",
+			dbc:       db.Config{VersionComparer: vulnerability.CompareInNamespace},
+			namespace: "debian 10",
+			pkgName:   "curl",
+			installed: "7.64.0-4",
+			want:      "",
+		},
+		{
+			name:      "no version comparer",
+			dbc:       db.Config{},
+			namespace: "debian 10",
+			pkgName:   "openssl",
+			installed: "1.1.1d-0+deb10u5",
+			wantErr:   "no version comparer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/minimal-fix.yaml"})
+			defer db.Close()
+
+			got, err := tt.dbc.MinimalFix(tt.namespace, tt.pkgName, tt.installed)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
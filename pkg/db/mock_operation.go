@@ -6,6 +6,7 @@ import (
 	types "github.com/aquasecurity/trivy-db/pkg/types"
 	mock "github.com/stretchr/testify/mock"
 	bbolt "go.etcd.io/bbolt"
+	io "io"
 )
 
 // MockOperation is an autogenerated mock type for the Operation type
@@ -57,6 +58,101 @@ func (_m *MockOperation) BatchUpdate(fn func(*bbolt.Tx) error) error {
 	return r0
 }
 
+type OperationCanonicalIDArgs struct {
+	VulnID         string
+	VulnIDAnything bool
+}
+
+type OperationCanonicalIDReturns struct {
+	CanonicalID string
+	Err         error
+}
+
+type OperationCanonicalIDExpectation struct {
+	Args    OperationCanonicalIDArgs
+	Returns OperationCanonicalIDReturns
+}
+
+func (_m *MockOperation) ApplyCanonicalIDExpectation(e OperationCanonicalIDExpectation) {
+	var args []interface{}
+	if e.Args.VulnIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.VulnID)
+	}
+	_m.On("CanonicalID", args...).Return(e.Returns.CanonicalID, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyCanonicalIDExpectations(expectations []OperationCanonicalIDExpectation) {
+	for _, e := range expectations {
+		_m.ApplyCanonicalIDExpectation(e)
+	}
+}
+
+// CanonicalID provides a mock function with given fields: vulnID
+func (_m *MockOperation) CanonicalID(vulnID string) (string, error) {
+	ret := _m.Called(vulnID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(vulnID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(vulnID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationCoverageReturns struct {
+	Stats map[string]CoverageStats
+	Err   error
+}
+
+type OperationCoverageExpectation struct {
+	Returns OperationCoverageReturns
+}
+
+func (_m *MockOperation) ApplyCoverageExpectation(e OperationCoverageExpectation) {
+	var args []interface{}
+	_m.On("Coverage", args...).Return(e.Returns.Stats, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyCoverageExpectations(expectations []OperationCoverageExpectation) {
+	for _, e := range expectations {
+		_m.ApplyCoverageExpectation(e)
+	}
+}
+
+// Coverage provides a mock function with given fields:
+func (_m *MockOperation) Coverage() (map[string]CoverageStats, error) {
+	ret := _m.Called()
+
+	var r0 map[string]CoverageStats
+	if rf, ok := ret.Get(0).(func() map[string]CoverageStats); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]CoverageStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type OperationDeleteAdvisoryDetailBucketReturns struct {
 	_a0 error
 }
@@ -123,6 +219,206 @@ func (_m *MockOperation) DeleteVulnerabilityDetailBucket() error {
 	return r0
 }
 
+type OperationExportOSVArgs struct {
+	Dir         string
+	DirAnything bool
+}
+
+type OperationExportOSVReturns struct {
+	Err error
+}
+
+type OperationExportOSVExpectation struct {
+	Args    OperationExportOSVArgs
+	Returns OperationExportOSVReturns
+}
+
+func (_m *MockOperation) ApplyExportOSVExpectation(e OperationExportOSVExpectation) {
+	var args []interface{}
+	if e.Args.DirAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Dir)
+	}
+	_m.On("ExportOSV", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyExportOSVExpectations(expectations []OperationExportOSVExpectation) {
+	for _, e := range expectations {
+		_m.ApplyExportOSVExpectation(e)
+	}
+}
+
+// ExportOSV provides a mock function with given fields: dir
+func (_m *MockOperation) ExportOSV(dir string) error {
+	ret := _m.Called(dir)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(dir)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type OperationExportVEXArgs struct {
+	Components         []Component
+	ComponentsAnything bool
+	W                  io.Writer
+	WAnything          bool
+}
+
+type OperationExportVEXReturns struct {
+	Err error
+}
+
+type OperationExportVEXExpectation struct {
+	Args    OperationExportVEXArgs
+	Returns OperationExportVEXReturns
+}
+
+func (_m *MockOperation) ApplyExportVEXExpectation(e OperationExportVEXExpectation) {
+	var args []interface{}
+	if e.Args.ComponentsAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Components)
+	}
+	if e.Args.WAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.W)
+	}
+	_m.On("ExportVEX", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyExportVEXExpectations(expectations []OperationExportVEXExpectation) {
+	for _, e := range expectations {
+		_m.ApplyExportVEXExpectation(e)
+	}
+}
+
+// ExportVEX provides a mock function with given fields: components, w
+func (_m *MockOperation) ExportVEX(components []Component, w io.Writer) error {
+	ret := _m.Called(components, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]Component, io.Writer) error); ok {
+		r0 = rf(components, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type OperationExtractSourceArgs struct {
+	Name            string
+	NameAnything    bool
+	DstPath         string
+	DstPathAnything bool
+}
+
+type OperationExtractSourceReturns struct {
+	Err error
+}
+
+type OperationExtractSourceExpectation struct {
+	Args    OperationExtractSourceArgs
+	Returns OperationExtractSourceReturns
+}
+
+func (_m *MockOperation) ApplyExtractSourceExpectation(e OperationExtractSourceExpectation) {
+	var args []interface{}
+	if e.Args.NameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Name)
+	}
+	if e.Args.DstPathAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.DstPath)
+	}
+	_m.On("ExtractSource", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyExtractSourceExpectations(expectations []OperationExtractSourceExpectation) {
+	for _, e := range expectations {
+		_m.ApplyExtractSourceExpectation(e)
+	}
+}
+
+// ExtractSource provides a mock function with given fields: name, dstPath
+func (_m *MockOperation) ExtractSource(name string, dstPath string) error {
+	ret := _m.Called(name, dstPath)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(name, dstPath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type OperationFindByPackageArgs struct {
+	Name         string
+	NameAnything bool
+}
+
+type OperationFindByPackageReturns struct {
+	Advisories map[string][]types.Advisory
+	Err        error
+}
+
+type OperationFindByPackageExpectation struct {
+	Args    OperationFindByPackageArgs
+	Returns OperationFindByPackageReturns
+}
+
+func (_m *MockOperation) ApplyFindByPackageExpectation(e OperationFindByPackageExpectation) {
+	var args []interface{}
+	if e.Args.NameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Name)
+	}
+	_m.On("FindByPackage", args...).Return(e.Returns.Advisories, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyFindByPackageExpectations(expectations []OperationFindByPackageExpectation) {
+	for _, e := range expectations {
+		_m.ApplyFindByPackageExpectation(e)
+	}
+}
+
+// FindByPackage provides a mock function with given fields: name
+func (_m *MockOperation) FindByPackage(name string) (map[string][]types.Advisory, error) {
+	ret := _m.Called(name)
+
+	var r0 map[string][]types.Advisory
+	if rf, ok := ret.Get(0).(func(string) map[string][]types.Advisory); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]types.Advisory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type OperationForEachAdvisoryArgs struct {
 	Sources         []string
 	SourcesAnything bool
@@ -289,51 +585,53 @@ func (_m *MockOperation) GetAdvisories(source string, pkgName string) ([]types.A
 	return r0, r1
 }
 
-type OperationGetVulnerabilityArgs struct {
-	VulnerabilityID         string
-	VulnerabilityIDAnything bool
+type OperationGetAdvisoriesByPURLArgs struct {
+	Purl         string
+	PurlAnything bool
 }
 
-type OperationGetVulnerabilityReturns struct {
-	Vulnerability types.Vulnerability
-	Err           error
+type OperationGetAdvisoriesByPURLReturns struct {
+	Advisories []types.Advisory
+	Err        error
 }
 
-type OperationGetVulnerabilityExpectation struct {
-	Args    OperationGetVulnerabilityArgs
-	Returns OperationGetVulnerabilityReturns
+type OperationGetAdvisoriesByPURLExpectation struct {
+	Args    OperationGetAdvisoriesByPURLArgs
+	Returns OperationGetAdvisoriesByPURLReturns
 }
 
-func (_m *MockOperation) ApplyGetVulnerabilityExpectation(e OperationGetVulnerabilityExpectation) {
+func (_m *MockOperation) ApplyGetAdvisoriesByPURLExpectation(e OperationGetAdvisoriesByPURLExpectation) {
 	var args []interface{}
-	if e.Args.VulnerabilityIDAnything {
+	if e.Args.PurlAnything {
 		args = append(args, mock.Anything)
 	} else {
-		args = append(args, e.Args.VulnerabilityID)
+		args = append(args, e.Args.Purl)
 	}
-	_m.On("GetVulnerability", args...).Return(e.Returns.Vulnerability, e.Returns.Err)
+	_m.On("GetAdvisoriesByPURL", args...).Return(e.Returns.Advisories, e.Returns.Err)
 }
 
-func (_m *MockOperation) ApplyGetVulnerabilityExpectations(expectations []OperationGetVulnerabilityExpectation) {
+func (_m *MockOperation) ApplyGetAdvisoriesByPURLExpectations(expectations []OperationGetAdvisoriesByPURLExpectation) {
 	for _, e := range expectations {
-		_m.ApplyGetVulnerabilityExpectation(e)
+		_m.ApplyGetAdvisoriesByPURLExpectation(e)
 	}
 }
 
-// GetVulnerability provides a mock function with given fields: vulnerabilityID
-func (_m *MockOperation) GetVulnerability(vulnerabilityID string) (types.Vulnerability, error) {
-	ret := _m.Called(vulnerabilityID)
+// GetAdvisoriesByPURL provides a mock function with given fields: purl
+func (_m *MockOperation) GetAdvisoriesByPURL(purl string) ([]types.Advisory, error) {
+	ret := _m.Called(purl)
 
-	var r0 types.Vulnerability
-	if rf, ok := ret.Get(0).(func(string) types.Vulnerability); ok {
-		r0 = rf(vulnerabilityID)
+	var r0 []types.Advisory
+	if rf, ok := ret.Get(0).(func(string) []types.Advisory); ok {
+		r0 = rf(purl)
 	} else {
-		r0 = ret.Get(0).(types.Vulnerability)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Advisory)
+		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(vulnerabilityID)
+		r1 = rf(purl)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -341,53 +639,60 @@ func (_m *MockOperation) GetVulnerability(vulnerabilityID string) (types.Vulnera
 	return r0, r1
 }
 
-type OperationGetVulnerabilityDetailArgs struct {
-	CveID         string
-	CveIDAnything bool
+type OperationGetAdvisoriesBySeverityArgs struct {
+	Namespace         string
+	NamespaceAnything bool
+	Min               types.Severity
+	MinAnything       bool
 }
 
-type OperationGetVulnerabilityDetailReturns struct {
-	Detail map[types.SourceID]types.VulnerabilityDetail
-	Err    error
+type OperationGetAdvisoriesBySeverityReturns struct {
+	Advisories []AdvisoryWithDetail
+	Err        error
 }
 
-type OperationGetVulnerabilityDetailExpectation struct {
-	Args    OperationGetVulnerabilityDetailArgs
-	Returns OperationGetVulnerabilityDetailReturns
+type OperationGetAdvisoriesBySeverityExpectation struct {
+	Args    OperationGetAdvisoriesBySeverityArgs
+	Returns OperationGetAdvisoriesBySeverityReturns
 }
 
-func (_m *MockOperation) ApplyGetVulnerabilityDetailExpectation(e OperationGetVulnerabilityDetailExpectation) {
+func (_m *MockOperation) ApplyGetAdvisoriesBySeverityExpectation(e OperationGetAdvisoriesBySeverityExpectation) {
 	var args []interface{}
-	if e.Args.CveIDAnything {
+	if e.Args.NamespaceAnything {
 		args = append(args, mock.Anything)
 	} else {
-		args = append(args, e.Args.CveID)
+		args = append(args, e.Args.Namespace)
 	}
-	_m.On("GetVulnerabilityDetail", args...).Return(e.Returns.Detail, e.Returns.Err)
+	if e.Args.MinAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Min)
+	}
+	_m.On("GetAdvisoriesBySeverity", args...).Return(e.Returns.Advisories, e.Returns.Err)
 }
 
-func (_m *MockOperation) ApplyGetVulnerabilityDetailExpectations(expectations []OperationGetVulnerabilityDetailExpectation) {
+func (_m *MockOperation) ApplyGetAdvisoriesBySeverityExpectations(expectations []OperationGetAdvisoriesBySeverityExpectation) {
 	for _, e := range expectations {
-		_m.ApplyGetVulnerabilityDetailExpectation(e)
+		_m.ApplyGetAdvisoriesBySeverityExpectation(e)
 	}
 }
 
-// GetVulnerabilityDetail provides a mock function with given fields: cveID
-func (_m *MockOperation) GetVulnerabilityDetail(cveID string) (map[types.SourceID]types.VulnerabilityDetail, error) {
-	ret := _m.Called(cveID)
+// GetAdvisoriesBySeverity provides a mock function with given fields: namespace, min
+func (_m *MockOperation) GetAdvisoriesBySeverity(namespace string, min types.Severity) ([]AdvisoryWithDetail, error) {
+	ret := _m.Called(namespace, min)
 
-	var r0 map[types.SourceID]types.VulnerabilityDetail
-	if rf, ok := ret.Get(0).(func(string) map[types.SourceID]types.VulnerabilityDetail); ok {
-		r0 = rf(cveID)
+	var r0 []AdvisoryWithDetail
+	if rf, ok := ret.Get(0).(func(string, types.Severity) []AdvisoryWithDetail); ok {
+		r0 = rf(namespace, min)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[types.SourceID]types.VulnerabilityDetail)
+			r0 = ret.Get(0).([]AdvisoryWithDetail)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(cveID)
+	if rf, ok := ret.Get(1).(func(string, types.Severity) error); ok {
+		r1 = rf(namespace, min)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -395,34 +700,738 @@ func (_m *MockOperation) GetVulnerabilityDetail(cveID string) (map[types.SourceI
 	return r0, r1
 }
 
-type OperationPutAdvisoryDetailArgs struct {
-	Tx                      *bbolt.Tx
-	TxAnything              bool
-	VulnerabilityID         string
-	VulnerabilityIDAnything bool
-	PkgName                 string
-	PkgNameAnything         bool
-	NestedBktNames          []string
-	NestedBktNamesAnything  bool
-	Advisory                interface{}
-	AdvisoryAnything        bool
+type OperationGetAdvisoriesPagedArgs struct {
+	Source          string
+	SourceAnything  bool
+	PkgName         string
+	PkgNameAnything bool
+	Offset          int
+	OffsetAnything  bool
+	Limit           int
+	LimitAnything   bool
 }
 
-type OperationPutAdvisoryDetailReturns struct {
-	Err error
+type OperationGetAdvisoriesPagedReturns struct {
+	Advisories []types.Advisory
+	Total      int
+	Err        error
 }
 
-type OperationPutAdvisoryDetailExpectation struct {
-	Args    OperationPutAdvisoryDetailArgs
-	Returns OperationPutAdvisoryDetailReturns
+type OperationGetAdvisoriesPagedExpectation struct {
+	Args    OperationGetAdvisoriesPagedArgs
+	Returns OperationGetAdvisoriesPagedReturns
 }
 
-func (_m *MockOperation) ApplyPutAdvisoryDetailExpectation(e OperationPutAdvisoryDetailExpectation) {
+func (_m *MockOperation) ApplyGetAdvisoriesPagedExpectation(e OperationGetAdvisoriesPagedExpectation) {
 	var args []interface{}
-	if e.Args.TxAnything {
+	if e.Args.SourceAnything {
 		args = append(args, mock.Anything)
 	} else {
-		args = append(args, e.Args.Tx)
+		args = append(args, e.Args.Source)
+	}
+	if e.Args.PkgNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.PkgName)
+	}
+	if e.Args.OffsetAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Offset)
+	}
+	if e.Args.LimitAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Limit)
+	}
+	_m.On("GetAdvisoriesPaged", args...).Return(e.Returns.Advisories, e.Returns.Total, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetAdvisoriesPagedExpectations(expectations []OperationGetAdvisoriesPagedExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetAdvisoriesPagedExpectation(e)
+	}
+}
+
+// GetAdvisoriesPaged provides a mock function with given fields: source, pkgName, offset, limit
+func (_m *MockOperation) GetAdvisoriesPaged(source string, pkgName string, offset int, limit int) ([]types.Advisory, int, error) {
+	ret := _m.Called(source, pkgName, offset, limit)
+
+	var r0 []types.Advisory
+	if rf, ok := ret.Get(0).(func(string, string, int, int) []types.Advisory); ok {
+		r0 = rf(source, pkgName, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Advisory)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, string, int, int) int); ok {
+		r1 = rf(source, pkgName, offset, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, string, int, int) error); ok {
+		r2 = rf(source, pkgName, offset, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+type OperationGetAdvisoryGroupArgs struct {
+	Id         string
+	IdAnything bool
+}
+
+type OperationGetAdvisoryGroupReturns struct {
+	Advisories []GroupedAdvisory
+	Err        error
+}
+
+type OperationGetAdvisoryGroupExpectation struct {
+	Args    OperationGetAdvisoryGroupArgs
+	Returns OperationGetAdvisoryGroupReturns
+}
+
+func (_m *MockOperation) ApplyGetAdvisoryGroupExpectation(e OperationGetAdvisoryGroupExpectation) {
+	var args []interface{}
+	if e.Args.IdAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Id)
+	}
+	_m.On("GetAdvisoryGroup", args...).Return(e.Returns.Advisories, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetAdvisoryGroupExpectations(expectations []OperationGetAdvisoryGroupExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetAdvisoryGroupExpectation(e)
+	}
+}
+
+// GetAdvisoryGroup provides a mock function with given fields: id
+func (_m *MockOperation) GetAdvisoryGroup(id string) ([]GroupedAdvisory, error) {
+	ret := _m.Called(id)
+
+	var r0 []GroupedAdvisory
+	if rf, ok := ret.Get(0).(func(string) []GroupedAdvisory); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]GroupedAdvisory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationGetAffectedPackagesArgs struct {
+	VulnID         string
+	VulnIDAnything bool
+}
+
+type OperationGetAffectedPackagesReturns struct {
+	Pkgs []AffectedPackage
+	Err  error
+}
+
+type OperationGetAffectedPackagesExpectation struct {
+	Args    OperationGetAffectedPackagesArgs
+	Returns OperationGetAffectedPackagesReturns
+}
+
+func (_m *MockOperation) ApplyGetAffectedPackagesExpectation(e OperationGetAffectedPackagesExpectation) {
+	var args []interface{}
+	if e.Args.VulnIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.VulnID)
+	}
+	_m.On("GetAffectedPackages", args...).Return(e.Returns.Pkgs, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetAffectedPackagesExpectations(expectations []OperationGetAffectedPackagesExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetAffectedPackagesExpectation(e)
+	}
+}
+
+// GetAffectedPackages provides a mock function with given fields: vulnID
+func (_m *MockOperation) GetAffectedPackages(vulnID string) ([]AffectedPackage, error) {
+	ret := _m.Called(vulnID)
+
+	var r0 []AffectedPackage
+	if rf, ok := ret.Get(0).(func(string) []AffectedPackage); ok {
+		r0 = rf(vulnID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]AffectedPackage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(vulnID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationGetAllOSAdvisoriesArgs struct {
+	PkgName         string
+	PkgNameAnything bool
+}
+
+type OperationGetAllOSAdvisoriesReturns struct {
+	Advisories map[string][]types.Advisory
+	Err        error
+}
+
+type OperationGetAllOSAdvisoriesExpectation struct {
+	Args    OperationGetAllOSAdvisoriesArgs
+	Returns OperationGetAllOSAdvisoriesReturns
+}
+
+func (_m *MockOperation) ApplyGetAllOSAdvisoriesExpectation(e OperationGetAllOSAdvisoriesExpectation) {
+	var args []interface{}
+	if e.Args.PkgNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.PkgName)
+	}
+	_m.On("GetAllOSAdvisories", args...).Return(e.Returns.Advisories, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetAllOSAdvisoriesExpectations(expectations []OperationGetAllOSAdvisoriesExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetAllOSAdvisoriesExpectation(e)
+	}
+}
+
+// GetAllOSAdvisories provides a mock function with given fields: pkgName
+func (_m *MockOperation) GetAllOSAdvisories(pkgName string) (map[string][]types.Advisory, error) {
+	ret := _m.Called(pkgName)
+
+	var r0 map[string][]types.Advisory
+	if rf, ok := ret.Get(0).(func(string) map[string][]types.Advisory); ok {
+		r0 = rf(pkgName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]types.Advisory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(pkgName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationGetByCPEArgs struct {
+	Cpe         string
+	CpeAnything bool
+}
+
+type OperationGetByCPEReturns struct {
+	VulnIDs []string
+	Err     error
+}
+
+type OperationGetByCPEExpectation struct {
+	Args    OperationGetByCPEArgs
+	Returns OperationGetByCPEReturns
+}
+
+func (_m *MockOperation) ApplyGetByCPEExpectation(e OperationGetByCPEExpectation) {
+	var args []interface{}
+	if e.Args.CpeAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Cpe)
+	}
+	_m.On("GetByCPE", args...).Return(e.Returns.VulnIDs, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetByCPEExpectations(expectations []OperationGetByCPEExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetByCPEExpectation(e)
+	}
+}
+
+// GetByCPE provides a mock function with given fields: cpe
+func (_m *MockOperation) GetByCPE(cpe string) ([]string, error) {
+	ret := _m.Called(cpe)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(cpe)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(cpe)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationGetFixableAdvisoriesArgs struct {
+	Namespace         string
+	NamespaceAnything bool
+	PkgName           string
+	PkgNameAnything   bool
+}
+
+type OperationGetFixableAdvisoriesReturns struct {
+	Advisories []types.Advisory
+	Err        error
+}
+
+type OperationGetFixableAdvisoriesExpectation struct {
+	Args    OperationGetFixableAdvisoriesArgs
+	Returns OperationGetFixableAdvisoriesReturns
+}
+
+func (_m *MockOperation) ApplyGetFixableAdvisoriesExpectation(e OperationGetFixableAdvisoriesExpectation) {
+	var args []interface{}
+	if e.Args.NamespaceAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Namespace)
+	}
+	if e.Args.PkgNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.PkgName)
+	}
+	_m.On("GetFixableAdvisories", args...).Return(e.Returns.Advisories, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetFixableAdvisoriesExpectations(expectations []OperationGetFixableAdvisoriesExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetFixableAdvisoriesExpectation(e)
+	}
+}
+
+// GetFixableAdvisories provides a mock function with given fields: namespace, pkgName
+func (_m *MockOperation) GetFixableAdvisories(namespace string, pkgName string) ([]types.Advisory, error) {
+	ret := _m.Called(namespace, pkgName)
+
+	var r0 []types.Advisory
+	if rf, ok := ret.Get(0).(func(string, string) []types.Advisory); ok {
+		r0 = rf(namespace, pkgName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Advisory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, pkgName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationGetTaggedAdvisoriesArgs struct {
+	Namespace         string
+	NamespaceAnything bool
+	PkgName           string
+	PkgNameAnything   bool
+	Tag               string
+	TagAnything       bool
+}
+
+type OperationGetTaggedAdvisoriesReturns struct {
+	Advisories []types.Advisory
+	Err        error
+}
+
+type OperationGetTaggedAdvisoriesExpectation struct {
+	Args    OperationGetTaggedAdvisoriesArgs
+	Returns OperationGetTaggedAdvisoriesReturns
+}
+
+func (_m *MockOperation) ApplyGetTaggedAdvisoriesExpectation(e OperationGetTaggedAdvisoriesExpectation) {
+	var args []interface{}
+	if e.Args.NamespaceAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Namespace)
+	}
+	if e.Args.PkgNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.PkgName)
+	}
+	if e.Args.TagAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Tag)
+	}
+	_m.On("GetTaggedAdvisories", args...).Return(e.Returns.Advisories, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetTaggedAdvisoriesExpectations(expectations []OperationGetTaggedAdvisoriesExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetTaggedAdvisoriesExpectation(e)
+	}
+}
+
+// GetTaggedAdvisories provides a mock function with given fields: namespace, pkgName, tag
+func (_m *MockOperation) GetTaggedAdvisories(namespace string, pkgName string, tag string) ([]types.Advisory, error) {
+	ret := _m.Called(namespace, pkgName, tag)
+
+	var r0 []types.Advisory
+	if rf, ok := ret.Get(0).(func(string, string, string) []types.Advisory); ok {
+		r0 = rf(namespace, pkgName, tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Advisory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(namespace, pkgName, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationGetVulnerabilityArgs struct {
+	VulnerabilityID         string
+	VulnerabilityIDAnything bool
+}
+
+type OperationGetVulnerabilityReturns struct {
+	Vulnerability types.Vulnerability
+	Err           error
+}
+
+type OperationGetVulnerabilityExpectation struct {
+	Args    OperationGetVulnerabilityArgs
+	Returns OperationGetVulnerabilityReturns
+}
+
+func (_m *MockOperation) ApplyGetVulnerabilityExpectation(e OperationGetVulnerabilityExpectation) {
+	var args []interface{}
+	if e.Args.VulnerabilityIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.VulnerabilityID)
+	}
+	_m.On("GetVulnerability", args...).Return(e.Returns.Vulnerability, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetVulnerabilityExpectations(expectations []OperationGetVulnerabilityExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetVulnerabilityExpectation(e)
+	}
+}
+
+// GetVulnerability provides a mock function with given fields: vulnerabilityID
+func (_m *MockOperation) GetVulnerability(vulnerabilityID string) (types.Vulnerability, error) {
+	ret := _m.Called(vulnerabilityID)
+
+	var r0 types.Vulnerability
+	if rf, ok := ret.Get(0).(func(string) types.Vulnerability); ok {
+		r0 = rf(vulnerabilityID)
+	} else {
+		r0 = ret.Get(0).(types.Vulnerability)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(vulnerabilityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationGetVulnerabilityDetailArgs struct {
+	CveID         string
+	CveIDAnything bool
+}
+
+type OperationGetVulnerabilityDetailReturns struct {
+	Detail map[types.SourceID]types.VulnerabilityDetail
+	Err    error
+}
+
+type OperationGetVulnerabilityDetailExpectation struct {
+	Args    OperationGetVulnerabilityDetailArgs
+	Returns OperationGetVulnerabilityDetailReturns
+}
+
+func (_m *MockOperation) ApplyGetVulnerabilityDetailExpectation(e OperationGetVulnerabilityDetailExpectation) {
+	var args []interface{}
+	if e.Args.CveIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.CveID)
+	}
+	_m.On("GetVulnerabilityDetail", args...).Return(e.Returns.Detail, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetVulnerabilityDetailExpectations(expectations []OperationGetVulnerabilityDetailExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetVulnerabilityDetailExpectation(e)
+	}
+}
+
+// GetVulnerabilityDetail provides a mock function with given fields: cveID
+func (_m *MockOperation) GetVulnerabilityDetail(cveID string) (map[types.SourceID]types.VulnerabilityDetail, error) {
+	ret := _m.Called(cveID)
+
+	var r0 map[types.SourceID]types.VulnerabilityDetail
+	if rf, ok := ret.Get(0).(func(string) map[types.SourceID]types.VulnerabilityDetail); ok {
+		r0 = rf(cveID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[types.SourceID]types.VulnerabilityDetail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(cveID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationGetVulnerabilityFieldsArgs struct {
+	CveID          string
+	CveIDAnything  bool
+	Fields         []string
+	FieldsAnything bool
+}
+
+type OperationGetVulnerabilityFieldsReturns struct {
+	Detail map[types.SourceID]types.VulnerabilityDetail
+	Err    error
+}
+
+type OperationGetVulnerabilityFieldsExpectation struct {
+	Args    OperationGetVulnerabilityFieldsArgs
+	Returns OperationGetVulnerabilityFieldsReturns
+}
+
+func (_m *MockOperation) ApplyGetVulnerabilityFieldsExpectation(e OperationGetVulnerabilityFieldsExpectation) {
+	var args []interface{}
+	if e.Args.CveIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.CveID)
+	}
+	if e.Args.FieldsAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Fields)
+	}
+	_m.On("GetVulnerabilityFields", args...).Return(e.Returns.Detail, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyGetVulnerabilityFieldsExpectations(expectations []OperationGetVulnerabilityFieldsExpectation) {
+	for _, e := range expectations {
+		_m.ApplyGetVulnerabilityFieldsExpectation(e)
+	}
+}
+
+// GetVulnerabilityFields provides a mock function with given fields: cveID, fields
+func (_m *MockOperation) GetVulnerabilityFields(cveID string, fields []string) (map[types.SourceID]types.VulnerabilityDetail, error) {
+	ret := _m.Called(cveID, fields)
+
+	var r0 map[types.SourceID]types.VulnerabilityDetail
+	if rf, ok := ret.Get(0).(func(string, []string) map[types.SourceID]types.VulnerabilityDetail); ok {
+		r0 = rf(cveID, fields)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[types.SourceID]types.VulnerabilityDetail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []string) error); ok {
+		r1 = rf(cveID, fields)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationListNamespacesReturns struct {
+	Namespaces []string
+	Err        error
+}
+
+type OperationListNamespacesExpectation struct {
+	Returns OperationListNamespacesReturns
+}
+
+func (_m *MockOperation) ApplyListNamespacesExpectation(e OperationListNamespacesExpectation) {
+	var args []interface{}
+	_m.On("ListNamespaces", args...).Return(e.Returns.Namespaces, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyListNamespacesExpectations(expectations []OperationListNamespacesExpectation) {
+	for _, e := range expectations {
+		_m.ApplyListNamespacesExpectation(e)
+	}
+}
+
+// ListNamespaces provides a mock function with given fields:
+func (_m *MockOperation) ListNamespaces() ([]string, error) {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationMinimalFixArgs struct {
+	Namespace         string
+	NamespaceAnything bool
+	PkgName           string
+	PkgNameAnything   bool
+	Installed         string
+	InstalledAnything bool
+}
+
+type OperationMinimalFixReturns struct {
+	FixedVersion string
+	Err          error
+}
+
+type OperationMinimalFixExpectation struct {
+	Args    OperationMinimalFixArgs
+	Returns OperationMinimalFixReturns
+}
+
+func (_m *MockOperation) ApplyMinimalFixExpectation(e OperationMinimalFixExpectation) {
+	var args []interface{}
+	if e.Args.NamespaceAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Namespace)
+	}
+	if e.Args.PkgNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.PkgName)
+	}
+	if e.Args.InstalledAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Installed)
+	}
+	_m.On("MinimalFix", args...).Return(e.Returns.FixedVersion, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyMinimalFixExpectations(expectations []OperationMinimalFixExpectation) {
+	for _, e := range expectations {
+		_m.ApplyMinimalFixExpectation(e)
+	}
+}
+
+// MinimalFix provides a mock function with given fields: namespace, pkgName, installed
+func (_m *MockOperation) MinimalFix(namespace string, pkgName string, installed string) (string, error) {
+	ret := _m.Called(namespace, pkgName, installed)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(namespace, pkgName, installed)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(namespace, pkgName, installed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationPutAdvisoryDetailArgs struct {
+	Tx                      *bbolt.Tx
+	TxAnything              bool
+	VulnerabilityID         string
+	VulnerabilityIDAnything bool
+	PkgName                 string
+	PkgNameAnything         bool
+	NestedBktNames          []string
+	NestedBktNamesAnything  bool
+	Advisory                interface{}
+	AdvisoryAnything        bool
+}
+
+type OperationPutAdvisoryDetailReturns struct {
+	Err error
+}
+
+type OperationPutAdvisoryDetailExpectation struct {
+	Args    OperationPutAdvisoryDetailArgs
+	Returns OperationPutAdvisoryDetailReturns
+}
+
+func (_m *MockOperation) ApplyPutAdvisoryDetailExpectation(e OperationPutAdvisoryDetailExpectation) {
+	var args []interface{}
+	if e.Args.TxAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Tx)
 	}
 	if e.Args.VulnerabilityIDAnything {
 		args = append(args, mock.Anything)
@@ -467,25 +1476,213 @@ func (_m *MockOperation) PutAdvisoryDetail(tx *bbolt.Tx, vulnerabilityID string,
 	return r0
 }
 
-type OperationPutDataSourceArgs struct {
+type OperationPutAdvisoryGroupArgs struct {
+	Tx                      *bbolt.Tx
+	TxAnything              bool
+	ParentID                string
+	ParentIDAnything        bool
+	Namespace               string
+	NamespaceAnything       bool
+	PkgName                 string
+	PkgNameAnything         bool
+	VulnerabilityID         string
+	VulnerabilityIDAnything bool
+}
+
+type OperationPutAdvisoryGroupReturns struct {
+	Err error
+}
+
+type OperationPutAdvisoryGroupExpectation struct {
+	Args    OperationPutAdvisoryGroupArgs
+	Returns OperationPutAdvisoryGroupReturns
+}
+
+func (_m *MockOperation) ApplyPutAdvisoryGroupExpectation(e OperationPutAdvisoryGroupExpectation) {
+	var args []interface{}
+	if e.Args.TxAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Tx)
+	}
+	if e.Args.ParentIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.ParentID)
+	}
+	if e.Args.NamespaceAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Namespace)
+	}
+	if e.Args.PkgNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.PkgName)
+	}
+	if e.Args.VulnerabilityIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.VulnerabilityID)
+	}
+	_m.On("PutAdvisoryGroup", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyPutAdvisoryGroupExpectations(expectations []OperationPutAdvisoryGroupExpectation) {
+	for _, e := range expectations {
+		_m.ApplyPutAdvisoryGroupExpectation(e)
+	}
+}
+
+// PutAdvisoryGroup provides a mock function with given fields: tx, parentID, namespace, pkgName, vulnerabilityID
+func (_m *MockOperation) PutAdvisoryGroup(tx *bbolt.Tx, parentID string, namespace string, pkgName string, vulnerabilityID string) error {
+	ret := _m.Called(tx, parentID, namespace, pkgName, vulnerabilityID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, string, string, string, string) error); ok {
+		r0 = rf(tx, parentID, namespace, pkgName, vulnerabilityID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type OperationPutDataSourceArgs struct {
+	Tx              *bbolt.Tx
+	TxAnything      bool
+	BktName         string
+	BktNameAnything bool
+	Source          types.DataSource
+	SourceAnything  bool
+}
+
+type OperationPutDataSourceReturns struct {
+	Err error
+}
+
+type OperationPutDataSourceExpectation struct {
+	Args    OperationPutDataSourceArgs
+	Returns OperationPutDataSourceReturns
+}
+
+func (_m *MockOperation) ApplyPutDataSourceExpectation(e OperationPutDataSourceExpectation) {
+	var args []interface{}
+	if e.Args.TxAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Tx)
+	}
+	if e.Args.BktNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.BktName)
+	}
+	if e.Args.SourceAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Source)
+	}
+	_m.On("PutDataSource", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyPutDataSourceExpectations(expectations []OperationPutDataSourceExpectation) {
+	for _, e := range expectations {
+		_m.ApplyPutDataSourceExpectation(e)
+	}
+}
+
+// PutDataSource provides a mock function with given fields: tx, bktName, source
+func (_m *MockOperation) PutDataSource(tx *bbolt.Tx, bktName string, source types.DataSource) error {
+	ret := _m.Called(tx, bktName, source)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, string, types.DataSource) error); ok {
+		r0 = rf(tx, bktName, source)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type OperationPutNamespaceAliasArgs struct {
+	Tx                *bbolt.Tx
+	TxAnything        bool
+	Alias             string
+	AliasAnything     bool
+	Namespace         string
+	NamespaceAnything bool
+}
+
+type OperationPutNamespaceAliasReturns struct {
+	Err error
+}
+
+type OperationPutNamespaceAliasExpectation struct {
+	Args    OperationPutNamespaceAliasArgs
+	Returns OperationPutNamespaceAliasReturns
+}
+
+func (_m *MockOperation) ApplyPutNamespaceAliasExpectation(e OperationPutNamespaceAliasExpectation) {
+	var args []interface{}
+	if e.Args.TxAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Tx)
+	}
+	if e.Args.AliasAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Alias)
+	}
+	if e.Args.NamespaceAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Namespace)
+	}
+	_m.On("PutNamespaceAlias", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyPutNamespaceAliasExpectations(expectations []OperationPutNamespaceAliasExpectation) {
+	for _, e := range expectations {
+		_m.ApplyPutNamespaceAliasExpectation(e)
+	}
+}
+
+// PutNamespaceAlias provides a mock function with given fields: tx, alias, namespace
+func (_m *MockOperation) PutNamespaceAlias(tx *bbolt.Tx, alias string, namespace string) error {
+	ret := _m.Called(tx, alias, namespace)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, string, string) error); ok {
+		r0 = rf(tx, alias, namespace)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type OperationPutPackageAliasesArgs struct {
 	Tx              *bbolt.Tx
 	TxAnything      bool
 	BktName         string
 	BktNameAnything bool
-	Source          types.DataSource
-	SourceAnything  bool
+	Aliases         types.PackageAliases
+	AliasesAnything bool
 }
 
-type OperationPutDataSourceReturns struct {
+type OperationPutPackageAliasesReturns struct {
 	Err error
 }
 
-type OperationPutDataSourceExpectation struct {
-	Args    OperationPutDataSourceArgs
-	Returns OperationPutDataSourceReturns
+type OperationPutPackageAliasesExpectation struct {
+	Args    OperationPutPackageAliasesArgs
+	Returns OperationPutPackageAliasesReturns
 }
 
-func (_m *MockOperation) ApplyPutDataSourceExpectation(e OperationPutDataSourceExpectation) {
+func (_m *MockOperation) ApplyPutPackageAliasesExpectation(e OperationPutPackageAliasesExpectation) {
 	var args []interface{}
 	if e.Args.TxAnything {
 		args = append(args, mock.Anything)
@@ -497,27 +1694,27 @@ func (_m *MockOperation) ApplyPutDataSourceExpectation(e OperationPutDataSourceE
 	} else {
 		args = append(args, e.Args.BktName)
 	}
-	if e.Args.SourceAnything {
+	if e.Args.AliasesAnything {
 		args = append(args, mock.Anything)
 	} else {
-		args = append(args, e.Args.Source)
+		args = append(args, e.Args.Aliases)
 	}
-	_m.On("PutDataSource", args...).Return(e.Returns.Err)
+	_m.On("PutPackageAliases", args...).Return(e.Returns.Err)
 }
 
-func (_m *MockOperation) ApplyPutDataSourceExpectations(expectations []OperationPutDataSourceExpectation) {
+func (_m *MockOperation) ApplyPutPackageAliasesExpectations(expectations []OperationPutPackageAliasesExpectation) {
 	for _, e := range expectations {
-		_m.ApplyPutDataSourceExpectation(e)
+		_m.ApplyPutPackageAliasesExpectation(e)
 	}
 }
 
-// PutDataSource provides a mock function with given fields: tx, bktName, source
-func (_m *MockOperation) PutDataSource(tx *bbolt.Tx, bktName string, source types.DataSource) error {
-	ret := _m.Called(tx, bktName, source)
+// PutPackageAliases provides a mock function with given fields: tx, bktName, aliases
+func (_m *MockOperation) PutPackageAliases(tx *bbolt.Tx, bktName string, aliases types.PackageAliases) error {
+	ret := _m.Called(tx, bktName, aliases)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*bbolt.Tx, string, types.DataSource) error); ok {
-		r0 = rf(tx, bktName, source)
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, string, types.PackageAliases) error); ok {
+		r0 = rf(tx, bktName, aliases)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -757,6 +1954,64 @@ func (_m *MockOperation) PutVulnerability(tx *bbolt.Tx, vulnerabilityID string,
 	return r0
 }
 
+type OperationPutVulnerabilityAliasArgs struct {
+	Tx             *bbolt.Tx
+	TxAnything     bool
+	VulnID         string
+	VulnIDAnything bool
+	Alias          string
+	AliasAnything  bool
+}
+
+type OperationPutVulnerabilityAliasReturns struct {
+	Err error
+}
+
+type OperationPutVulnerabilityAliasExpectation struct {
+	Args    OperationPutVulnerabilityAliasArgs
+	Returns OperationPutVulnerabilityAliasReturns
+}
+
+func (_m *MockOperation) ApplyPutVulnerabilityAliasExpectation(e OperationPutVulnerabilityAliasExpectation) {
+	var args []interface{}
+	if e.Args.TxAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Tx)
+	}
+	if e.Args.VulnIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.VulnID)
+	}
+	if e.Args.AliasAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Alias)
+	}
+	_m.On("PutVulnerabilityAlias", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyPutVulnerabilityAliasExpectations(expectations []OperationPutVulnerabilityAliasExpectation) {
+	for _, e := range expectations {
+		_m.ApplyPutVulnerabilityAliasExpectation(e)
+	}
+}
+
+// PutVulnerabilityAlias provides a mock function with given fields: tx, vulnID, alias
+func (_m *MockOperation) PutVulnerabilityAlias(tx *bbolt.Tx, vulnID string, alias string) error {
+	ret := _m.Called(tx, vulnID, alias)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, string, string) error); ok {
+		r0 = rf(tx, vulnID, alias)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type OperationPutVulnerabilityDetailArgs struct {
 	Tx                      *bbolt.Tx
 	TxAnything              bool
@@ -873,6 +2128,39 @@ func (_m *MockOperation) PutVulnerabilityID(tx *bbolt.Tx, vulnerabilityID string
 	return r0
 }
 
+type OperationRebuildIndexesReturns struct {
+	Err error
+}
+
+type OperationRebuildIndexesExpectation struct {
+	Returns OperationRebuildIndexesReturns
+}
+
+func (_m *MockOperation) ApplyRebuildIndexesExpectation(e OperationRebuildIndexesExpectation) {
+	var args []interface{}
+	_m.On("RebuildIndexes", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyRebuildIndexesExpectations(expectations []OperationRebuildIndexesExpectation) {
+	for _, e := range expectations {
+		_m.ApplyRebuildIndexesExpectation(e)
+	}
+}
+
+// RebuildIndexes provides a mock function with given fields:
+func (_m *MockOperation) RebuildIndexes() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type OperationRedHatNVRToCPEsArgs struct {
 	Nvr         string
 	NvrAnything bool
@@ -981,6 +2269,39 @@ func (_m *MockOperation) RedHatRepoToCPEs(repository string) ([]int, error) {
 	return r0, r1
 }
 
+type OperationRenormalizeSeveritiesReturns struct {
+	Err error
+}
+
+type OperationRenormalizeSeveritiesExpectation struct {
+	Returns OperationRenormalizeSeveritiesReturns
+}
+
+func (_m *MockOperation) ApplyRenormalizeSeveritiesExpectation(e OperationRenormalizeSeveritiesExpectation) {
+	var args []interface{}
+	_m.On("RenormalizeSeverities", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyRenormalizeSeveritiesExpectations(expectations []OperationRenormalizeSeveritiesExpectation) {
+	for _, e := range expectations {
+		_m.ApplyRenormalizeSeveritiesExpectation(e)
+	}
+}
+
+// RenormalizeSeverities provides a mock function with given fields:
+func (_m *MockOperation) RenormalizeSeverities() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type OperationSaveAdvisoryDetailsArgs struct {
 	Tx            *bbolt.Tx
 	TxAnything    bool
@@ -1031,3 +2352,262 @@ func (_m *MockOperation) SaveAdvisoryDetails(tx *bbolt.Tx, cveID string) error {
 
 	return r0
 }
+
+type OperationSearchTextArgs struct {
+	Query         string
+	QueryAnything bool
+}
+
+type OperationSearchTextReturns struct {
+	VulnIDs []string
+	Err     error
+}
+
+type OperationSearchTextExpectation struct {
+	Args    OperationSearchTextArgs
+	Returns OperationSearchTextReturns
+}
+
+func (_m *MockOperation) ApplySearchTextExpectation(e OperationSearchTextExpectation) {
+	var args []interface{}
+	if e.Args.QueryAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Query)
+	}
+	_m.On("SearchText", args...).Return(e.Returns.VulnIDs, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplySearchTextExpectations(expectations []OperationSearchTextExpectation) {
+	for _, e := range expectations {
+		_m.ApplySearchTextExpectation(e)
+	}
+}
+
+// SearchText provides a mock function with given fields: query
+func (_m *MockOperation) SearchText(query string) ([]string, error) {
+	ret := _m.Called(query)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationSeverityConflictsReturns struct {
+	Conflicts map[string]types.VendorSeverity
+	Err       error
+}
+
+type OperationSeverityConflictsExpectation struct {
+	Returns OperationSeverityConflictsReturns
+}
+
+func (_m *MockOperation) ApplySeverityConflictsExpectation(e OperationSeverityConflictsExpectation) {
+	var args []interface{}
+	_m.On("SeverityConflicts", args...).Return(e.Returns.Conflicts, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplySeverityConflictsExpectations(expectations []OperationSeverityConflictsExpectation) {
+	for _, e := range expectations {
+		_m.ApplySeverityConflictsExpectation(e)
+	}
+}
+
+// SeverityConflicts provides a mock function with given fields:
+func (_m *MockOperation) SeverityConflicts() (map[string]types.VendorSeverity, error) {
+	ret := _m.Called()
+
+	var r0 map[string]types.VendorSeverity
+	if rf, ok := ret.Get(0).(func() map[string]types.VendorSeverity); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]types.VendorSeverity)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationUpdateAdvisoryArgs struct {
+	Namespace         string
+	NamespaceAnything bool
+	PkgName           string
+	PkgNameAnything   bool
+	VulnID            string
+	VulnIDAnything    bool
+	Adv               types.Advisory
+	AdvAnything       bool
+}
+
+type OperationUpdateAdvisoryReturns struct {
+	Err error
+}
+
+type OperationUpdateAdvisoryExpectation struct {
+	Args    OperationUpdateAdvisoryArgs
+	Returns OperationUpdateAdvisoryReturns
+}
+
+func (_m *MockOperation) ApplyUpdateAdvisoryExpectation(e OperationUpdateAdvisoryExpectation) {
+	var args []interface{}
+	if e.Args.NamespaceAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Namespace)
+	}
+	if e.Args.PkgNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.PkgName)
+	}
+	if e.Args.VulnIDAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.VulnID)
+	}
+	if e.Args.AdvAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Adv)
+	}
+	_m.On("UpdateAdvisory", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyUpdateAdvisoryExpectations(expectations []OperationUpdateAdvisoryExpectation) {
+	for _, e := range expectations {
+		_m.ApplyUpdateAdvisoryExpectation(e)
+	}
+}
+
+// UpdateAdvisory provides a mock function with given fields: namespace, pkgName, vulnID, adv
+func (_m *MockOperation) UpdateAdvisory(namespace string, pkgName string, vulnID string, adv types.Advisory) error {
+	ret := _m.Called(namespace, pkgName, vulnID, adv)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, types.Advisory) error); ok {
+		r0 = rf(namespace, pkgName, vulnID, adv)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type OperationVerifyBucketHashesReturns struct {
+	Tampered []string
+	Err      error
+}
+
+type OperationVerifyBucketHashesExpectation struct {
+	Returns OperationVerifyBucketHashesReturns
+}
+
+func (_m *MockOperation) ApplyVerifyBucketHashesExpectation(e OperationVerifyBucketHashesExpectation) {
+	var args []interface{}
+	_m.On("VerifyBucketHashes", args...).Return(e.Returns.Tampered, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyVerifyBucketHashesExpectations(expectations []OperationVerifyBucketHashesExpectation) {
+	for _, e := range expectations {
+		_m.ApplyVerifyBucketHashesExpectation(e)
+	}
+}
+
+// VerifyBucketHashes provides a mock function with given fields:
+func (_m *MockOperation) VerifyBucketHashes() ([]string, error) {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type OperationWithNamespaceArgs struct {
+	Source         string
+	SourceAnything bool
+}
+
+type OperationWithNamespaceReturns struct {
+	Reader NamespaceReader
+	Err    error
+}
+
+type OperationWithNamespaceExpectation struct {
+	Args    OperationWithNamespaceArgs
+	Returns OperationWithNamespaceReturns
+}
+
+func (_m *MockOperation) ApplyWithNamespaceExpectation(e OperationWithNamespaceExpectation) {
+	var args []interface{}
+	if e.Args.SourceAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Source)
+	}
+	_m.On("WithNamespace", args...).Return(e.Returns.Reader, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyWithNamespaceExpectations(expectations []OperationWithNamespaceExpectation) {
+	for _, e := range expectations {
+		_m.ApplyWithNamespaceExpectation(e)
+	}
+}
+
+// WithNamespace provides a mock function with given fields: source
+func (_m *MockOperation) WithNamespace(source string) (NamespaceReader, error) {
+	ret := _m.Called(source)
+
+	var r0 NamespaceReader
+	if rf, ok := ret.Get(0).(func(string) NamespaceReader); ok {
+		r0 = rf(source)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(NamespaceReader)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(source)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
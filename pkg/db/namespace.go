@@ -0,0 +1,86 @@
+package db
+
+import (
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+// internalBuckets hold data other than advisories, so they are not namespaces.
+var internalBuckets = map[string]struct{}{
+	vulnerabilityBucket:       {},
+	vulnerabilityDetailBucket: {},
+	vulnerabilityIDBucket:     {},
+	advisoryDetailBucket:      {},
+	dataSourceBucket:          {},
+	packageAliasBucket:        {},
+	namespaceAliasBucket:      {},
+	affectedPackageBucket:     {},
+	textIndexBucket:           {},
+	cpeIndexBucket:            {},
+	advisoryGroupBucket:       {},
+	advisoryFragmentBucket:    {},
+	buildCheckpointBucket:     {},
+	redhatCPERootBucket:       {},
+	bucketHashBucket:          {},
+	severityConflictBucket:    {},
+	vulnerabilityAliasBucket:  {},
+}
+
+// ListNamespaces returns the sorted names of all namespaces in the DB, such as "debian 10" and
+// "npm::Node.js Ecosystem Security Working Group". Namespaces nested in the advisory-detail bucket
+// and registered in the data-source bucket are included as well, so that it works before the DB is optimized.
+func (dbc Config) ListNamespaces() ([]string, error) {
+	uniq := map[string]struct{}{}
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if _, ok := internalBuckets[string(name)]; !ok {
+				uniq[string(name)] = struct{}{}
+			}
+			return nil
+		})
+		if err != nil {
+			return xerrors.Errorf("top-level bucket error: %w", err)
+		}
+
+		if b := tx.Bucket([]byte(dataSourceBucket)); b != nil {
+			err = b.ForEach(func(k, _ []byte) error {
+				uniq[string(k)] = struct{}{}
+				return nil
+			})
+			if err != nil {
+				return xerrors.Errorf("data source error: %w", err)
+			}
+		}
+
+		if b := tx.Bucket([]byte(advisoryDetailBucket)); b != nil {
+			// advisory-detail => vulnerability ID => namespace
+			err = b.ForEach(func(vulnID, v []byte) error {
+				if v != nil {
+					return nil
+				}
+				return b.Bucket(vulnID).ForEach(func(k, v []byte) error {
+					if v == nil {
+						uniq[string(k)] = struct{}{}
+					}
+					return nil
+				})
+			})
+			if err != nil {
+				return xerrors.Errorf("advisory detail error: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to list namespaces: %w", err)
+	}
+
+	var namespaces []string
+	for ns := range uniq {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"encoding/json"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+const (
+	namespaceAliasBucket = "namespace-alias"
+)
+
+// PutNamespaceAlias makes GetAdvisories fall back to the namespace when the alias namespace has no advisories
+// for the package, e.g. "oracle linux 8.6" => "oracle linux 8" for distributions sharing advisories across minor versions.
+func (dbc Config) PutNamespaceAlias(tx *bolt.Tx, alias, namespace string) error {
+	if err := dbc.put(tx, []string{namespaceAliasBucket}, alias, namespace); err != nil {
+		return xerrors.Errorf("failed to put the namespace alias %s: %w", alias, err)
+	}
+	return nil
+}
+
+// getNamespaceAlias returns the namespace the alias falls back to, or an empty string
+func (dbc Config) getNamespaceAlias(tx *bolt.Tx, alias string) (string, error) {
+	b := dbc.getTx(tx, []string{namespaceAliasBucket}, alias)
+	if b == nil {
+		return "", nil
+	}
+	var namespace string
+	if err := json.Unmarshal(b, &namespace); err != nil {
+		return "", xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+	return namespace, nil
+}
@@ -0,0 +1,71 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_GetAdvisoriesWithNamespaceAlias(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		pkgName string
+		want    []types.Advisory
+	}{
+		{
+			name:    "fall back to the aliased namespace",
+			source:  "oracle linux 8.6",
+			pkgName: "openssl",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-3711",
+					FixedVersion:    "1:1.1.1k-5.el8_5",
+				},
+			},
+		},
+		{
+			name:    "advisories in the namespace itself",
+			source:  "oracle linux 8.6",
+			pkgName: "curl",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2022-22576",
+					FixedVersion:    "7.61.1-22.el8_6.3",
+				},
+			},
+		},
+		{
+			name:    "no alias",
+			source:  "oracle linux 8.5",
+			pkgName: "openssl",
+		},
+		{
+			name:    "aliases are one-way",
+			source:  "oracle linux 8",
+			pkgName: "curl",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/namespace-alias.yaml"})
+			defer db.Close()
+
+			dbc := db.Config{}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				return dbc.PutNamespaceAlias(tx, "oracle linux 8.6", "oracle linux 8")
+			})
+			require.NoError(t, err)
+
+			got, err := dbc.GetAdvisories(tt.source, tt.pkgName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
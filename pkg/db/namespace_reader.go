@@ -0,0 +1,58 @@
+package db
+
+import (
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// NamespaceReader looks up advisories in a namespace within one read transaction.
+// It must be closed after use since an open read transaction blocks remapping the DB file.
+type NamespaceReader interface {
+	GetAdvisories(pkgName string) (advisories []types.Advisory, err error)
+	Close() error
+}
+
+type namespaceReader struct {
+	dbc    Config
+	tx     *bolt.Tx
+	source string
+}
+
+// WithNamespace returns a reader holding one read transaction for repeated lookups in the source,
+// e.g. "debian 10" or "npm::".
+func (dbc Config) WithNamespace(source string) (NamespaceReader, error) {
+	tx, err := dbc.Connection().Begin(false)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to begin a transaction: %w", err)
+	}
+	return &namespaceReader{
+		dbc:    dbc,
+		tx:     tx,
+		source: source,
+	}, nil
+}
+
+func (r *namespaceReader) GetAdvisories(pkgName string) ([]types.Advisory, error) {
+	if r.tx == nil {
+		return nil, xerrors.New("namespace reader is already closed")
+	}
+	advisories, err := r.dbc.getAdvisories(r.tx, r.source, pkgName)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get advisories: %w", err)
+	}
+	return advisories, nil
+}
+
+func (r *namespaceReader) Close() error {
+	if r.tx == nil {
+		return nil
+	}
+	err := r.tx.Rollback()
+	r.tx = nil
+	if err != nil {
+		return xerrors.Errorf("failed to close the transaction: %w", err)
+	}
+	return nil
+}
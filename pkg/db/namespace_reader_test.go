@@ -0,0 +1,55 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_WithNamespace(t *testing.T) {
+	_ = dbtest.InitDB(t, []string{"testdata/fixtures/ospkg.yaml"})
+	defer db.Close()
+
+	dbc := db.Config{}
+	conn := dbc.Connection()
+	before := conn.Stats().TxN
+
+	r, err := dbc.WithNamespace("Red Hat Enterprise Linux 8")
+	require.NoError(t, err)
+
+	got, err := r.GetAdvisories("bind")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.Advisory{
+		{
+			VulnerabilityID: "CVE-2018-5745",
+			FixedVersion:    "32:9.11.4-26.P2.el8",
+		},
+		{
+			VulnerabilityID: "CVE-2020-8617",
+			FixedVersion:    "32:9.11.13-5.el8_2",
+		},
+	}, got)
+
+	got, err = r.GetAdvisories("bind")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	got, err = r.GetAdvisories("non-existent")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	require.NoError(t, r.Close())
+
+	// All the lookups share one read transaction
+	assert.Equal(t, 1, conn.Stats().TxN-before)
+
+	// The reader is unusable after closing
+	_, err = r.GetAdvisories("bind")
+	assert.Error(t, err)
+	assert.NoError(t, r.Close())
+}
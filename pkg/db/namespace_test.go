@@ -0,0 +1,52 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+)
+
+func TestConfig_ListNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures []string
+		want     []string
+	}{
+		{
+			name:     "top-level buckets",
+			fixtures: []string{"testdata/fixtures/multiple-buckets.yaml", "testdata/fixtures/redhat-cpe.yaml"},
+			want: []string{
+				"composer::GitHub Security Advisory Composer",
+				"composer::php-security-advisories",
+			},
+		},
+		{
+			name:     "nested in advisory-detail",
+			fixtures: []string{"testdata/fixtures/advisory-detail.yaml"},
+			want: []string{
+				"Red Hat",
+				"alpine 3.14",
+				"debian 10",
+			},
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, tt.fixtures)
+			defer db.Close()
+
+			dbc := db.Config{}
+			got, err := dbc.ListNamespaces()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
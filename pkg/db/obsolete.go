@@ -0,0 +1,125 @@
+package db
+
+import (
+	"encoding/json"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// MarkObsoleteAdvisories flags the advisories no longer given by upstream as obsolete instead of deleting them,
+// so that the history is kept for auditing. It must be called after SaveAdvisoryDetails and before
+// the advisory-detail bucket is deleted. Only namespaces written in this build are checked, so that
+// sources not updated, e.g. with --only-update, keep their advisories. It returns the number of marked advisories.
+func (dbc Config) MarkObsoleteAdvisories() (int, error) {
+	var marked int
+	err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(advisoryDetailBucket))
+		if root == nil {
+			return nil
+		}
+
+		// advisory-detail => vulnerability ID => namespace
+		namespaces := map[string]struct{}{}
+		err := root.ForEach(func(vulnID, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			return root.Bucket(vulnID).ForEach(func(ns, v []byte) error {
+				if v == nil {
+					namespaces[string(ns)] = struct{}{}
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return xerrors.Errorf("advisory detail error: %w", err)
+		}
+
+		for ns := range namespaces {
+			nsBkt := tx.Bucket([]byte(ns))
+			if nsBkt == nil {
+				continue
+			}
+			n, err := markObsolete(root, nsBkt, []string{ns})
+			if err != nil {
+				return xerrors.Errorf("obsolete error in %s: %w", ns, err)
+			}
+			marked += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("failed to mark obsolete advisories: %w", err)
+	}
+	return marked, nil
+}
+
+// markObsolete walks the advisories under the bucket, i.e. namespace => (nested buckets) => package name => vulnerability ID,
+// and flags those missing in advisory-detail => vulnerability ID => namespace => (nested buckets) => package name.
+func markObsolete(root, bkt *bolt.Bucket, bktNames []string) (int, error) {
+	var marked int
+	var obsolete [][]byte
+	err := bkt.ForEach(func(k, v []byte) error {
+		if v == nil {
+			n, err := markObsolete(root, bkt.Bucket(k), append(append([]string{}, bktNames...), string(k)))
+			marked += n
+			return err
+		}
+		if len(bktNames) < 2 || advisoryDetailExists(root, string(k), bktNames) {
+			return nil
+		}
+		obsolete = append(obsolete, k)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Keys must not be modified while iterating
+	for _, vulnID := range obsolete {
+		advisory := map[string]interface{}{}
+		if err = json.Unmarshal(bkt.Get(vulnID), &advisory); err != nil {
+			return 0, xerrors.Errorf("JSON unmarshal error (%s): %w", vulnID, err)
+		}
+		if advisory["Obsolete"] == true {
+			continue
+		}
+		advisory["Obsolete"] = true
+		b, err := json.Marshal(advisory)
+		if err != nil {
+			return 0, xerrors.Errorf("JSON marshal error (%s): %w", vulnID, err)
+		}
+		if err = bkt.Put(vulnID, b); err != nil {
+			return 0, xerrors.Errorf("failed to put the obsolete advisory: %w", NewError(ErrWrite, err))
+		}
+		marked++
+	}
+	return marked, nil
+}
+
+// advisoryDetailExists returns true if advisory-detail has the advisory of the package at the path,
+// i.e. namespace => (nested buckets) => package name
+func advisoryDetailExists(root *bolt.Bucket, vulnID string, path []string) bool {
+	bkt := root.Bucket([]byte(vulnID))
+	for _, name := range path[:len(path)-1] {
+		if bkt == nil {
+			return false
+		}
+		bkt = bkt.Bucket([]byte(name))
+	}
+	return bkt != nil && bkt.Get([]byte(path[len(path)-1])) != nil
+}
+
+// dropObsolete removes the advisories flagged by MarkObsoleteAdvisories
+func dropObsolete(advisories []types.Advisory) []types.Advisory {
+	var filtered []types.Advisory
+	for _, adv := range advisories {
+		if !adv.Obsolete {
+			filtered = append(filtered, adv)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,306 @@
+package db
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+const osvSchemaVersion = "1.3.1"
+
+var (
+	// osvEcosystems maps the ecosystem prefix of namespaces to the OSV ecosystem, e.g. "pip::" => "PyPI"
+	osvEcosystems = map[string]string{
+		"npm":      "npm",
+		"pip":      "PyPI",
+		"rubygems": "RubyGems",
+		"cargo":    "crates.io",
+		"nuget":    "NuGet",
+		"maven":    "Maven",
+		"go":       "Go",
+		"composer": "Packagist",
+		"conan":    "ConanCenter",
+	}
+
+	// e.g. ">=1.2.0, <1.2.5", ">=1.2.0 <1.2.5" and "<= 1.5.1"
+	constraintRegexp = regexp.MustCompile(`(>=|<=|>|<|==|=)?\s*([^\s,|<>=]+)`)
+)
+
+// osvEntry is the subset of the OSV schema we can fill from the DB
+// Ref. https://ossf.github.io/osv-schema/
+type osvEntry struct {
+	SchemaVersion    string                 `json:"schema_version"`
+	ID               string                 `json:"id"`
+	Modified         *time.Time             `json:"modified,omitempty"`
+	Published        *time.Time             `json:"published,omitempty"`
+	Summary          string                 `json:"summary,omitempty"`
+	Details          string                 `json:"details,omitempty"`
+	Severity         []osvSeverity          `json:"severity,omitempty"`
+	Affected         []osvAffected          `json:"affected"`
+	References       []osvReference         `json:"references,omitempty"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific,omitempty"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvAffected struct {
+	Package          osvPackage             `json:"package"`
+	Ranges           []osvRange             `json:"ranges,omitempty"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific,omitempty"`
+}
+
+type osvPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+type osvReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// ExportOSV writes one OSV JSON file per vulnerability having advisories into the directory, e.g. CVE-2014-7205.json.
+// Version constraints are converted into OSV ranges where possible, and the original constraints are kept
+// in "database_specific" of each affected package so that nothing stored in the DB is lost.
+func (dbc Config) ExportOSV(dir string) error {
+	entries := map[string]*osvEntry{}
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		err := tx.ForEach(func(ns []byte, nsBkt *bolt.Bucket) error {
+			if _, ok := internalBuckets[string(ns)]; ok {
+				return nil
+			}
+			return nsBkt.ForEach(func(pkgName, v []byte) error {
+				if v != nil {
+					return nil
+				}
+				return nsBkt.Bucket(pkgName).ForEach(func(vulnID, value []byte) error {
+					var adv types.Advisory
+					if err := json.Unmarshal(value, &adv); err != nil {
+						return xerrors.Errorf("failed to unmarshal the advisory (%s, %s): %w", pkgName, vulnID, err)
+					}
+
+					entry, ok := entries[string(vulnID)]
+					if !ok {
+						entry = &osvEntry{SchemaVersion: osvSchemaVersion, ID: string(vulnID)}
+						entries[string(vulnID)] = entry
+					}
+					entry.Affected = append(entry.Affected, toOSVAffected(string(ns), string(pkgName), adv))
+					return nil
+				})
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		for vulnID, entry := range entries {
+			b := dbc.getTx(tx, []string{vulnerabilityBucket}, vulnID)
+			if b == nil {
+				continue
+			}
+			var vuln types.Vulnerability
+			if err = json.Unmarshal(b, &vuln); err != nil {
+				return xerrors.Errorf("failed to unmarshal the vulnerability %s: %w", vulnID, err)
+			}
+			fillOSVEntry(entry, vuln)
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to export OSV: %w", err)
+	}
+
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return xerrors.Errorf("failed to mkdir: %w", err)
+	}
+	for vulnID, entry := range entries {
+		sortOSVAffected(entry.Affected)
+		b, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return xerrors.Errorf("JSON marshal error: %w", err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, vulnID+".json"), b, 0600); err != nil {
+			return xerrors.Errorf("failed to write %s: %w", vulnID, err)
+		}
+	}
+	return nil
+}
+
+func fillOSVEntry(entry *osvEntry, vuln types.Vulnerability) {
+	entry.Summary = vuln.Title
+	entry.Details = vuln.Description
+	entry.Published = vuln.PublishedDate
+	entry.Modified = vuln.LastModifiedDate
+	entry.Severity = toOSVSeverity(vuln.CVSS)
+	for _, ref := range vuln.References {
+		entry.References = append(entry.References, osvReference{Type: "WEB", URL: ref})
+	}
+
+	entry.DatabaseSpecific = map[string]interface{}{}
+	if vuln.Severity != "" {
+		entry.DatabaseSpecific["severity"] = vuln.Severity
+	}
+	if len(vuln.CweIDs) > 0 {
+		entry.DatabaseSpecific["cwe_ids"] = vuln.CweIDs
+	}
+}
+
+// sortOSVAffected sorts the affected packages by ecosystem, name and namespace, so that exports are deterministic
+func sortOSVAffected(affected []osvAffected) {
+	sort.SliceStable(affected, func(i, j int) bool {
+		if affected[i].Package.Ecosystem != affected[j].Package.Ecosystem {
+			return affected[i].Package.Ecosystem < affected[j].Package.Ecosystem
+		}
+		if affected[i].Package.Name != affected[j].Package.Name {
+			return affected[i].Package.Name < affected[j].Package.Name
+		}
+		nsI, _ := affected[i].DatabaseSpecific["namespace"].(string)
+		nsJ, _ := affected[j].DatabaseSpecific["namespace"].(string)
+		return nsI < nsJ
+	})
+}
+
+// toOSVSeverity takes the CVSS vectors, preferring NVD
+func toOSVSeverity(cvss types.VendorCVSS) []osvSeverity {
+	var sources []string
+	for source := range cvss {
+		sources = append(sources, string(source))
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i] == "nvd" || sources[j] == "nvd" {
+			return sources[i] == "nvd"
+		}
+		return sources[i] < sources[j]
+	})
+
+	var v3, v2 string
+	for _, source := range sources {
+		c := cvss[types.SourceID(source)]
+		if v3 == "" {
+			v3 = c.V3Vector
+		}
+		if v2 == "" {
+			v2 = c.V2Vector
+		}
+	}
+
+	var severities []osvSeverity
+	if v3 != "" {
+		severities = append(severities, osvSeverity{Type: "CVSS_V3", Score: v3})
+	}
+	if v2 != "" {
+		severities = append(severities, osvSeverity{Type: "CVSS_V2", Score: v2})
+	}
+	return severities
+}
+
+func toOSVAffected(namespace, pkgName string, adv types.Advisory) osvAffected {
+	ecosystem := namespace
+	if i := strings.Index(namespace, "::"); i > 0 {
+		if eco, ok := osvEcosystems[namespace[:i]]; ok {
+			ecosystem = eco
+		}
+	}
+
+	affected := osvAffected{
+		Package: osvPackage{
+			Ecosystem: ecosystem,
+			Name:      pkgName,
+		},
+		DatabaseSpecific: map[string]interface{}{
+			"namespace": namespace,
+		},
+	}
+	if len(adv.VulnerableVersions) > 0 {
+		affected.DatabaseSpecific["vulnerable_versions"] = adv.VulnerableVersions
+	}
+	if len(adv.PatchedVersions) > 0 {
+		affected.DatabaseSpecific["patched_versions"] = adv.PatchedVersions
+	}
+	if len(adv.UnaffectedVersions) > 0 {
+		affected.DatabaseSpecific["unaffected_versions"] = adv.UnaffectedVersions
+	}
+
+	switch {
+	case adv.AffectsAllVersions():
+		affected.Ranges = append(affected.Ranges, osvRange{Type: "ECOSYSTEM", Events: []osvEvent{{Introduced: "0"}}})
+	case len(adv.VulnerableVersions) > 0:
+		for _, constraint := range adv.VulnerableVersions {
+			if events, ok := toOSVEvents(constraint); ok {
+				affected.Ranges = append(affected.Ranges, osvRange{Type: "ECOSYSTEM", Events: events})
+			}
+		}
+	case len(adv.PatchedVersions) > 0:
+		// The affected range cannot be derived from patched versions alone, e.g. "~> 1.1.5"
+	default:
+		// OS packages
+		introduced := adv.IntroducedVersion
+		if introduced == "" {
+			introduced = "0"
+		}
+		events := []osvEvent{{Introduced: introduced}}
+		if adv.FixedVersion != "" {
+			events = append(events, osvEvent{Fixed: adv.FixedVersion})
+		}
+		affected.Ranges = append(affected.Ranges, osvRange{Type: "ECOSYSTEM", Events: events})
+	}
+	return affected
+}
+
+// toOSVEvents converts a constraint into OSV events, e.g. ">=1.2.0, <1.2.5" => introduced 1.2.0 and fixed 1.2.5.
+// It returns false for constraints OSV cannot express, e.g. ">1.2.0".
+func toOSVEvents(constraint string) ([]osvEvent, bool) {
+	var introduced, fixed, lastAffected string
+	for _, m := range constraintRegexp.FindAllStringSubmatch(constraint, -1) {
+		op, ver := m[1], m[2]
+		switch op {
+		case ">=":
+			introduced = ver
+		case "<":
+			fixed = ver
+		case "<=":
+			lastAffected = ver
+		case "", "=", "==":
+			introduced, lastAffected = ver, ver
+		default:
+			return nil, false
+		}
+	}
+	if fixed != "" && lastAffected != "" {
+		return nil, false
+	}
+
+	if introduced == "" {
+		introduced = "0"
+	}
+	events := []osvEvent{{Introduced: introduced}}
+	if fixed != "" {
+		events = append(events, osvEvent{Fixed: fixed})
+	} else if lastAffected != "" {
+		events = append(events, osvEvent{LastAffected: lastAffected})
+	}
+	return events, true
+}
@@ -0,0 +1,114 @@
+package db_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+)
+
+func TestConfig_ExportOSV(t *testing.T) {
+	_ = dbtest.InitDB(t, []string{"testdata/fixtures/osv-export.yaml"})
+	defer db.Close()
+
+	dir := t.TempDir()
+	dbc := db.Config{}
+	err := dbc.ExportOSV(dir)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	got, err := os.ReadFile(filepath.Join(dir, "CVE-2014-7205.json"))
+	require.NoError(t, err)
+
+	want := `{
+  "schema_version": "1.3.1",
+  "id": "CVE-2014-7205",
+  "published": "2014-10-08T17:55:06Z",
+  "summary": "Arbitrary JavaScript Execution",
+  "details": "A vulnerability exists in bassmaster <= 1.5.1",
+  "severity": [
+    {"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+    {"type": "CVSS_V2", "score": "AV:N/AC:L/Au:N/C:P/I:P/A:P"}
+  ],
+  "affected": [
+    {
+      "package": {"ecosystem": "PyPI", "name": "django"},
+      "ranges": [
+        {"type": "ECOSYSTEM", "events": [{"introduced": "1.2.0"}, {"fixed": "1.2.5"}]}
+      ],
+      "database_specific": {
+        "namespace": "pip::GitHub Security Advisory pip",
+        "vulnerable_versions": [">=1.2.0, <1.2.5"],
+        "patched_versions": ["1.2.5"]
+      }
+    },
+    {
+      "package": {"ecosystem": "debian 10", "name": "bassmaster"},
+      "ranges": [
+        {"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.5.2-1"}]}
+      ],
+      "database_specific": {"namespace": "debian 10"}
+    },
+    {
+      "package": {"ecosystem": "npm", "name": "bassmaster"},
+      "ranges": [
+        {"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"last_affected": "1.5.1"}]}
+      ],
+      "database_specific": {
+        "namespace": "npm::Node.js Ecosystem Security Working Group",
+        "vulnerable_versions": ["<=1.5.1"],
+        "patched_versions": [">=1.5.2"]
+      }
+    }
+  ],
+  "references": [
+    {"type": "WEB", "url": "https://nodesecurity.io/advisories/bassmaster_js_injection"}
+  ],
+  "database_specific": {"severity": "HIGH", "cwe_ids": ["CWE-94"]}
+}`
+	assert.JSONEq(t, want, string(got))
+
+	// Without the vulnerability, e.g. in AdvisoriesOnly builds, affected packages are sorted as well
+	got, err = os.ReadFile(filepath.Join(dir, "CVE-2020-8203.json"))
+	require.NoError(t, err)
+
+	want = `{
+  "schema_version": "1.3.1",
+  "id": "CVE-2020-8203",
+  "affected": [
+    {
+      "package": {"ecosystem": "PyPI", "name": "pydash"},
+      "ranges": [
+        {"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "5.0.0"}]}
+      ],
+      "database_specific": {"namespace": "pip::GitHub Security Advisory pip"}
+    },
+    {
+      "package": {"ecosystem": "npm", "name": "lodash"},
+      "ranges": [
+        {"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "4.17.19"}]}
+      ],
+      "database_specific": {
+        "namespace": "npm::GitHub Security Advisory npm",
+        "vulnerable_versions": ["<4.17.19"]
+      }
+    },
+    {
+      "package": {"ecosystem": "npm", "name": "lodash"},
+      "database_specific": {
+        "namespace": "npm::Node.js Ecosystem Security Working Group",
+        "patched_versions": [">=4.17.19"]
+      }
+    }
+  ]
+}`
+	assert.JSONEq(t, want, string(got))
+}
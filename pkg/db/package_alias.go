@@ -0,0 +1,92 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
+)
+
+const (
+	packageAliasBucket = "package-alias"
+)
+
+// PutPackageAliases stores alternative names of packages in the namespace, e.g. renamed packages or RPM Provides.
+// Aliases work in both directions so that advisories stored under either name are found by GetAdvisories.
+func (dbc Config) PutPackageAliases(tx *bolt.Tx, bktName string, aliases types.PackageAliases) error {
+	for pkgName, names := range aliases {
+		for _, alias := range names {
+			if alias == pkgName {
+				continue
+			}
+			if err := dbc.putPackageAlias(tx, bktName, alias, pkgName); err != nil {
+				return xerrors.Errorf("failed to put the alias %s: %w", alias, err)
+			}
+			if err := dbc.putPackageAlias(tx, bktName, pkgName, alias); err != nil {
+				return xerrors.Errorf("failed to put the alias %s: %w", pkgName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (dbc Config) putPackageAlias(tx *bolt.Tx, bktName, name, alias string) error {
+	var existing []string
+	if bkt := tx.Bucket([]byte(packageAliasBucket)); bkt != nil {
+		if nsBkt := bkt.Bucket([]byte(bktName)); nsBkt != nil {
+			if b := nsBkt.Get([]byte(name)); b != nil {
+				if err := json.Unmarshal(b, &existing); err != nil {
+					return xerrors.Errorf("JSON unmarshal error: %w", err)
+				}
+			}
+		}
+	}
+
+	aliases := ustrings.Unique(append(existing, alias))
+	return dbc.put(tx, []string{packageAliasBucket, bktName}, name, aliases)
+}
+
+// getPackageAliases returns alias names of the package. The source is handled in the same way as ForEachAdvisory.
+func (dbc Config) getPackageAliases(tx *bolt.Tx, source, pkgName string) ([]string, error) {
+	root := tx.Bucket([]byte(packageAliasBucket))
+	if root == nil {
+		return nil, nil
+	}
+
+	var nsBuckets []string
+	if strings.Contains(source, "::") {
+		prefix := []byte(source)
+		c := root.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			nsBuckets = append(nsBuckets, string(k))
+		}
+	} else {
+		nsBuckets = append(nsBuckets, source)
+	}
+
+	var aliases []string
+	for _, ns := range nsBuckets {
+		bkt := root.Bucket([]byte(ns))
+		if bkt == nil {
+			continue
+		}
+		b := bkt.Get([]byte(pkgName))
+		if b == nil {
+			continue
+		}
+		var names []string
+		if err := json.Unmarshal(b, &names); err != nil {
+			return nil, xerrors.Errorf("JSON unmarshal error: %w", err)
+		}
+		aliases = append(aliases, names...)
+	}
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+	return ustrings.Unique(aliases), nil
+}
@@ -0,0 +1,107 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_GetAdvisoriesWithAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		pkgName string
+		want    []types.Advisory
+	}{
+		{
+			name:    "canonical name",
+			source:  "rpm::Example",
+			pkgName: "nodejs",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-22930",
+					FixedVersion:    "14.17.4",
+				},
+			},
+		},
+		{
+			name:    "alias",
+			source:  "rpm::Example",
+			pkgName: "node",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-22930",
+					FixedVersion:    "14.17.4",
+				},
+			},
+		},
+		{
+			name:    "alias with prefix scan",
+			source:  "rpm::",
+			pkgName: "node",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-22930",
+					FixedVersion:    "14.17.4",
+				},
+			},
+		},
+		{
+			name:    "alias in another namespace",
+			source:  "Red Hat",
+			pkgName: "node",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/package-alias.yaml"})
+			defer db.Close()
+
+			dbc := db.Config{}
+			got, err := dbc.GetAdvisories(tt.source, tt.pkgName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_PutPackageAliases(t *testing.T) {
+	_ = dbtest.InitDB(t, []string{"testdata/fixtures/package-alias.yaml"})
+	defer db.Close()
+
+	dbc := db.Config{}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := dbc.PutAdvisory(tx, []string{"rpm::Example", "python3"}, "CVE-2021-3177",
+			types.Advisory{FixedVersion: "3.6.8-37"}); err != nil {
+			return err
+		}
+		return dbc.PutPackageAliases(tx, "rpm::Example", types.PackageAliases{
+			"python3": {"python36", "python3"},
+		})
+	})
+	require.NoError(t, err)
+
+	want := []types.Advisory{
+		{
+			VulnerabilityID: "CVE-2021-3177",
+			FixedVersion:    "3.6.8-37",
+		},
+	}
+
+	// Look up by the alias
+	got, err := dbc.GetAdvisories("rpm::Example", "python36")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// Aliases of other packages are kept
+	got, err = dbc.GetAdvisories("rpm::Example", "node")
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"net/url"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// purlNamespaces maps the PURL type to the namespace prefix of language-specific packages
+// Ref. https://github.com/package-url/purl-spec/blob/master/PURL-TYPES.rst
+var purlNamespaces = map[string]string{
+	"npm":      "npm::",
+	"pypi":     "pip::",
+	"gem":      "rubygems::",
+	"cargo":    "cargo::",
+	"nuget":    "nuget::",
+	"golang":   "go::",
+	"composer": "composer::",
+	"maven":    "maven::",
+}
+
+// GetAdvisoriesByPURL returns the advisories of the package identified by the Package URL.
+// Language-specific packages are looked up in all namespaces of the ecosystem, e.g. "pkg:npm/bassmaster"
+// in "npm::" namespaces. OS packages need the "distro" qualifier, e.g. "pkg:deb/debian/openssl?distro=debian-10".
+// The version and subpath are ignored.
+func (dbc Config) GetAdvisoriesByPURL(purl string) ([]types.Advisory, error) {
+	namespace, pkgName, err := resolvePURL(purl)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to resolve %s: %w", purl, err)
+	}
+
+	var advisories []types.Advisory
+	err = dbc.Connection().View(func(tx *bolt.Tx) error {
+		advisories, err = dbc.getAdvisories(tx, namespace, pkgName)
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get advisories of %s: %w", purl, err)
+	}
+	return advisories, nil
+}
+
+// resolvePURL returns the namespace and the package name as stored in the DB,
+// e.g. "pkg:npm/%40babel/core@7.0.0" => "npm::", "@babel/core"
+func resolvePURL(purl string) (string, string, error) {
+	rest := strings.TrimPrefix(purl, "pkg:")
+	if rest == purl {
+		return "", "", xerrors.New("no pkg scheme")
+	}
+
+	// Subpath and qualifiers, e.g. "#lib" and "?distro=debian-10"
+	if i := strings.Index(rest, "#"); i >= 0 {
+		rest = rest[:i]
+	}
+	var qualifiers url.Values
+	if i := strings.Index(rest, "?"); i >= 0 {
+		var err error
+		if qualifiers, err = url.ParseQuery(rest[i+1:]); err != nil {
+			return "", "", xerrors.Errorf("invalid qualifiers: %w", err)
+		}
+		rest = rest[:i]
+	}
+	if i := strings.LastIndex(rest, "@"); i > 0 && rest[i-1] != '/' {
+		rest = rest[:i]
+	}
+
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(rest, "/"), "/") {
+		s, err := url.PathUnescape(s)
+		if err != nil {
+			return "", "", xerrors.Errorf("invalid path: %w", err)
+		}
+		segments = append(segments, s)
+	}
+	if len(segments) < 2 || segments[len(segments)-1] == "" {
+		return "", "", xerrors.New("no package name")
+	}
+	typ, path := strings.ToLower(segments[0]), segments[1:]
+	name := path[len(path)-1]
+
+	switch typ {
+	case "deb", "rpm", "apk":
+		// e.g. "debian-10" => "debian 10"
+		distro := qualifiers.Get("distro")
+		if distro == "" {
+			return "", "", xerrors.Errorf("no distro qualifier for %s", typ)
+		}
+		return strings.Replace(distro, "-", " ", 1), name, nil
+	case "pypi":
+		// PEP 503 normalization
+		return purlNamespaces[typ], strings.ReplaceAll(strings.ToLower(name), "_", "-"), nil
+	case "maven":
+		// e.g. "org.apache.logging.log4j:log4j-core"
+		return purlNamespaces[typ], strings.Join(path, ":"), nil
+	case "nuget":
+		return purlNamespaces[typ], name, nil
+	}
+
+	prefix, ok := purlNamespaces[typ]
+	if !ok {
+		return "", "", xerrors.Errorf("unsupported type: %s", typ)
+	}
+	// e.g. "@babel/core", "github.com/gin-gonic/gin" and "symfony/symfony"
+	return prefix, strings.ToLower(strings.Join(path, "/")), nil
+}
@@ -0,0 +1,100 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_GetAdvisoriesByPURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		purl    string
+		want    []types.Advisory
+		wantErr string
+	}{
+		{
+			name: "npm",
+			purl: "pkg:npm/bassmaster@1.5.1",
+			want: []types.Advisory{
+				{
+					VulnerabilityID:    "CVE-2014-7205",
+					VulnerableVersions: []string{"<=1.5.1"},
+					PatchedVersions:    []string{">=1.5.2"},
+				},
+			},
+		},
+		{
+			name: "npm with scope",
+			purl: "pkg:npm/%40babel/traverse@7.23.0",
+			want: []types.Advisory{
+				{
+					VulnerabilityID:    "CVE-2023-45133",
+					VulnerableVersions: []string{"<7.23.2"},
+					PatchedVersions:    []string{">=7.23.2"},
+				},
+			},
+		},
+		{
+			name: "pypi",
+			purl: "pkg:pypi/Django_Allauth@0.40.0",
+			want: []types.Advisory{
+				{
+					VulnerabilityID:    "CVE-2019-19844",
+					VulnerableVersions: []string{"<0.41.0"},
+					PatchedVersions:    []string{"0.41.0"},
+				},
+			},
+		},
+		{
+			name: "deb",
+			purl: "pkg:deb/debian/openssl@1.1.1d-0+deb10u6?arch=amd64&distro=debian-10",
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2021-3711",
+					FixedVersion:    "1.1.1d-0+deb10u7",
+				},
+			},
+		},
+		{
+			name: "no advisory",
+			purl: "pkg:npm/lodash@4.17.20",
+		},
+		{
+			name:    "deb without distro",
+			purl:    "pkg:deb/debian/openssl@1.1.1d-0+deb10u6",
+			wantErr: "no distro qualifier",
+		},
+		{
+			name:    "unsupported type",
+			purl:    "pkg:hex/phoenix@1.0.0",
+			wantErr: "unsupported type: hex",
+		},
+		{
+			name:    "not a purl",
+			purl:    "npm/bassmaster",
+			wantErr: "no pkg scheme",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/purl.yaml"})
+			defer db.Close()
+
+			dbc := db.Config{}
+			got, err := dbc.GetAdvisoriesByPURL(tt.purl)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// checkRangeOverlap applies the RangeOverlapPolicy to the advisory. Other types than types.Advisory are returned as is.
+func (dbc Config) checkRangeOverlap(vulnID, pkgName string, advisory interface{}) (interface{}, error) {
+	if dbc.RangeOverlapPolicy == "" || dbc.RangeOverlapPolicy == RangeOverlapIgnore {
+		return advisory, nil
+	}
+
+	var adv types.Advisory
+	switch a := advisory.(type) {
+	case types.Advisory:
+		adv = a
+	case *types.Advisory:
+		if a == nil {
+			return advisory, nil
+		}
+		adv = *a
+	default:
+		return advisory, nil
+	}
+
+	var patched []string
+	for _, p := range adv.PatchedVersions {
+		ver, overlapped := overlappingVersion(adv.VulnerableVersions, p)
+		if !overlapped {
+			patched = append(patched, p)
+			continue
+		}
+		if dbc.RangeOverlapPolicy == RangeOverlapStrict {
+			return nil, xerrors.Errorf("%s %s: %s is both vulnerable and patched (%q)", vulnID, pkgName, ver, p)
+		}
+		log.Printf("WARN: %s %s: %s is both vulnerable and patched, dropping the patched range %q\n",
+			vulnID, pkgName, ver, p)
+	}
+	if len(patched) == len(adv.PatchedVersions) {
+		return advisory, nil
+	}
+	adv.PatchedVersions = patched
+	return adv, nil
+}
+
+// overlappingVersion returns a version satisfying both one of the vulnerable constraints and the patched one.
+// Only the versions written in the constraints are checked, and constraints that cannot be parsed never overlap.
+func overlappingVersion(vulnerable []string, patched string) (string, bool) {
+	patchedCs := parseConstraints(patched)
+	if len(patchedCs) == 0 {
+		return "", false
+	}
+	for _, v := range vulnerable {
+		vulnCs := parseConstraints(v)
+		if len(vulnCs) == 0 {
+			continue
+		}
+		for _, m := range constraintVersionRegexp.FindAllStringSubmatch(v+" "+patched, -1) {
+			ver, err := version.NewVersion(m[1])
+			if err != nil {
+				continue
+			}
+			if satisfiesAny(vulnCs, ver) && satisfiesAny(patchedCs, ver) {
+				return m[1], true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseConstraints parses the constraint separated by "||", e.g. ">= 1.0, < 1.2 || >= 2.0".
+// Nil is returned if any part cannot be parsed, e.g. "^1.0".
+func parseConstraints(s string) []version.Constraints {
+	var ors []version.Constraints
+	for _, or := range strings.Split(s, "||") {
+		// Join an operator separated by spaces with the version, e.g. ">= 1.0.0"
+		var constraints []string
+		var operator string
+		for _, field := range strings.Fields(strings.ReplaceAll(or, ",", " ")) {
+			if strings.Trim(field, "<>=!~") == "" {
+				operator += field
+				continue
+			}
+			constraints = append(constraints, operator+field)
+			operator = ""
+		}
+		if len(constraints) == 0 {
+			return nil
+		}
+		c, err := version.NewConstraint(strings.Join(constraints, ", "))
+		if err != nil {
+			return nil
+		}
+		ors = append(ors, c)
+	}
+	return ors
+}
+
+func satisfiesAny(ors []version.Constraints, ver *version.Version) bool {
+	for _, c := range ors {
+		if c.Check(ver) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Reference is a reference URL with the type given by the data source, e.g. "FIX" and "ADVISORY" in OSV,
+// and the link text if any, e.g. "Link for SUSE-SU-2019:0048-2"
+type Reference struct {
+	Type  string
+	URL   string
+	Title string
+}
+
+// preferredReferenceTypes are kept first when the number of references is capped
+var preferredReferenceTypes = []string{"ADVISORY", "FIX"}
+
+// SelectReferences returns the reference URLs capped to MaxReferences.
+// Advisory and fix references are kept in preference to the others, and the original order is preserved.
+func (dbc Config) SelectReferences(refs []Reference) []string {
+	keep := make([]bool, len(refs))
+	remaining := len(refs)
+	if dbc.MaxReferences > 0 && remaining > dbc.MaxReferences {
+		remaining = dbc.MaxReferences
+	}
+
+	// Preferred references first, then the others in order
+	for _, preferred := range []bool{true, false} {
+		for i, ref := range refs {
+			if remaining == 0 {
+				break
+			}
+			if keep[i] || isPreferredReference(ref) != preferred {
+				continue
+			}
+			keep[i] = true
+			remaining--
+		}
+	}
+
+	var urls []string
+	for i, ref := range refs {
+		if keep[i] {
+			urls = append(urls, ref.URL)
+		}
+	}
+	return urls
+}
+
+func isPreferredReference(ref Reference) bool {
+	for _, t := range preferredReferenceTypes {
+		if strings.EqualFold(ref.Type, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// deniedReference returns true if the host of the reference is in ReferenceDomainDenylist or its subdomain
+func (dbc Config) deniedReference(ref string) bool {
+	if len(dbc.ReferenceDomainDenylist) == 0 {
+		return false
+	}
+	ref = strings.TrimSpace(ref)
+	u, err := url.Parse(ref)
+	if err == nil && u.Host == "" {
+		// e.g. "www.example.com/advisory"
+		u, err = url.Parse("//" + ref)
+	}
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range dbc.ReferenceDomainDenylist {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropDeniedReferences removes the references denied by ReferenceDomainDenylist
+func (dbc Config) dropDeniedReferences(refs []string) []string {
+	if len(dbc.ReferenceDomainDenylist) == 0 {
+		return refs
+	}
+	var kept []string
+	for _, ref := range refs {
+		if !dbc.deniedReference(ref) {
+			kept = append(kept, ref)
+		}
+	}
+	return kept
+}
+
+// limitReferences keeps the first MaxReferences references
+func (dbc Config) limitReferences(refs []string) []string {
+	if dbc.MaxReferences > 0 && len(refs) > dbc.MaxReferences {
+		return refs[:dbc.MaxReferences]
+	}
+	return refs
+}
+
+// ReferenceTitles returns the titles of the references keyed by the URL for types.VulnerabilityDetail,
+// or nil if no reference has a title
+func ReferenceTitles(refs []Reference) map[string]string {
+	var titles map[string]string
+	for _, ref := range refs {
+		if ref.Title == "" {
+			continue
+		}
+		if titles == nil {
+			titles = map[string]string{}
+		}
+		titles[ref.URL] = ref.Title
+	}
+	return titles
+}
+
+// keepReferenceTitles drops the titles of the URLs no longer in the references, e.g. capped by MaxReferences
+func keepReferenceTitles(urls []string, titles map[string]string) map[string]string {
+	if len(titles) == 0 {
+		return nil
+	}
+	kept := map[string]string{}
+	for _, url := range urls {
+		if title, ok := titles[url]; ok {
+			kept[url] = title
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
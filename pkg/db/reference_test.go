@@ -0,0 +1,187 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_SelectReferences(t *testing.T) {
+	refs := []db.Reference{
+		{Type: "WEB", URL: "https://example.com/blog"},
+		{Type: "PACKAGE", URL: "https://pypi.org/project/example"},
+		{Type: "FIX", URL: "https://github.com/example/example/commit/1234567"},
+		{Type: "WEB", URL: "https://example.com/mailing-list"},
+		{Type: "ADVISORY", URL: "https://github.com/advisories/GHSA-xxxx-xxxx-xxxx"},
+	}
+
+	tests := []struct {
+		name          string
+		maxReferences int
+		want          []string
+	}{
+		{
+			name:          "no limit",
+			maxReferences: 0,
+			want: []string{
+				"https://example.com/blog",
+				"https://pypi.org/project/example",
+				"https://github.com/example/example/commit/1234567",
+				"https://example.com/mailing-list",
+				"https://github.com/advisories/GHSA-xxxx-xxxx-xxxx",
+			},
+		},
+		{
+			name:          "prefer fix and advisory",
+			maxReferences: 2,
+			want: []string{
+				"https://github.com/example/example/commit/1234567",
+				"https://github.com/advisories/GHSA-xxxx-xxxx-xxxx",
+			},
+		},
+		{
+			name:          "fill with the others in order",
+			maxReferences: 3,
+			want: []string{
+				"https://example.com/blog",
+				"https://github.com/example/example/commit/1234567",
+				"https://github.com/advisories/GHSA-xxxx-xxxx-xxxx",
+			},
+		},
+		{
+			name:          "only fix",
+			maxReferences: 1,
+			want: []string{
+				"https://github.com/example/example/commit/1234567",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbc := db.Config{MaxReferences: tt.maxReferences}
+			assert.Equal(t, tt.want, dbc.SelectReferences(refs))
+		})
+	}
+}
+
+func TestConfig_PutVulnerabilityDetailReferenceTitles(t *testing.T) {
+	refs := []db.Reference{
+		{URL: "https://www.suse.com/support/update/announcement/2019/suse-su-20190048-2/", Title: "Link for SUSE-SU-2019:0048-2"},
+		{URL: "https://www.suse.com/security/cve/CVE-2018-16873/"},
+		{URL: "http://lists.suse.com/pipermail/sle-security-updates/2019-July/005660.html", Title: "E-Mail link for SUSE-SU-2019:0048-2"},
+	}
+	var urls []string
+	for _, ref := range refs {
+		urls = append(urls, ref.URL)
+	}
+
+	tests := []struct {
+		name          string
+		maxReferences int
+		want          types.VulnerabilityDetail
+	}{
+		{
+			name: "all the references",
+			want: types.VulnerabilityDetail{
+				References: urls,
+				ReferenceTitles: map[string]string{
+					"https://www.suse.com/support/update/announcement/2019/suse-su-20190048-2/":  "Link for SUSE-SU-2019:0048-2",
+					"http://lists.suse.com/pipermail/sle-security-updates/2019-July/005660.html": "E-Mail link for SUSE-SU-2019:0048-2",
+				},
+			},
+		},
+		{
+			name:          "titles of dropped references",
+			maxReferences: 2,
+			want: types.VulnerabilityDetail{
+				References: urls[:2],
+				ReferenceTitles: map[string]string{
+					"https://www.suse.com/support/update/announcement/2019/suse-su-20190048-2/": "Link for SUSE-SU-2019:0048-2",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{MaxReferences: tt.maxReferences}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				return dbc.PutVulnerabilityDetail(tx, "SUSE-SU-2019:0048-2", "suse-cvrf", types.VulnerabilityDetail{
+					References:      urls,
+					ReferenceTitles: db.ReferenceTitles(refs),
+				})
+			})
+			require.NoError(t, err)
+
+			got, err := dbc.GetVulnerabilityDetail("SUSE-SU-2019:0048-2")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got["suse-cvrf"])
+		})
+	}
+}
+
+func TestConfig_ReferenceDomainDenylist(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	dbc := db.Config{ReferenceDomainDenylist: []string{"example.com", "Exploit-DB.com"}}
+	references := []string{
+		"https://example.com/advisory",
+		"https://www.example.com/blog",
+		"www.example.com/mailing-list",
+		"https://notexample.com/advisory",
+		"https://nvd.nist.gov/vuln/detail/CVE-2021-0001",
+	}
+	exploitRefs := []string{
+		"https://www.exploit-db.com/exploits/50000",
+		"https://github.com/rapid7/metasploit-framework/pull/1",
+	}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := dbc.PutVulnerabilityDetail(tx, "CVE-2021-0001", "nvd", types.VulnerabilityDetail{
+			References: references,
+			ReferenceTitles: map[string]string{
+				"https://example.com/advisory":                   "Example advisory",
+				"https://nvd.nist.gov/vuln/detail/CVE-2021-0001": "NVD",
+			},
+			ExploitRefs: exploitRefs,
+		}); err != nil {
+			return err
+		}
+		return dbc.PutVulnerability(tx, "CVE-2021-0001", types.Vulnerability{
+			References:  references,
+			ExploitRefs: exploitRefs,
+		})
+	})
+	require.NoError(t, err)
+
+	wantReferences := []string{
+		"https://notexample.com/advisory",
+		"https://nvd.nist.gov/vuln/detail/CVE-2021-0001",
+	}
+	wantExploitRefs := []string{
+		"https://github.com/rapid7/metasploit-framework/pull/1",
+	}
+
+	details, err := dbc.GetVulnerabilityDetail("CVE-2021-0001")
+	require.NoError(t, err)
+	assert.Equal(t, types.VulnerabilityDetail{
+		References: wantReferences,
+		ReferenceTitles: map[string]string{
+			"https://nvd.nist.gov/vuln/detail/CVE-2021-0001": "NVD",
+		},
+		ExploitRefs: wantExploitRefs,
+	}, details["nvd"])
+
+	vuln, err := dbc.GetVulnerability("CVE-2021-0001")
+	require.NoError(t, err)
+	assert.Equal(t, wantReferences, vuln.References)
+	assert.Equal(t, wantExploitRefs, vuln.ExploitRefs)
+}
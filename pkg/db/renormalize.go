@@ -0,0 +1,99 @@
+package db
+
+import (
+	"encoding/json"
+	"reflect"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// RenormalizeSeverities recomputes Severity, SeveritySource and VendorSeverity of all the stored vulnerabilities
+// with the SeverityNormalizer, e.g. after the normalizer is improved. The details are taken from
+// the vulnerability-detail bucket if the DB still has it, and otherwise rebuilt from the stored vendor severities
+// and CVSS. The other fields are kept as is.
+func (dbc Config) RenormalizeSeverities() error {
+	if dbc.SeverityNormalizer == nil {
+		return xerrors.New("no severity normalizer")
+	}
+
+	err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(vulnerabilityBucket))
+		if bkt == nil {
+			return nil
+		}
+
+		// bolt doesn't allow modifying the bucket while iterating it
+		updated := map[string]types.Vulnerability{}
+		err := bkt.ForEach(func(k, v []byte) error {
+			var vuln types.Vulnerability
+			if err := json.Unmarshal(v, &vuln); err != nil {
+				return xerrors.Errorf("JSON unmarshal error (%s): %w", k, err)
+			}
+
+			details, err := dbc.storedDetails(tx, string(k), vuln)
+			if err != nil {
+				return err
+			}
+			normalized := dbc.SeverityNormalizer(details)
+			if vuln.Severity == normalized.Severity && vuln.SeveritySource == normalized.SeveritySource &&
+				reflect.DeepEqual(vuln.VendorSeverity, normalized.VendorSeverity) {
+				return nil
+			}
+
+			vuln.Severity = normalized.Severity
+			vuln.SeveritySource = normalized.SeveritySource
+			vuln.VendorSeverity = normalized.VendorSeverity
+			updated[string(k)] = vuln
+			return nil
+		})
+		if err != nil {
+			return xerrors.Errorf("vulnerability walk error: %w", err)
+		}
+
+		for cveID, vuln := range updated {
+			if err = dbc.PutVulnerability(tx, cveID, vuln); err != nil {
+				return xerrors.Errorf("failed to put the vulnerability: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to renormalize severities: %w", err)
+	}
+	return nil
+}
+
+// storedDetails returns the details of the vulnerability in the vulnerability-detail bucket,
+// or the ones rebuilt from the vendor severities and CVSS of the vulnerability if the bucket is gone after the build.
+func (dbc Config) storedDetails(tx *bolt.Tx, cveID string, vuln types.Vulnerability) (map[types.SourceID]types.VulnerabilityDetail, error) {
+	values, err := dbc.forEachTx(tx, []string{vulnerabilityDetailBucket, cveID})
+	if err != nil {
+		return nil, xerrors.Errorf("vulnerability detail error: %w", err)
+	}
+
+	details := map[types.SourceID]types.VulnerabilityDetail{}
+	if len(values) > 0 {
+		for source, value := range values {
+			var detail types.VulnerabilityDetail
+			if err = json.Unmarshal(value.Content, &detail); err != nil {
+				return nil, xerrors.Errorf("JSON unmarshal error (%s): %w", source, err)
+			}
+			details[types.SourceID(source)] = detail
+		}
+		return details, nil
+	}
+
+	for source, severity := range vuln.VendorSeverity {
+		details[source] = types.VulnerabilityDetail{Severity: severity}
+	}
+	for source, cvss := range vuln.CVSS {
+		d := details[source]
+		d.CvssScore, d.CvssVector = cvss.V2Score, cvss.V2Vector
+		d.CvssScoreV3, d.CvssVectorV3 = cvss.V3Score, cvss.V3Vector
+		details[source] = d
+	}
+	return details, nil
+}
@@ -0,0 +1,111 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+func TestConfig_RenormalizeSeverities(t *testing.T) {
+	tests := []struct {
+		name    string
+		details map[types.SourceID]types.VulnerabilityDetail
+		stored  types.Vulnerability
+		want    types.Vulnerability
+	}{
+		{
+			name: "built DB",
+			stored: types.Vulnerability{
+				Title:          "mis-normalized",
+				Severity:       "LOW",
+				SeveritySource: "redhat",
+				VendorSeverity: types.VendorSeverity{
+					"nvd":    types.SeverityCritical,
+					"redhat": types.SeverityLow,
+				},
+				CVSS: types.VendorCVSS{
+					"nvd": {
+						V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+						V3Score:  9.8,
+					},
+				},
+			},
+			want: types.Vulnerability{
+				Title:          "mis-normalized",
+				Severity:       "CRITICAL",
+				SeveritySource: "nvd",
+				SeverityRank:   4,
+				VendorSeverity: types.VendorSeverity{
+					"nvd":    types.SeverityCritical,
+					"redhat": types.SeverityLow,
+				},
+				CVSS: types.VendorCVSS{
+					"nvd": {
+						V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+						V3Score:  9.8,
+					},
+				},
+			},
+		},
+		{
+			name: "vulnerability details",
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				"nvd":    {CvssScoreV3: 7.5},
+				"debian": {Severity: types.SeverityLow},
+			},
+			stored: types.Vulnerability{
+				Title:    "mis-normalized",
+				Severity: "LOW",
+			},
+			want: types.Vulnerability{
+				Title:          "mis-normalized",
+				Severity:       "HIGH",
+				SeveritySource: "nvd",
+				SeverityRank:   3,
+				VendorSeverity: types.VendorSeverity{
+					"nvd":    types.SeverityHigh,
+					"debian": types.SeverityLow,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{SeverityNormalizer: vulnerability.New(db.Config{}).Normalize}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				for source, detail := range tt.details {
+					if err := dbc.PutVulnerabilityDetail(tx, "CVE-2021-20001", source, detail); err != nil {
+						return err
+					}
+				}
+				return dbc.PutVulnerability(tx, "CVE-2021-20001", tt.stored)
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, dbc.RenormalizeSeverities())
+
+			got, err := dbc.GetVulnerability("CVE-2021-20001")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_RenormalizeSeveritiesWithoutNormalizer(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	err := db.Config{}.RenormalizeSeverities()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no severity normalizer")
+}
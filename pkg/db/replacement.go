@@ -0,0 +1,40 @@
+package db
+
+import (
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// replacementChain holds the vulnerability IDs visited while following ReplacedBy, so that a cycle is detected
+type replacementChain map[string]struct{}
+
+// next returns the ID replacing the vulnerability ID, or an empty string if it is not replaced
+func (c replacementChain) next(vulnID, replacedBy string) (string, error) {
+	replacedBy = normalizeVulnID(replacedBy)
+	if replacedBy == "" || replacedBy == vulnID {
+		return "", nil
+	}
+	c[vulnID] = struct{}{}
+	if _, ok := c[replacedBy]; ok {
+		return "", xerrors.Errorf("replacement cycle: %s is replaced by %s", vulnID, replacedBy)
+	}
+	return replacedBy, nil
+}
+
+// detailReplacedBy returns the replacement given by any of the sources, preferring the first source in name order
+func detailReplacedBy(details map[types.SourceID]types.VulnerabilityDetail) string {
+	var sources []string
+	for source, d := range details {
+		if d.ReplacedBy != "" {
+			sources = append(sources, string(source))
+		}
+	}
+	if len(sources) == 0 {
+		return ""
+	}
+	sort.Strings(sources)
+	return details[types.SourceID(sources[0])].ReplacedBy
+}
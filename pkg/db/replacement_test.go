@@ -0,0 +1,76 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_GetVulnerabilityDetailReplacedBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		vulnID  string
+		want    map[types.SourceID]types.VulnerabilityDetail
+		wantErr string
+	}{
+		{
+			name:   "replaced",
+			vulnID: "NSWG-ECO-0",
+			want: map[types.SourceID]types.VulnerabilityDetail{
+				"nodejs-security-wg": {
+					ID:          "CVE-2017-16138",
+					Title:       "Regular Expression Denial of Service in mime",
+					CvssScoreV3: 7.5,
+				},
+			},
+		},
+		{
+			name:   "replacement",
+			vulnID: "CVE-2017-16138",
+			want: map[types.SourceID]types.VulnerabilityDetail{
+				"nodejs-security-wg": {
+					ID:          "CVE-2017-16138",
+					Title:       "Regular Expression Denial of Service in mime",
+					CvssScoreV3: 7.5,
+				},
+			},
+		},
+		{
+			name:    "cycle",
+			vulnID:  "NSWG-ECO-1",
+			wantErr: "replacement cycle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, []string{"testdata/fixtures/replaced-by.yaml"})
+			defer db.Close()
+
+			got, err := db.Config{}.GetVulnerabilityDetail(tt.vulnID)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_GetVulnerabilityReplacedBy(t *testing.T) {
+	_ = dbtest.InitDB(t, []string{"testdata/fixtures/replaced-by.yaml"})
+	defer db.Close()
+
+	got, err := db.Config{}.GetVulnerability("NSWG-ECO-0")
+	require.NoError(t, err)
+	assert.Equal(t, types.Vulnerability{
+		Title:    "Regular Expression Denial of Service in mime",
+		Severity: "HIGH",
+	}, got)
+}
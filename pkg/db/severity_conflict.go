@@ -0,0 +1,73 @@
+package db
+
+import (
+	"encoding/json"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+const (
+	severityConflictBucket = "severity-conflict"
+)
+
+// putSeverityConflict records the vendor severities of the vulnerability if they disagree by more than one level,
+// e.g. CRITICAL by NVD and MEDIUM by Red Hat. A stale record is removed if they no longer disagree.
+func (dbc Config) putSeverityConflict(tx *bolt.Tx, cveID string, vs types.VendorSeverity) error {
+	if !conflicting(vs) {
+		if bkt := tx.Bucket([]byte(severityConflictBucket)); bkt != nil {
+			if err := bkt.Delete([]byte(cveID)); err != nil {
+				return xerrors.Errorf("failed to delete the severity conflict: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := dbc.put(tx, []string{severityConflictBucket}, cveID, vs); err != nil {
+		return xerrors.Errorf("failed to put the severity conflict: %w", err)
+	}
+	return nil
+}
+
+// SeverityConflicts returns the vendor severities keyed by the vulnerability ID, recorded by PutVulnerability
+// for vulnerabilities whose severities disagree by more than one level when DetectSeverityConflicts is enabled.
+func (dbc Config) SeverityConflicts() (map[string]types.VendorSeverity, error) {
+	conflicts := map[string]types.VendorSeverity{}
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(severityConflictBucket))
+		if bkt == nil {
+			return nil
+		}
+		return bkt.ForEach(func(k, v []byte) error {
+			var vs types.VendorSeverity
+			if err := json.Unmarshal(v, &vs); err != nil {
+				return xerrors.Errorf("JSON unmarshal error (%s): %w", k, err)
+			}
+			conflicts[string(k)] = vs
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get severity conflicts: %w", err)
+	}
+	return conflicts, nil
+}
+
+// conflicting returns true if the known severities are more than one level apart
+func conflicting(vs types.VendorSeverity) bool {
+	var lowest, highest types.Severity
+	for _, s := range vs {
+		if s == types.SeverityUnknown {
+			continue
+		}
+		if lowest == types.SeverityUnknown || s < lowest {
+			lowest = s
+		}
+		if s > highest {
+			highest = s
+		}
+	}
+	return lowest != types.SeverityUnknown && highest-lowest > 1
+}
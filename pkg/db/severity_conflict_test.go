@@ -0,0 +1,62 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_SeverityConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		detect bool
+		want   map[string]types.VendorSeverity
+	}{
+		{
+			name:   "detected",
+			detect: true,
+			want: map[string]types.VendorSeverity{
+				"CVE-2021-0001": {"nvd": types.SeverityCritical, "redhat": types.SeverityMedium},
+			},
+		},
+		{
+			name: "disabled",
+			want: map[string]types.VendorSeverity{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{DetectSeverityConflicts: tt.detect}
+			vulns := map[string]types.VendorSeverity{
+				// Two levels apart
+				"CVE-2021-0001": {"nvd": types.SeverityCritical, "redhat": types.SeverityMedium},
+				// One level apart
+				"CVE-2021-0002": {"nvd": types.SeverityCritical, "redhat": types.SeverityHigh},
+				// Unknown is ignored
+				"CVE-2021-0003": {"nvd": types.SeverityUnknown, "redhat": types.SeverityHigh},
+			}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				for id, vs := range vulns {
+					if err := dbc.PutVulnerability(tx, id, types.Vulnerability{VendorSeverity: vs}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			got, err := dbc.SeverityConflicts()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
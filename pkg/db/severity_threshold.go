@@ -0,0 +1,46 @@
+package db
+
+import (
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// minSeverity returns the severity threshold of the namespace. MinSeverityPerNamespace overrides MinSeverity.
+func (dbc Config) minSeverity(namespace string) types.Severity {
+	if s, ok := dbc.MinSeverityPerNamespace[namespace]; ok {
+		return s
+	}
+	return dbc.MinSeverity
+}
+
+// belowMinSeverity returns true if the advisory is less severe than the threshold of the namespace.
+// The severity of the advisory, e.g. given by Debian per package, is preferred to the highest one
+// of the vulnerability details. Advisories of unknown severity are kept.
+func (dbc Config) belowMinSeverity(s store, namespace, vulnID string, advisory map[string]interface{}) (bool, error) {
+	min := dbc.minSeverity(namespace)
+	if min == types.SeverityUnknown {
+		return false, nil
+	}
+
+	var severity types.Severity
+	if s, ok := advisory["Severity"].(float64); ok {
+		severity = types.Severity(s)
+	}
+	if severity == types.SeverityUnknown {
+		for source, value := range s.values([]string{vulnerabilityDetailBucket, vulnID}) {
+			var detail types.VulnerabilityDetail
+			if err := json.Unmarshal(value, &detail); err != nil {
+				return false, xerrors.Errorf("JSON unmarshal error (%s): %w", source, err)
+			}
+			for _, s := range []types.Severity{detail.Severity, detail.SeverityV3} {
+				if s > severity {
+					severity = s
+				}
+			}
+		}
+	}
+	return severity != types.SeverityUnknown && severity < min, nil
+}
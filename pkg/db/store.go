@@ -0,0 +1,85 @@
+package db
+
+import (
+	"encoding/json"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+// store is the storage behind the write path shared by Config and MemoryDB,
+// so that both normalize and merge advisories the same way.
+type store interface {
+	// value returns the value in the nested buckets, or nil if it doesn't exist.
+	value(bktNames []string, key string) []byte
+	// values returns the key-values in the nested buckets, skipping child buckets.
+	values(bktNames []string) map[string][]byte
+	// countKeys counts the keys in the nested buckets, stopping after max.
+	countKeys(bktNames []string, max int) int
+	// putValue creates the nested buckets if needed and puts the value.
+	putValue(bktNames []string, key string, value []byte) error
+}
+
+func putJSON(s store, bktNames []string, key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal JSON: %w", err)
+	}
+	return s.putValue(bktNames, key, b)
+}
+
+// txStore is the store of a bolt transaction
+type txStore struct {
+	dbc Config
+	tx  *bolt.Tx
+}
+
+func (s txStore) bucket(bktNames []string) *bolt.Bucket {
+	if len(bktNames) == 0 {
+		return nil
+	}
+	bkt := s.tx.Bucket([]byte(bktNames[0]))
+	for _, bktName := range bktNames[1:] {
+		if bkt == nil {
+			return nil
+		}
+		bkt = bkt.Bucket([]byte(bktName))
+	}
+	return bkt
+}
+
+func (s txStore) value(bktNames []string, key string) []byte {
+	return s.dbc.getTx(s.tx, bktNames, key)
+}
+
+func (s txStore) values(bktNames []string) map[string][]byte {
+	bkt := s.bucket(bktNames)
+	if bkt == nil {
+		return nil
+	}
+	values := map[string][]byte{}
+	_ = bkt.ForEach(func(k, v []byte) error {
+		if v != nil {
+			values[string(k)] = v
+		}
+		return nil
+	})
+	return values
+}
+
+func (s txStore) countKeys(bktNames []string, max int) int {
+	bkt := s.bucket(bktNames)
+	if bkt == nil {
+		return 0
+	}
+	var n int
+	c := bkt.Cursor()
+	for k, _ := c.First(); k != nil && n <= max; k, _ = c.Next() {
+		n++
+	}
+	return n
+}
+
+func (s txStore) putValue(bktNames []string, key string, value []byte) error {
+	return s.dbc.putBytes(s.tx, bktNames, key, value)
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+const (
+	textIndexBucket = "text-index"
+)
+
+// putTextIndex adds the words of the texts to the inverted index, i.e. "text-index" => word => vulnerability ID
+func (dbc Config) putTextIndex(tx *bolt.Tx, vulnID string, texts ...string) error {
+	for _, word := range tokenize(texts...) {
+		if err := dbc.putBytes(tx, []string{textIndexBucket, word}, vulnID, []byte{}); err != nil {
+			return xerrors.Errorf("failed to put text index: %w", err)
+		}
+	}
+	return nil
+}
+
+// SearchText returns the sorted IDs of vulnerabilities whose title or description contains all the words in the query.
+// It requires the index built with BuildTextIndex.
+func (dbc Config) SearchText(query string) ([]string, error) {
+	words := tokenize(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	var vulnIDs []string
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(textIndexBucket))
+		if root == nil {
+			return nil
+		}
+
+		matched := map[string]int{}
+		for _, word := range words {
+			bkt := root.Bucket([]byte(word))
+			if bkt == nil {
+				return nil
+			}
+			if err := bkt.ForEach(func(vulnID, _ []byte) error {
+				matched[string(vulnID)]++
+				return nil
+			}); err != nil {
+				return xerrors.Errorf("text index foreach error: %w", err)
+			}
+		}
+
+		for vulnID, n := range matched {
+			if n == len(words) {
+				vulnIDs = append(vulnIDs, vulnID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to search text: %w", err)
+	}
+
+	sort.Strings(vulnIDs)
+	return vulnIDs, nil
+}
+
+// tokenize splits the texts into unique lowercase words
+// e.g. "executed server side via eval." => ["executed", "server", "side", "via", "eval"]
+func tokenize(texts ...string) []string {
+	uniq := map[string]struct{}{}
+	var words []string
+	for _, text := range texts {
+		fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		for _, word := range fields {
+			if _, ok := uniq[word]; ok {
+				continue
+			}
+			uniq[word] = struct{}{}
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,81 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_SearchText(t *testing.T) {
+	details := map[string]types.VulnerabilityDetail{
+		"CVE-2019-10906": {
+			Title:       "python-jinja2: str.format_map allows sandbox escape",
+			Description: "In Pallets Jinja before 2.10.1, str.format_map allows a sandbox escape.",
+		},
+		"CVE-2020-28493": {
+			Title:       "python-jinja2: ReDoS vulnerability in the urlize filter",
+			Description: "This affects the package jinja2 from 0 and before 2.11.3.",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		buildTextIndex bool
+		query          string
+		want           []string
+	}{
+		{
+			name:           "single word",
+			buildTextIndex: true,
+			query:          "Jinja2",
+			want:           []string{"CVE-2019-10906", "CVE-2020-28493"},
+		},
+		{
+			name:           "all words must match",
+			buildTextIndex: true,
+			query:          "jinja2 sandbox",
+			want:           []string{"CVE-2019-10906"},
+		},
+		{
+			name:           "no match",
+			buildTextIndex: true,
+			query:          "log4j",
+		},
+		{
+			name:           "empty query",
+			buildTextIndex: true,
+			query:          " ",
+		},
+		{
+			name:  "no index",
+			query: "jinja2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{BuildTextIndex: tt.buildTextIndex}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				for vulnID, detail := range details {
+					if err := dbc.PutVulnerabilityDetail(tx, vulnID, "nvd", detail); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			got, err := dbc.SearchText(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
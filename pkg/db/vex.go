@@ -0,0 +1,335 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// Component is a package to be looked up in ExportVEX
+type Component struct {
+	Source  string // namespace such as "debian 10" or "npm::", which is handled in the same way as GetAdvisories
+	Name    string
+	Version string // compared with the vulnerable and patched versions of the advisories if given
+}
+
+func (c Component) bomRef() string {
+	if c.Version == "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s@%s", c.Name, c.Version)
+}
+
+// cdxDocument is a minimal CycloneDX 1.4 document
+type cdxDocument struct {
+	BOMFormat       string             `json:"bomFormat"`
+	SpecVersion     string             `json:"specVersion"`
+	Version         int                `json:"version"`
+	Components      []cdxComponent     `json:"components"`
+	Vulnerabilities []cdxVulnerability `json:"vulnerabilities"`
+}
+
+type cdxComponent struct {
+	BOMRef  string `json:"bom-ref"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type cdxVulnerability struct {
+	ID          string        `json:"id"`
+	Source      *cdxSource    `json:"source,omitempty"`
+	Ratings     []cdxRating   `json:"ratings,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Advisories  []cdxAdvisory `json:"advisories,omitempty"`
+	Analysis    *cdxAnalysis  `json:"analysis,omitempty"`
+	Affects     []cdxAffect   `json:"affects"`
+}
+
+type cdxSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cdxRating struct {
+	Source   cdxSource `json:"source"`
+	Score    float64   `json:"score,omitempty"`
+	Severity string    `json:"severity,omitempty"`
+	Method   string    `json:"method,omitempty"`
+	Vector   string    `json:"vector,omitempty"`
+}
+
+type cdxAdvisory struct {
+	URL string `json:"url"`
+}
+
+type cdxAnalysis struct {
+	State string `json:"state"`
+}
+
+type cdxAffect struct {
+	Ref      string       `json:"ref"`
+	Versions []cdxVersion `json:"versions,omitempty"`
+}
+
+type cdxVersion struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// vexState is the state of a component for an advisory
+type vexState int
+
+const (
+	vexAffected vexState = iota
+	vexFixed
+	vexNotAffected
+)
+
+// ExportVEX looks up advisories of the components and writes them as a CycloneDX document to w.
+// The ratings come from CVSS scores in the vulnerability bucket and the references are emitted as advisories.
+// Versions of the components are compared with VersionComparer, so that each affected component is marked
+// as "affected" or "unaffected", and vulnerabilities affecting none of the components are analyzed as
+// "resolved" if the components have the fix or "not_affected" otherwise.
+// Without VersionComparer or the version of a component, the component is regarded as affected.
+func (dbc Config) ExportVEX(components []Component, w io.Writer) error {
+	doc := cdxDocument{
+		BOMFormat:       "CycloneDX",
+		SpecVersion:     "1.4",
+		Version:         1,
+		Components:      []cdxComponent{},
+		Vulnerabilities: []cdxVulnerability{},
+	}
+
+	vulns := map[string]*cdxVulnerability{}
+	states := map[string][]vexState{}
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		for _, c := range components {
+			ref := c.bomRef()
+			doc.Components = append(doc.Components, cdxComponent{
+				BOMRef:  ref,
+				Type:    "library",
+				Name:    c.Name,
+				Version: c.Version,
+			})
+
+			advisories, err := dbc.getAdvisories(tx, c.Source, c.Name)
+			if err != nil {
+				return xerrors.Errorf("failed to get advisories for %s: %w", c.Name, err)
+			}
+
+			for _, adv := range advisories {
+				v, ok := vulns[adv.VulnerabilityID]
+				if !ok {
+					v, err = dbc.newCDXVulnerability(tx, adv)
+					if err != nil {
+						return err
+					}
+					vulns[adv.VulnerabilityID] = v
+				}
+
+				state, compared := dbc.vexState(c, adv)
+				states[adv.VulnerabilityID] = append(states[adv.VulnerabilityID], state)
+				affect := cdxAffect{Ref: ref}
+				if compared {
+					status := "affected"
+					if state != vexAffected {
+						status = "unaffected"
+					}
+					affect.Versions = []cdxVersion{{Version: c.Version, Status: status}}
+				}
+				v.Affects = append(v.Affects, affect)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to export VEX: %w", err)
+	}
+
+	for vulnID, v := range vulns {
+		v.Analysis = vexAnalysis(states[vulnID])
+		doc.Vulnerabilities = append(doc.Vulnerabilities, *v)
+	}
+	sort.Slice(doc.Vulnerabilities, func(i, j int) bool {
+		return doc.Vulnerabilities[i].ID < doc.Vulnerabilities[j].ID
+	})
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	if err = e.Encode(doc); err != nil {
+		return xerrors.Errorf("JSON encode error: %w", err)
+	}
+	return nil
+}
+
+func (dbc Config) newCDXVulnerability(tx *bolt.Tx, adv types.Advisory) (*cdxVulnerability, error) {
+	v := &cdxVulnerability{ID: adv.VulnerabilityID}
+	if adv.DataSource != nil {
+		v.Source = &cdxSource{Name: adv.DataSource.Name, URL: adv.DataSource.URL}
+	}
+
+	b := dbc.getTx(tx, []string{vulnerabilityBucket}, adv.VulnerabilityID)
+	if b == nil {
+		return v, nil
+	}
+	var vuln types.Vulnerability
+	if err := json.Unmarshal(b, &vuln); err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal the vulnerability %s: %w", adv.VulnerabilityID, err)
+	}
+
+	v.Description = vuln.Description
+	for _, ref := range vuln.References {
+		v.Advisories = append(v.Advisories, cdxAdvisory{URL: ref})
+	}
+
+	var sources []string
+	for source := range vuln.CVSS {
+		sources = append(sources, string(source))
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		cvss := vuln.CVSS[types.SourceID(source)]
+		severity := ""
+		if sev, ok := vuln.VendorSeverity[types.SourceID(source)]; ok {
+			severity = strings.ToLower(sev.String())
+		}
+		if cvss.V3Score > 0 {
+			v.Ratings = append(v.Ratings, cdxRating{
+				Source:   cdxSource{Name: source},
+				Score:    cvss.V3Score,
+				Severity: severity,
+				Method:   cvssV3Method(cvss.V3Vector),
+				Vector:   cvss.V3Vector,
+			})
+		}
+		if cvss.V2Score > 0 {
+			v.Ratings = append(v.Ratings, cdxRating{
+				Source:   cdxSource{Name: source},
+				Score:    cvss.V2Score,
+				Severity: severity,
+				Method:   "CVSSv2",
+				Vector:   cvss.V2Vector,
+			})
+		}
+	}
+	return v, nil
+}
+
+// vexAnalysis returns the analysis of the vulnerability affecting none of the components, e.g. all fixed
+func vexAnalysis(states []vexState) *cdxAnalysis {
+	resolved := false
+	for _, s := range states {
+		switch s {
+		case vexAffected:
+			return nil
+		case vexFixed:
+			resolved = true
+		}
+	}
+	if resolved {
+		return &cdxAnalysis{State: "resolved"}
+	}
+	return &cdxAnalysis{State: "not_affected"}
+}
+
+// vexState returns the state of the component for the advisory, and true if the version of the component was compared.
+// Components are regarded as affected unless the version is proved to be fixed or out of the vulnerable versions.
+func (dbc Config) vexState(c Component, adv types.Advisory) (vexState, bool) {
+	if adv.Status == types.StatusNotAffected {
+		return vexNotAffected, false
+	}
+	if c.Version == "" || dbc.VersionComparer == nil || adv.AffectsAllVersions() {
+		return vexAffected, false
+	}
+
+	// OS packages
+	if adv.FixedVersion != "" {
+		if dbc.VersionComparer(c.Source, c.Version, adv.FixedVersion) >= 0 {
+			return vexFixed, true
+		}
+		if adv.IntroducedVersion != "" && dbc.VersionComparer(c.Source, c.Version, adv.IntroducedVersion) < 0 {
+			return vexNotAffected, true
+		}
+		return vexAffected, true
+	}
+
+	if len(adv.VulnerableVersions) > 0 {
+		vulnerable, ok := dbc.satisfiesAny(c.Source, c.Version, adv.VulnerableVersions)
+		if !ok || vulnerable {
+			return vexAffected, ok
+		}
+		if patched, ok := dbc.satisfiesAny(c.Source, c.Version, adv.PatchedVersions); ok && patched {
+			return vexFixed, true
+		}
+		return vexNotAffected, true
+	}
+
+	// The affected range cannot be derived from patched versions alone
+	if patched, ok := dbc.satisfiesAny(c.Source, c.Version, adv.PatchedVersions); ok && patched {
+		return vexFixed, true
+	}
+	return vexAffected, false
+}
+
+// satisfiesAny returns true if the version satisfies any of the constraints, e.g. ">=1.2.0, <1.2.5 || >=2.0.0".
+// It returns false as the second value if any constraint cannot be evaluated, e.g. "~> 1.1.5".
+func (dbc Config) satisfiesAny(namespace, version string, constraints []string) (bool, bool) {
+	var satisfied bool
+	for _, constraint := range constraints {
+		for _, alt := range strings.Split(constraint, "||") {
+			ok, valid := dbc.satisfies(namespace, version, alt)
+			if !valid {
+				return false, false
+			}
+			satisfied = satisfied || ok
+		}
+	}
+	return satisfied, true
+}
+
+// satisfies returns true if the version satisfies all the terms of the constraint, e.g. ">=1.2.0, <1.2.5"
+func (dbc Config) satisfies(namespace, version, constraint string) (bool, bool) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" || strings.ContainsAny(constraint, "~^!") {
+		return false, false
+	}
+	for _, m := range constraintRegexp.FindAllStringSubmatch(constraint, -1) {
+		op, ver := m[1], m[2]
+		if ver == types.AllVersions {
+			continue
+		}
+		c := dbc.VersionComparer(namespace, version, ver)
+		var ok bool
+		switch op {
+		case ">=":
+			ok = c >= 0
+		case ">":
+			ok = c > 0
+		case "<=":
+			ok = c <= 0
+		case "<":
+			ok = c < 0
+		default:
+			ok = c == 0
+		}
+		if !ok {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func cvssV3Method(vector string) string {
+	if strings.HasPrefix(vector, "CVSS:3.1/") {
+		return "CVSSv31"
+	}
+	return "CVSSv3"
+}
@@ -0,0 +1,143 @@
+package db_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+func TestConfig_ExportVEX(t *testing.T) {
+	_ = dbtest.InitDB(t, []string{"testdata/fixtures/vex.yaml"})
+	defer db.Close()
+
+	components := []db.Component{
+		{
+			Source:  "npm::",
+			Name:    "lodash",
+			Version: "4.17.11",
+		},
+		{
+			Source:  "npm::",
+			Name:    "minimist",
+			Version: "1.2.0",
+		},
+	}
+
+	var buf bytes.Buffer
+	dbc := db.Config{}
+	err := dbc.ExportVEX(components, &buf)
+	require.NoError(t, err)
+
+	want := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"bom-ref": "lodash@4.17.11", "type": "library", "name": "lodash", "version": "4.17.11"},
+    {"bom-ref": "minimist@1.2.0", "type": "library", "name": "minimist", "version": "1.2.0"}
+  ],
+  "vulnerabilities": [
+    {
+      "id": "CVE-2019-10744",
+      "description": "Prototype pollution in lodash",
+      "ratings": [
+        {
+          "source": {"name": "nodejs-security-wg"},
+          "score": 9.1,
+          "severity": "critical",
+          "method": "CVSSv31",
+          "vector": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:H"
+        }
+      ],
+      "advisories": [{"url": "https://github.com/lodash/lodash/pull/4336"}],
+      "affects": [{"ref": "lodash@4.17.11"}]
+    },
+    {
+      "id": "CVE-2020-7598",
+      "affects": [{"ref": "minimist@1.2.0"}]
+    }
+  ]
+}`
+	assert.JSONEq(t, want, buf.String())
+}
+
+func TestConfig_ExportVEXVersions(t *testing.T) {
+	_ = dbtest.InitDB(t, []string{"testdata/fixtures/vex-versions.yaml"})
+	defer db.Close()
+
+	components := []db.Component{
+		{
+			Source:  "npm::",
+			Name:    "lodash",
+			Version: "4.17.11",
+		},
+		{
+			Source:  "npm::",
+			Name:    "lodash",
+			Version: "4.17.12",
+		},
+		{
+			Source:  "npm::",
+			Name:    "minimist",
+			Version: "0.2.4",
+		},
+		{
+			Source:  "debian 10",
+			Name:    "curl",
+			Version: "7.64.0-4",
+		},
+	}
+
+	var buf bytes.Buffer
+	dbc := db.Config{VersionComparer: vulnerability.CompareInNamespace}
+	err := dbc.ExportVEX(components, &buf)
+	require.NoError(t, err)
+
+	want := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"bom-ref": "lodash@4.17.11", "type": "library", "name": "lodash", "version": "4.17.11"},
+    {"bom-ref": "lodash@4.17.12", "type": "library", "name": "lodash", "version": "4.17.12"},
+    {"bom-ref": "minimist@0.2.4", "type": "library", "name": "minimist", "version": "0.2.4"},
+    {"bom-ref": "curl@7.64.0-4", "type": "library", "name": "curl", "version": "7.64.0-4"}
+  ],
+  "vulnerabilities": [
+    {
+      "id": "CVE-2019-10744",
+      "affects": [
+        {"ref": "lodash@4.17.11", "versions": [{"version": "4.17.11", "status": "affected"}]},
+        {"ref": "lodash@4.17.12", "versions": [{"version": "4.17.12", "status": "unaffected"}]}
+      ]
+    },
+    {
+      "id": "CVE-2020-7598",
+      "affects": [
+        {"ref": "minimist@0.2.4", "versions": [{"version": "0.2.4", "status": "affected"}]}
+      ]
+    },
+    {
+      "id": "CVE-2021-22922",
+      "analysis": {"state": "not_affected"},
+      "affects": [
+        {"ref": "curl@7.64.0-4", "versions": [{"version": "7.64.0-4", "status": "unaffected"}]}
+      ]
+    },
+    {
+      "id": "CVE-2021-44906",
+      "analysis": {"state": "resolved"},
+      "affects": [
+        {"ref": "minimist@0.2.4", "versions": [{"version": "0.2.4", "status": "unaffected"}]}
+      ]
+    }
+  ]
+}`
+	assert.JSONEq(t, want, buf.String())
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/aquasecurity/trivy-db/pkg/types"
 
@@ -13,15 +14,139 @@ const (
 	vulnerabilityBucket = "vulnerability"
 )
 
+// PutVulnerability stores the vulnerability.
+// FirstSeen of the stored vulnerability is preserved so that it keeps the time of the initial insert.
+// Conflicting vendor severities are recorded as well if DetectSeverityConflicts is enabled. See SeverityConflicts.
 func (dbc Config) PutVulnerability(tx *bolt.Tx, cveID string, vuln types.Vulnerability) error {
+	cveID = normalizeVulnID(cveID)
+
+	vuln, err := dbc.reconcileCVSS(vuln)
+	if err != nil {
+		return xerrors.Errorf("failed to reconcile CVSS: %w", err)
+	}
+
+	// Keep the rank in sync with the severity. Unknown names are ranked as UNKNOWN.
+	severity, _ := types.NewSeverity(vuln.Severity)
+	vuln.SeverityRank = int(severity)
+
+	if b := dbc.getTx(tx, []string{vulnerabilityBucket}, cveID); b != nil {
+		var stored types.Vulnerability
+		if err := json.Unmarshal(b, &stored); err != nil {
+			return xerrors.Errorf("failed to unmarshal the stored vulnerability: %w", err)
+		}
+		if stored.FirstSeen != nil {
+			vuln.FirstSeen = stored.FirstSeen
+		}
+	}
+
+	vuln.References = dbc.dropDeniedReferences(vuln.References)
+	vuln.ReferenceTitles = keepReferenceTitles(vuln.References, vuln.ReferenceTitles)
+	vuln.ExploitRefs = dbc.dropDeniedReferences(vuln.ExploitRefs)
+
+	if dbc.CvssPrecision > 0 && len(vuln.CVSS) > 0 {
+		cvss := make(types.VendorCVSS, len(vuln.CVSS))
+		for vendor, c := range vuln.CVSS {
+			c.V2Score = dbc.roundScore(c.V2Score)
+			c.V3Score = dbc.roundScore(c.V3Score)
+			cvss[vendor] = c
+		}
+		vuln.CVSS = cvss
+	}
+
 	if err := dbc.put(tx, []string{vulnerabilityBucket}, cveID, vuln); err != nil {
 		return xerrors.Errorf("failed to put severity: %w", err)
 	}
+	dbc.cacheInvalidate(tx, cacheKey(vulnerabilityBucket, cveID))
+
+	if dbc.DetectSeverityConflicts {
+		if err := dbc.putSeverityConflict(tx, cveID, vuln.VendorSeverity); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (dbc Config) GetVulnerability(cveID string) (vuln types.Vulnerability, err error) {
-	err = db.View(func(tx *bolt.Tx) error {
+// reconcileCVSS selects the severity from the CVSS scores of all the sources according to CvssMergePolicy,
+// e.g. CRITICAL of NVD 9.8 over MEDIUM of Red Hat 5.3 under CvssMax. V3 scores are preferred to v2 ones.
+func (dbc Config) reconcileCVSS(vuln types.Vulnerability) (types.Vulnerability, error) {
+	policy, err := ParseCvssMergePolicy(string(dbc.CvssMergePolicy))
+	if err != nil {
+		return vuln, err
+	}
+
+	var (
+		source types.SourceID
+		score  float64
+	)
+	switch policy {
+	case CvssMax:
+		var sources []string
+		for s := range vuln.CVSS {
+			sources = append(sources, string(s))
+		}
+		sort.Strings(sources)
+		for _, v3 := range []bool{true, false} {
+			for _, s := range sources {
+				if sc := cvssScore(vuln.CVSS[types.SourceID(s)], v3); sc > score {
+					source, score = types.SourceID(s), sc
+				}
+			}
+			if score > 0 {
+				break
+			}
+		}
+	case CvssNVDFirst:
+		if c, ok := vuln.CVSS[nvdSource]; ok {
+			source, score = nvdSource, cvssScore(c, true)
+			if score <= 0 {
+				score = cvssScore(c, false)
+			}
+		}
+	}
+	if score <= 0 {
+		return vuln, nil
+	}
+
+	vuln.Severity = types.CvssSeverity(score).String()
+	vuln.SeveritySource = string(source)
+	return vuln, nil
+}
+
+func cvssScore(c types.CVSS, v3 bool) float64 {
+	if v3 {
+		return c.V3Score
+	}
+	return c.V2Score
+}
+
+// GetVulnerability returns the vulnerability. If it is replaced by another ID, the replacement is returned instead.
+func (dbc Config) GetVulnerability(cveID string) (types.Vulnerability, error) {
+	chain := replacementChain{}
+	cveID = normalizeVulnID(cveID)
+	for {
+		vuln, err := dbc.getVulnerability(cveID)
+		if err != nil {
+			return types.Vulnerability{}, err
+		}
+		replacement, err := chain.next(cveID, vuln.ReplacedBy)
+		if err != nil {
+			return types.Vulnerability{}, xerrors.Errorf("failed to follow the replacement: %w", err)
+		} else if replacement == "" {
+			return vuln, nil
+		}
+		cveID = replacement
+	}
+}
+
+func (dbc Config) getVulnerability(cveID string) (vuln types.Vulnerability, err error) {
+	key := cacheKey(vulnerabilityBucket, cveID)
+	var cached types.Vulnerability
+	if dbc.cacheGet(key, &cached) {
+		return cached, nil
+	}
+
+	generation := dbc.cacheGeneration()
+	err = dbc.Connection().View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(vulnerabilityBucket))
 		value := bucket.Get([]byte(cveID))
 		if err = json.Unmarshal(value, &vuln); err != nil {
@@ -32,5 +157,6 @@ func (dbc Config) GetVulnerability(cveID string) (vuln types.Vulnerability, err
 	if err != nil {
 		return types.Vulnerability{}, xerrors.Errorf("failed to get the vulnerability: %w", err)
 	}
+	dbc.cacheSet(key, vuln, generation)
 	return vuln, nil
 }
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"encoding/json"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// vulnerabilityAliasBucket is a union-find forest of vulnerability IDs, i.e. vulnerability ID => parent ID.
+	// IDs not in the bucket are the representatives of their own clusters.
+	vulnerabilityAliasBucket = "vulnerability-alias"
+)
+
+// PutVulnerabilityAlias links the IDs of the same vulnerability, e.g. a CVE ID and a GHSA ID.
+// Links are transitive, so IDs linked through chains such as CVE => GHSA => DSA share the same CanonicalID.
+func (dbc Config) PutVulnerabilityAlias(tx *bolt.Tx, vulnID, alias string) error {
+	put := func(id, p string) error {
+		return dbc.put(tx, []string{vulnerabilityAliasBucket}, id, p)
+	}
+	if err := unionAliases(dbc.aliasParent(tx), put, vulnID, alias); err != nil {
+		return xerrors.Errorf("failed to put the vulnerability alias: %w", err)
+	}
+	return nil
+}
+
+// CanonicalID returns the representative of the alias cluster the vulnerability ID belongs to.
+// A CVE ID is preferred, otherwise the smallest ID. IDs without aliases are returned as is.
+func (dbc Config) CanonicalID(vulnID string) (string, error) {
+	var canonical string
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		var err error
+		canonical, err = findAlias(dbc.aliasParent(tx), normalizeVulnID(vulnID))
+		return err
+	})
+	if err != nil {
+		return "", xerrors.Errorf("failed to get the canonical ID of %s: %w", vulnID, err)
+	}
+	return canonical, nil
+}
+
+// aliasParent returns the function reading the parent of a vulnerability ID in the transaction
+func (dbc Config) aliasParent(tx *bolt.Tx) func(id string) (string, error) {
+	return func(id string) (string, error) {
+		b := dbc.getTx(tx, []string{vulnerabilityAliasBucket}, id)
+		if b == nil {
+			return "", nil
+		}
+		var p string
+		if err := json.Unmarshal(b, &p); err != nil {
+			return "", xerrors.Errorf("JSON unmarshal error: %w", err)
+		}
+		return p, nil
+	}
+}
+
+// findAlias returns the root of the ID, following the parents returned by parent. An empty parent means the root.
+func findAlias(parent func(id string) (string, error), id string) (string, error) {
+	seen := map[string]struct{}{}
+	for {
+		p, err := parent(id)
+		if err != nil {
+			return "", err
+		} else if p == "" || p == id {
+			return id, nil
+		}
+		if _, ok := seen[id]; ok {
+			return "", xerrors.Errorf("alias cycle at %s", id)
+		}
+		seen[id] = struct{}{}
+		id = p
+	}
+}
+
+// unionAliases merges the clusters of the IDs under the preferred root and points both IDs at it,
+// so that lookups of the IDs given by sources take a single step.
+func unionAliases(parent func(id string) (string, error), put func(id, parent string) error, vulnID, alias string) error {
+	vulnID, alias = normalizeVulnID(vulnID), normalizeVulnID(alias)
+	if vulnID == "" || alias == "" || vulnID == alias {
+		return nil
+	}
+
+	root1, err := findAlias(parent, vulnID)
+	if err != nil {
+		return err
+	}
+	root2, err := findAlias(parent, alias)
+	if err != nil {
+		return err
+	}
+
+	root := root1
+	if preferredAlias(root2, root1) {
+		root = root2
+	}
+	for _, id := range []string{root1, root2, vulnID, alias} {
+		if id == root {
+			continue
+		}
+		if err = put(id, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preferredAlias returns true if a represents the cluster rather than b. CVE IDs are preferred as the most common ones.
+func preferredAlias(a, b string) bool {
+	aCVE, bCVE := strings.HasPrefix(a, "CVE-"), strings.HasPrefix(b, "CVE-")
+	if aCVE != bCVE {
+		return aCVE
+	}
+	return a < b
+}
@@ -0,0 +1,77 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+)
+
+func TestConfig_CanonicalID(t *testing.T) {
+	tests := []struct {
+		name  string
+		links [][2]string
+		ids   []string
+		want  string
+	}{
+		{
+			name: "chain",
+			links: [][2]string{
+				{"CVE-2020-7598", "GHSA-vh95-rmgr-6w4m"},
+				{"GHSA-vh95-rmgr-6w4m", "DSA-5122-1"},
+			},
+			ids:  []string{"CVE-2020-7598", "GHSA-vh95-rmgr-6w4m", "DSA-5122-1"},
+			want: "CVE-2020-7598",
+		},
+		{
+			name: "clusters merged later",
+			links: [][2]string{
+				{"DSA-5122-1", "GHSA-vh95-rmgr-6w4m"},
+				{"OSV-2020-111", "cve-2020-7598"},
+				{"GHSA-vh95-rmgr-6w4m", "OSV-2020-111"},
+			},
+			ids:  []string{"CVE-2020-7598", "GHSA-vh95-rmgr-6w4m", "DSA-5122-1", "OSV-2020-111"},
+			want: "CVE-2020-7598",
+		},
+		{
+			name: "without CVE",
+			links: [][2]string{
+				{"PYSEC-2021-63", "GHSA-jq4v-f5q6-mjqq"},
+			},
+			ids:  []string{"PYSEC-2021-63", "GHSA-jq4v-f5q6-mjqq"},
+			want: "GHSA-jq4v-f5q6-mjqq",
+		},
+		{
+			name: "no alias",
+			ids:  []string{"CVE-2021-3711"},
+			want: "CVE-2021-3711",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				for _, link := range tt.links {
+					if err := dbc.PutVulnerabilityAlias(tx, link[0], link[1]); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			for _, id := range tt.ids {
+				got, err := dbc.CanonicalID(id)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got, id)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"encoding/json"
+	"math"
 
 	"github.com/aquasecurity/trivy-db/pkg/types"
 
@@ -11,16 +12,120 @@ import (
 
 const (
 	vulnerabilityDetailBucket = "vulnerability-detail"
+
+	// nvdSource is vulnerability.NVD, which cannot be imported here
+	nvdSource types.SourceID = "nvd"
 )
 
 func (dbc Config) PutVulnerabilityDetail(tx *bolt.Tx, cveID string, source types.SourceID, vuln types.VulnerabilityDetail) error {
+	cveID = normalizeVulnID(cveID)
+	if dbc.AdvisoriesOnly || dbc.filteredOut(cveID) {
+		return nil
+	}
+	vuln.References = dbc.limitReferences(dbc.dropDeniedReferences(vuln.References))
+	vuln.ExploitRefs = dbc.dropDeniedReferences(vuln.ExploitRefs)
+	vuln.ReferenceTitles = keepReferenceTitles(vuln.References, vuln.ReferenceTitles)
+	vuln, err := dbc.mergeCVSS(tx, cveID, source, vuln)
+	if err != nil {
+		return xerrors.Errorf("failed to merge CVSS: %w", err)
+	}
+	vuln.CvssScore = dbc.roundScore(normalizeNoScore(vuln.CvssScore))
+	vuln.CvssScoreV3 = dbc.roundScore(normalizeNoScore(vuln.CvssScoreV3))
 	if err := dbc.put(tx, []string{vulnerabilityDetailBucket, cveID}, string(source), vuln); err != nil {
 		return xerrors.Errorf("failed to put vulnerability detail: %w", err)
 	}
+	dbc.countVulnerability(source)
+	if dbc.BuildTextIndex {
+		if err := dbc.putTextIndex(tx, cveID, vuln.Title, vuln.Description); err != nil {
+			return xerrors.Errorf("text index error: %w", err)
+		}
+	}
+	if dbc.BuildCPEIndex {
+		if err := dbc.putCPEIndex(tx, cveID, vuln.CPEs); err != nil {
+			return xerrors.Errorf("CPE index error: %w", err)
+		}
+	}
 	return nil
 }
 
+// normalizeNoScore unifies negative scores into types.NoCvssScore
+func normalizeNoScore(score float64) float64 {
+	if score < 0 {
+		return types.NoCvssScore
+	}
+	return score
+}
+
+// roundScore rounds the CVSS score to CvssPrecision decimal places
+func (dbc Config) roundScore(score float64) float64 {
+	if dbc.CvssPrecision <= 0 {
+		return score
+	}
+	p := math.Pow10(dbc.CvssPrecision)
+	return math.Round(score*p) / p
+}
+
+// mergeCVSS takes the CVSS scores from the stored detail according to CvssMergePolicy
+func (dbc Config) mergeCVSS(tx *bolt.Tx, cveID string, source types.SourceID, vuln types.VulnerabilityDetail) (types.VulnerabilityDetail, error) {
+	policy, err := ParseCvssMergePolicy(string(dbc.CvssMergePolicy))
+	if err != nil {
+		return vuln, err
+	} else if policy == CvssLastWrite {
+		return vuln, nil
+	}
+
+	b := dbc.getTx(tx, []string{vulnerabilityDetailBucket, cveID}, string(source))
+	if b == nil {
+		return vuln, nil
+	}
+	var stored types.VulnerabilityDetail
+	if err := json.Unmarshal(b, &stored); err != nil {
+		return vuln, xerrors.Errorf("failed to unmarshal the vulnerability detail: %w", err)
+	}
+
+	switch policy {
+	case CvssMax:
+		if stored.CvssScore > vuln.CvssScore {
+			vuln.CvssScore, vuln.CvssVector = stored.CvssScore, stored.CvssVector
+		}
+		if stored.CvssScoreV3 > vuln.CvssScoreV3 {
+			vuln.CvssScoreV3, vuln.CvssVectorV3 = stored.CvssScoreV3, stored.CvssVectorV3
+		}
+	case CvssNVDFirst:
+		if source != nvdSource {
+			break
+		}
+		if stored.CvssScore > 0 {
+			vuln.CvssScore, vuln.CvssVector = stored.CvssScore, stored.CvssVector
+		}
+		if stored.CvssScoreV3 > 0 {
+			vuln.CvssScoreV3, vuln.CvssVectorV3 = stored.CvssScoreV3, stored.CvssVectorV3
+		}
+	}
+	return vuln, nil
+}
+
+// GetVulnerabilityDetail returns the details per source.
+// If a source tells the ID is replaced by another ID, the details of the replacement are returned instead.
 func (dbc Config) GetVulnerabilityDetail(cveID string) (map[types.SourceID]types.VulnerabilityDetail, error) {
+	chain := replacementChain{}
+	cveID = normalizeVulnID(cveID)
+	for {
+		details, err := dbc.getVulnerabilityDetail(cveID)
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := chain.next(cveID, detailReplacedBy(details))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to follow the replacement: %w", err)
+		} else if replacement == "" {
+			return details, nil
+		}
+		cveID = replacement
+	}
+}
+
+func (dbc Config) getVulnerabilityDetail(cveID string) (map[types.SourceID]types.VulnerabilityDetail, error) {
 	values, err := dbc.forEach([]string{vulnerabilityDetailBucket, cveID})
 	if err != nil {
 		return nil, xerrors.Errorf("error in NVD get: %w", err)
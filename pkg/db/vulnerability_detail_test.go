@@ -0,0 +1,318 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_PutVulnerabilityDetail(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	detail := types.VulnerabilityDetail{
+		ID:          "CVE-2021-20001",
+		Title:       "Cross-site scripting in Example",
+		Description: "Example allows remote attackers to inject arbitrary web script.",
+		Localized: map[string]types.LocalizedText{
+			"ja": {
+				Title:       "Example におけるクロスサイトスクリプティングの脆弱性",
+				Description: "Example には、任意のウェブスクリプトを注入される脆弱性が存在します。",
+			},
+		},
+	}
+
+	dbc := db.Config{}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.PutVulnerabilityDetail(tx, "CVE-2021-20001", "jvn", detail)
+	})
+	require.NoError(t, err)
+
+	got, err := dbc.GetVulnerabilityDetail("CVE-2021-20001")
+	require.NoError(t, err)
+	assert.Equal(t, map[types.SourceID]types.VulnerabilityDetail{
+		"jvn": detail,
+	}, got)
+	assert.Equal(t, "Cross-site scripting in Example", got["jvn"].Title)
+	assert.Equal(t, "Example におけるクロスサイトスクリプティングの脆弱性", got["jvn"].Localized["ja"].Title)
+}
+
+func TestConfig_PutVulnerabilityDetailLowercaseID(t *testing.T) {
+	tmpDir := dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	detail := types.VulnerabilityDetail{
+		Title: "node-tar arbitrary file overwrite",
+	}
+	advisory := types.Advisory{
+		PatchedVersions: []string{">=2.0.0"},
+	}
+
+	dbc := db.Config{}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := dbc.PutVulnerabilityID(tx, "cve-2014-7205"); err != nil {
+			return err
+		}
+		if err := dbc.PutVulnerabilityDetail(tx, "cve-2014-7205", "nvd", detail); err != nil {
+			return err
+		}
+		if err := dbc.PutVulnerabilityDetail(tx, "CVE-2014-7205", "ghsa", detail); err != nil {
+			return err
+		}
+		// Only CVE IDs are normalized
+		if err := dbc.PutVulnerabilityID(tx, "GHSA-jf85-cpcp-j695"); err != nil {
+			return err
+		}
+		return dbc.PutAdvisoryDetail(tx, "cve-2014-7205", "tar", []string{"npm::Node.js Ecosystem Security Working Group"}, advisory)
+	})
+	require.NoError(t, err)
+
+	got, err := dbc.GetVulnerabilityDetail("CVE-2014-7205")
+	require.NoError(t, err)
+	assert.Equal(t, map[types.SourceID]types.VulnerabilityDetail{
+		"nvd":  detail,
+		"ghsa": detail,
+	}, got)
+
+	require.NoError(t, db.Close())
+	dbPath := db.Path(tmpDir)
+	dbtest.JSONEq(t, dbPath, []string{"vulnerability-id", "CVE-2014-7205"}, map[string]interface{}{})
+	dbtest.JSONEq(t, dbPath, []string{"vulnerability-id", "GHSA-jf85-cpcp-j695"}, map[string]interface{}{})
+	dbtest.NoKey(t, dbPath, []string{"vulnerability-id", "cve-2014-7205"})
+	dbtest.NoBucket(t, dbPath, []string{"vulnerability-detail", "cve-2014-7205"})
+	dbtest.JSONEq(t, dbPath, []string{"advisory-detail", "CVE-2014-7205", "npm::Node.js Ecosystem Security Working Group", "tar"}, advisory)
+	dbtest.NoBucket(t, dbPath, []string{"advisory-detail", "cve-2014-7205"})
+}
+
+func TestConfig_PutVulnerabilityDetailMaxReferences(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	detail := types.VulnerabilityDetail{
+		References: []string{
+			"https://example.com/1",
+			"https://example.com/2",
+			"https://example.com/3",
+		},
+	}
+
+	dbc := db.Config{MaxReferences: 2}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.PutVulnerabilityDetail(tx, "CVE-2021-20001", "nvd", detail)
+	})
+	require.NoError(t, err)
+
+	got, err := dbc.GetVulnerabilityDetail("CVE-2021-20001")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://example.com/1",
+		"https://example.com/2",
+	}, got["nvd"].References)
+}
+
+func TestConfig_PutVulnerabilityDetailCvssMergePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy db.CvssMergePolicy
+		source types.SourceID
+		want   float64
+	}{
+		{
+			name:   "max",
+			policy: db.CvssMax,
+			source: "ghsa",
+			want:   6.5,
+		},
+		{
+			name:   "last write",
+			policy: db.CvssLastWrite,
+			source: "ghsa",
+			want:   4.0,
+		},
+		{
+			name:   "default",
+			source: "ghsa",
+			want:   4.0,
+		},
+		{
+			name:   "nvd first",
+			policy: db.CvssNVDFirst,
+			source: "nvd",
+			want:   6.5,
+		},
+		{
+			name:   "nvd first for another source",
+			policy: db.CvssNVDFirst,
+			source: "ghsa",
+			want:   4.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{CvssMergePolicy: tt.policy}
+			for _, score := range []float64{6.5, 4.0} {
+				err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+					return dbc.PutVulnerabilityDetail(tx, "CVE-2014-7205", tt.source, types.VulnerabilityDetail{
+						Title:       "Arbitrary JavaScript Execution",
+						CvssScoreV3: score,
+					})
+				})
+				require.NoError(t, err)
+			}
+
+			got, err := dbc.GetVulnerabilityDetail("CVE-2014-7205")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got[tt.source].CvssScoreV3)
+			assert.Equal(t, "Arbitrary JavaScript Execution", got[tt.source].Title)
+		})
+	}
+}
+
+func TestConfig_PutVulnerabilityCvssMergePolicy(t *testing.T) {
+	// As normalized from the details, taking the severity of NVD by priority
+	vuln := types.Vulnerability{
+		Severity:       "MEDIUM",
+		SeveritySource: "nvd",
+		CVSS: types.VendorCVSS{
+			"nvd": {
+				V2Score: 7.5,
+				V3Score: 5.3,
+			},
+			"redhat": {
+				V3Score: 9.8,
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		policy     db.CvssMergePolicy
+		want       string
+		wantSource string
+		wantErr    string
+	}{
+		{
+			name:       "max across sources",
+			policy:     db.CvssMax,
+			want:       "CRITICAL",
+			wantSource: "redhat",
+		},
+		{
+			name:       "nvd first",
+			policy:     db.CvssNVDFirst,
+			want:       "MEDIUM",
+			wantSource: "nvd",
+		},
+		{
+			name:       "last write",
+			policy:     db.CvssLastWrite,
+			want:       "MEDIUM",
+			wantSource: "nvd",
+		},
+		{
+			name:    "unknown policy",
+			policy:  "highest",
+			wantErr: "unknown CVSS merge policy: highest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{CvssMergePolicy: tt.policy}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				return dbc.PutVulnerability(tx, "CVE-2021-3711", vuln)
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := dbc.GetVulnerability("CVE-2021-3711")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Severity)
+			assert.Equal(t, tt.wantSource, got.SeveritySource)
+			severity, _ := types.NewSeverity(tt.want)
+			assert.Equal(t, int(severity), got.SeverityRank)
+		})
+	}
+}
+
+func TestParseCvssMergePolicy(t *testing.T) {
+	got, err := db.ParseCvssMergePolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, db.CvssLastWrite, got)
+
+	got, err = db.ParseCvssMergePolicy("max")
+	require.NoError(t, err)
+	assert.Equal(t, db.CvssMax, got)
+
+	_, err = db.ParseCvssMergePolicy("highest")
+	require.Error(t, err)
+}
+
+func TestConfig_PutVulnerabilityDetailCvssPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision int
+		want      float64
+	}{
+		{
+			name:      "one decimal place",
+			precision: 1,
+			want:      9.8,
+		},
+		{
+			name:      "two decimal places",
+			precision: 2,
+			want:      9.83,
+		},
+		{
+			name: "as given",
+			want: 9.832,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = dbtest.InitDB(t, nil)
+			defer db.Close()
+
+			dbc := db.Config{CvssPrecision: tt.precision}
+			err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+				if err := dbc.PutVulnerabilityDetail(tx, "CVE-2021-20001", "nvd", types.VulnerabilityDetail{
+					CvssScore:   9.832,
+					CvssScoreV3: 9.832,
+				}); err != nil {
+					return err
+				}
+				return dbc.PutVulnerability(tx, "CVE-2021-20001", types.Vulnerability{
+					CVSS: types.VendorCVSS{
+						"nvd": {V2Score: 9.832, V3Score: 9.832},
+					},
+				})
+			})
+			require.NoError(t, err)
+
+			details, err := dbc.GetVulnerabilityDetail("CVE-2021-20001")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, details["nvd"].CvssScore)
+			assert.Equal(t, tt.want, details["nvd"].CvssScoreV3)
+
+			vuln, err := dbc.GetVulnerability("CVE-2021-20001")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, vuln.CVSS["nvd"].V2Score)
+			assert.Equal(t, tt.want, vuln.CVSS["nvd"].V3Score)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// detailFieldNames is the set of the field names of types.VulnerabilityDetail
+var detailFieldNames = func() map[string]struct{} {
+	names := map[string]struct{}{}
+	t := reflect.TypeOf(types.VulnerabilityDetail{})
+	for i := 0; i < t.NumField(); i++ {
+		names[t.Field(i).Name] = struct{}{}
+	}
+	return names
+}()
+
+// GetVulnerabilityFields returns the details per source with only the given fields of types.VulnerabilityDetail,
+// e.g. "CvssScore" and "Severity". The other fields are left zero and never decoded,
+// so that callers needing the severity don't pay for descriptions and references.
+// Replacements are followed as GetVulnerabilityDetail does.
+func (dbc Config) GetVulnerabilityFields(cveID string, fields []string) (map[types.SourceID]types.VulnerabilityDetail, error) {
+	projection, err := newDetailProjection(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := replacementChain{}
+	cveID = normalizeVulnID(cveID)
+	for {
+		values, err := dbc.forEach([]string{vulnerabilityDetailBucket, cveID})
+		if err != nil {
+			return nil, xerrors.Errorf("vulnerability detail error: %w", err)
+		}
+		details, replacedBy, err := projection.apply(values)
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := chain.next(cveID, replacedBy)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to follow the replacement: %w", err)
+		} else if replacement == "" {
+			return details, nil
+		}
+		cveID = replacement
+	}
+}
+
+// detailProjection selects the fields to decode
+type detailProjection map[string]struct{}
+
+func newDetailProjection(fields []string) (detailProjection, error) {
+	p := detailProjection{}
+	for _, f := range fields {
+		if _, ok := detailFieldNames[f]; !ok {
+			return nil, xerrors.Errorf("unknown vulnerability detail field: %s", f)
+		}
+		p[f] = struct{}{}
+	}
+	return p, nil
+}
+
+// apply decodes the requested fields of the details per source, and returns the replacement ID as well
+func (p detailProjection) apply(values map[string]Value) (map[types.SourceID]types.VulnerabilityDetail, string, error) {
+	if len(values) == 0 {
+		return nil, "", nil
+	}
+
+	details := map[types.SourceID]types.VulnerabilityDetail{}
+	for source, value := range values {
+		// Only the top-level keys are split here. The values stay raw until they are requested.
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(value.Content, &raw); err != nil {
+			return nil, "", xerrors.Errorf("failed to unmarshal Vulnerability JSON: %w", err)
+		}
+		for name := range raw {
+			if _, ok := p[name]; !ok && name != "ReplacedBy" {
+				delete(raw, name)
+			}
+		}
+		b, err := json.Marshal(raw)
+		if err != nil {
+			return nil, "", xerrors.Errorf("JSON marshal error: %w", err)
+		}
+
+		var detail types.VulnerabilityDetail
+		if err = json.Unmarshal(b, &detail); err != nil {
+			return nil, "", xerrors.Errorf("failed to unmarshal Vulnerability JSON: %w", err)
+		}
+		details[types.SourceID(source)] = detail
+	}
+
+	replacedBy := detailReplacedBy(details)
+	if _, ok := p["ReplacedBy"]; !ok {
+		for source, detail := range details {
+			detail.ReplacedBy = ""
+			details[source] = detail
+		}
+	}
+	return details, replacedBy, nil
+}
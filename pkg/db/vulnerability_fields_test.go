@@ -0,0 +1,106 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_GetVulnerabilityFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		vulnID  string
+		fields  []string
+		want    map[types.SourceID]types.VulnerabilityDetail
+		wantErr string
+	}{
+		{
+			name:   "severity only",
+			vulnID: "CVE-2019-10744",
+			fields: []string{"CvssScore", "Severity"},
+			want: map[types.SourceID]types.VulnerabilityDetail{
+				"nvd": {
+					CvssScore: 6.4,
+					Severity:  types.SeverityMedium,
+				},
+				"ghsa": {
+					Severity: types.SeverityCritical,
+				},
+			},
+		},
+		{
+			name:   "replaced",
+			vulnID: "NSWG-ECO-0",
+			fields: []string{"Title"},
+			want: map[types.SourceID]types.VulnerabilityDetail{
+				"nvd": {
+					Title: "Prototype pollution in lodash",
+				},
+				"ghsa": {
+					Title: "Prototype Pollution in lodash",
+				},
+			},
+		},
+		{
+			name:   "no such vulnerability",
+			vulnID: "CVE-2000-0000",
+			fields: []string{"Severity"},
+		},
+		{
+			name:    "unknown field",
+			vulnID:  "CVE-2019-10744",
+			fields:  []string{"Score"},
+			wantErr: "unknown vulnerability detail field: Score",
+		},
+	}
+
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	dbc := db.Config{}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		details := map[types.SourceID]types.VulnerabilityDetail{
+			"nvd": {
+				ID:          "CVE-2019-10744",
+				CvssScore:   6.4,
+				CvssVector:  "AV:N/AC:L/Au:N/C:P/I:P/A:N",
+				Severity:    types.SeverityMedium,
+				References:  []string{"https://github.com/lodash/lodash/pull/4336"},
+				Title:       "Prototype pollution in lodash",
+				Description: "Versions of lodash lower than 4.17.12 are vulnerable to Prototype Pollution.",
+			},
+			"ghsa": {
+				Severity: types.SeverityCritical,
+				Title:    "Prototype Pollution in lodash",
+			},
+		}
+		for source, detail := range details {
+			if err := dbc.PutVulnerabilityDetail(tx, "CVE-2019-10744", source, detail); err != nil {
+				return err
+			}
+		}
+		return dbc.PutVulnerabilityDetail(tx, "NSWG-ECO-0", "nodejs-security-wg", types.VulnerabilityDetail{
+			ReplacedBy: "CVE-2019-10744",
+		})
+	})
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dbc.GetVulnerabilityFields(tt.vulnID, tt.fields)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"strings"
+
 	bolt "go.etcd.io/bbolt"
 	"golang.org/x/xerrors"
 )
@@ -9,7 +11,38 @@ const (
 	vulnerabilityIDBucket = "vulnerability-id"
 )
 
+// normalizeVulnID uppercases CVE IDs, e.g. "cve-2014-7205" => "CVE-2014-7205", so that sloppy sources
+// don't split a CVE into buckets differing in case. Other IDs such as GHSA IDs are case-sensitive and kept as is.
+func normalizeVulnID(vulnID string) string {
+	if len(vulnID) > 4 && strings.EqualFold(vulnID[:4], "CVE-") {
+		return strings.ToUpper(vulnID)
+	}
+	return vulnID
+}
+
+// filteredOut returns true if the vulnerability ID is excluded by ExcludeIDs or not listed in IncludeIDs
+func (dbc Config) filteredOut(vulnID string) bool {
+	for _, id := range dbc.ExcludeIDs {
+		if id == vulnID {
+			return true
+		}
+	}
+	if len(dbc.IncludeIDs) == 0 {
+		return false
+	}
+	for _, id := range dbc.IncludeIDs {
+		if id == vulnID {
+			return false
+		}
+	}
+	return true
+}
+
 func (dbc Config) PutVulnerabilityID(tx *bolt.Tx, vulnID string) error {
+	vulnID = normalizeVulnID(vulnID)
+	if dbc.filteredOut(vulnID) {
+		return nil
+	}
 	bucket, err := tx.CreateBucketIfNotExists([]byte(vulnerabilityIDBucket))
 	if err != nil {
 		return xerrors.Errorf("failed to create %s bucket: %w", vulnerabilityIDBucket, err)
@@ -18,7 +51,7 @@ func (dbc Config) PutVulnerabilityID(tx *bolt.Tx, vulnID string) error {
 }
 
 func (dbc Config) ForEachVulnerabilityID(f func(tx *bolt.Tx, vulnID string) error) error {
-	err := db.Batch(func(tx *bolt.Tx) error {
+	err := dbc.Connection().Batch(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(vulnerabilityIDBucket))
 		if bucket == nil {
 			return xerrors.Errorf("no such bucket: %s", vulnerabilityIDBucket)
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"fmt"
+	stdlog "log"
+	"sync"
+)
+
+// Dedup collapses identical messages, e.g. a warning repeated for every record of a source.
+// Messages are held until Flush, which logs each of them once in the order first given,
+// with the number of times it was given if more than once.
+type Dedup struct {
+	mu     sync.Mutex
+	counts map[string]int
+	order  []string
+}
+
+// NewDedup returns Dedup logging with the standard logger
+func NewDedup() *Dedup {
+	return &Dedup{
+		counts: map[string]int{},
+	}
+}
+
+// Printf formats the message in the manner of fmt.Sprintf and holds it until Flush
+func (d *Dedup) Printf(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.counts[msg]; !ok {
+		d.order = append(d.order, msg)
+	}
+	d.counts[msg]++
+}
+
+// Flush logs the held messages and forgets them
+func (d *Dedup) Flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, msg := range d.order {
+		if n := d.counts[msg]; n > 1 {
+			stdlog.Printf("%s (repeated %d times)", msg, n)
+		} else {
+			stdlog.Printf("%s", msg)
+		}
+	}
+	d.counts = map[string]int{}
+	d.order = nil
+}
@@ -0,0 +1,44 @@
+package log_test
+
+import (
+	"bytes"
+	stdlog "log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy-db/pkg/log"
+)
+
+func TestDedup(t *testing.T) {
+	var buf bytes.Buffer
+	stdlog.SetOutput(&buf)
+	flags := stdlog.Flags()
+	stdlog.SetFlags(0)
+	defer func() {
+		stdlog.SetOutput(os.Stderr)
+		stdlog.SetFlags(flags)
+	}()
+
+	d := log.NewDedup()
+	for i := 0; i < 1000; i++ {
+		d.Printf("skipped module criterion: %s", "nodejs:12")
+	}
+	d.Printf("skipped module criterion: %s", "ruby:2.5")
+
+	// Nothing is logged until Flush
+	assert.Empty(t, buf.String())
+
+	d.Flush()
+	assert.Equal(t, []string{
+		"skipped module criterion: nodejs:12 (repeated 1000 times)",
+		"skipped module criterion: ruby:2.5",
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+
+	// Flushed messages are forgotten
+	buf.Reset()
+	d.Flush()
+	assert.Empty(t, buf.String())
+}
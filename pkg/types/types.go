@@ -59,6 +59,22 @@ func NewSeverity(severity string) (Severity, error) {
 	return SeverityUnknown, fmt.Errorf("unknown severity: %s", severity)
 }
 
+// CvssSeverity returns the qualitative severity rating of the CVSS score
+func CvssSeverity(score float64) Severity {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0.0:
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
+
 func CompareSeverityString(sev1, sev2 string) int {
 	s1, _ := NewSeverity(sev1)
 	s2, _ := NewSeverity(sev2)
@@ -78,9 +94,58 @@ func (s Severity) String() string {
 	return SeverityNames[s]
 }
 
+// Status represents the status of a package against a vulnerability, as determined by the vendor.
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusNotAffected
+	StatusAffected
+	StatusFixed
+	StatusUnderInvestigation
+	StatusWillNotFix
+	StatusFixDeferred
+	StatusEndOfLife
+)
+
+var (
+	StatusNames = []string{
+		"unknown",
+		"not_affected",
+		"affected",
+		"fixed",
+		"under_investigation",
+		"will_not_fix",
+		"fix_deferred",
+		"end_of_life",
+	}
+)
+
+func NewStatus(status string) Status {
+	for i, name := range StatusNames {
+		if status == name {
+			return Status(i)
+		}
+	}
+	return StatusUnknown
+}
+
+func (s Status) String() string {
+	if int(s) < 0 || int(s) >= len(StatusNames) {
+		return StatusNames[StatusUnknown]
+	}
+	return StatusNames[s]
+}
+
 type LastUpdated struct {
 	Date time.Time
 }
+
+// NoCvssScore is the CvssScore of vulnerabilities the source tells explicitly to have no score,
+// e.g. "cvss_score": null in the Node.js Security WG. Like a zero score, it is ranked as SeverityUnknown
+// and never as the lowest score. Use HasCvssScore rather than comparing scores to tell it apart.
+const NoCvssScore = -1.0
+
 type VulnerabilityDetail struct {
 	ID               string     `json:",omitempty"` // e.g. CVE-2019-8331, OSVDB-104365
 	CvssScore        float64    `json:",omitempty"`
@@ -95,6 +160,48 @@ type VulnerabilityDetail struct {
 	Description      string     `json:",omitempty"`
 	PublishedDate    *time.Time `json:",omitempty"` // Take from NVD
 	LastModifiedDate *time.Time `json:",omitempty"` // Take from NVD
+
+	// ReferenceTitles holds the link text of References keyed by the URL, e.g. "Link for SUSE-SU-2019:0048-2",
+	// for sources providing it. References stays a flat list of URLs for backward compatibility.
+	ReferenceTitles map[string]string `json:",omitempty"`
+
+	// SeveritySource is the source name supplying the severity, e.g. "nodejs-security-wg".
+	// In the vulnerability bucket, it is the source whose severity won over the others.
+	SeveritySource string `json:",omitempty"`
+
+	EPSS           float64 `json:",omitempty"` // Probability of exploitation in the next 30 days, from 0 to 1
+	KnownExploited bool    `json:",omitempty"` // Listed in CISA Known Exploited Vulnerabilities (KEV)
+	Disputed       bool    `json:",omitempty"` // The validity of the vulnerability is disputed, e.g. "** DISPUTED **" in NVD
+
+	// ExploitRefs are references to public exploits, e.g. in Exploit-DB and Metasploit.
+	// References linking exploits are collected into types.Vulnerability as well, so sources don't have to fill it.
+	ExploitRefs []string `json:",omitempty"`
+
+	// PluginRefs are the plugin IDs of vulnerability scanners detecting the vulnerability, keyed by the scanner,
+	// e.g. "tenable" => ["78888"]
+	PluginRefs map[string][]string `json:",omitempty"`
+
+	// CPEs are the vulnerable products, e.g. "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*", taken from NVD configurations
+	CPEs []string `json:",omitempty"`
+
+	// Localized holds the title and description in other languages, keyed by the language code such as "ja".
+	// Title and Description above are always English.
+	Localized map[string]LocalizedText `json:",omitempty"`
+
+	// ReplacedBy is the ID superseding this one, e.g. a CVE assigned to an advisory published under a NSWG-ECO placeholder.
+	// Lookups by this ID return the data of the replacement.
+	ReplacedBy string `json:",omitempty"`
+}
+
+// HasCvssScore returns true if the detail has a CVSS v2 or v3 score, unlike zero and NoCvssScore
+func (d VulnerabilityDetail) HasCvssScore() bool {
+	return d.CvssScore > 0 || d.CvssScoreV3 > 0
+}
+
+// LocalizedText is the title and description of a vulnerability in a specific language
+type LocalizedText struct {
+	Title       string `json:",omitempty"`
+	Description string `json:",omitempty"`
 }
 
 type AdvisoryDetail struct {
@@ -103,6 +210,16 @@ type AdvisoryDetail struct {
 	AdvisoryItem interface{}
 }
 
+// GitRange is a range of commits affected by a vulnerability.
+// Introduced of "0" means the first commit, and Fixed is empty if the vulnerability is not fixed yet.
+type GitRange struct {
+	Introduced string `json:",omitempty"`
+	Fixed      string `json:",omitempty"`
+}
+
+// PackageAliases maps a package name to its other names, e.g. "nodejs" => ["node"]
+type PackageAliases map[string][]string
+
 // SourceID represents data source such as NVD.
 type SourceID string
 
@@ -120,29 +237,97 @@ type Advisory struct {
 	// e.g. Will not fix and Affected
 	State string `json:",omitempty"`
 
+	// Status is the vendor determination for the package, e.g. "not_affected" for VEX-style statements.
+	// Advisories with StatusNotAffected carry no versions and allow scanners to suppress findings.
+	Status Status `json:",omitempty"`
+
 	// Trivy DB has "vulnerability" bucket and severities are usually stored in the bucket per a vulnerability ID.
 	// In some cases, the advisory may have multiple severities depending on the packages.
 	// For example, CVE-2015-2328 in Debian has "unimportant" for mongodb and "low" for pcre3.
 	// e.g. https://security-tracker.debian.org/tracker/CVE-2015-2328
 	Severity Severity `json:",omitempty"`
 
+	// DebianUrgency is the raw urgency given by Debian Security Tracker, e.g. "unimportant" and "medium".
+	// Severity above is converted from it, and "unimportant" is mapped to SeverityLow.
+	DebianUrgency string `json:",omitempty"`
+
+	// Module is the module:stream the package is built in, e.g. "nodejs:12" for Red Hat AppStream modules.
+	// Modular packages are stored under "<module:stream>::<package name>" in the namespace.
+	Module string `json:",omitempty"`
+
 	// Versions for os package
 	FixedVersion    string `json:",omitempty"`
 	AffectedVersion string `json:",omitempty"` // Only for Arch Linux
 
+	// Arches holds the architectures the fixed version is for, e.g. "x86_64" and "aarch64".
+	// Empty means all architectures.
+	Arches []string `json:",omitempty"`
+
+	// IntroducedVersion is the first affected version given by the source, e.g. OSV "introduced".
+	// Versions older than this are not affected even when they are older than FixedVersion.
+	IntroducedVersion string `json:",omitempty"`
+
 	// MajorVersion ranges for language-specific package
 	// Some advisories provide VulnerableVersions only, others provide PatchedVersions and UnaffectedVersions
+	// VulnerableVersions of AllVersions means every version is affected and no fix exists.
 	VulnerableVersions []string `json:",omitempty"`
 	PatchedVersions    []string `json:",omitempty"`
 	UnaffectedVersions []string `json:",omitempty"`
 
+	// FixCommits holds the URLs of the upstream commits fixing the vulnerability, e.g. for auto-remediation.
+	// It is filled only by sources telling commits apart from the other references.
+	FixCommits []string `json:",omitempty"`
+
+	// GitRanges are the affected commit ranges, e.g. given by OSV for projects without versions.
+	// They are exposed as is and never used for version matching.
+	GitRanges []GitRange `json:",omitempty"`
+
+	// AffectedSymbols are the vulnerable functions and methods for reachability analysis,
+	// e.g. "smallvec::SmallVec::grow" in RustSec and "golang.org/x/text/language.Parse" in Go.
+	AffectedSymbols []string `json:",omitempty"`
+
+	// Tags are arbitrary labels attached by sources or post-processors, e.g. "kev" and "internet-facing-risk",
+	// for filtering with GetTaggedAdvisories.
+	Tags []string `json:",omitempty"`
+
+	// Obsolete is set on advisories no longer given by upstream, which are kept for auditing.
+	// They are left out of lookups unless db.Config.IncludeObsolete is enabled.
+	Obsolete bool `json:",omitempty"`
+
 	// DataSource holds where the advisory comes from
 	DataSource *DataSource `json:",omitempty"`
 
+	// Provenance is the upstream file the advisory was parsed from, followed by "#<index>" for files with multiple records.
+	// It is stored only when db.Config.TrackProvenance is enabled.
+	Provenance string `json:",omitempty"`
+
+	// FetchedAt is when the advisory was refreshed from upstream, unlike the modified date of the vulnerability.
+	// It is stored only when db.Config.SourceDate is set.
+	FetchedAt *time.Time `json:",omitempty"`
+
 	// Custom is basically for extensibility and is not supposed to be used in OSS
 	Custom interface{} `json:",omitempty"`
 }
 
+// AllVersions is the VulnerableVersions sentinel for advisories affecting every version with no fix
+const AllVersions = "*"
+
+// AffectsAllVersions returns true if the advisory affects every version, unlike an advisory with no ranges,
+// which is ambiguous.
+func (a Advisory) AffectsAllVersions() bool {
+	for _, v := range a.VulnerableVersions {
+		if v == AllVersions {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFix returns true if the advisory tells the version fixing the vulnerability
+func (a Advisory) HasFix() bool {
+	return a.FixedVersion != "" || len(a.PatchedVersions) > 0
+}
+
 type Vulnerability struct {
 	Title            string         `json:",omitempty"`
 	Description      string         `json:",omitempty"`
@@ -153,6 +338,19 @@ type Vulnerability struct {
 	References       []string       `json:",omitempty"`
 	PublishedDate    *time.Time     `json:",omitempty"` // Take from NVD
 	LastModifiedDate *time.Time     `json:",omitempty"` // Take from NVD
+	FirstSeen        *time.Time     `json:",omitempty"` // When the vulnerability was stored in the DB for the first time
+	RiskScore        float64        `json:",omitempty"` // See vulnerability.RiskScore. Only set if EPSS or KEV is available.
+	SeveritySource   string         `json:",omitempty"` // The source whose severity is taken as Severity
+	ExploitRefs      []string       `json:",omitempty"` // References to public exploits, e.g. in Exploit-DB
+	SeverityRank     int            `json:",omitempty"` // Severity as a number for sorting, from 0 (UNKNOWN) to 4 (CRITICAL)
+	Disputed         bool           `json:",omitempty"` // Any source flags the vulnerability as disputed
+	ReplacedBy       string         `json:",omitempty"` // The ID superseding this one. See VulnerabilityDetail.ReplacedBy.
+
+	// PluginRefs are the plugin IDs of vulnerability scanners keyed by the scanner, e.g. "tenable" => ["78888"]
+	PluginRefs map[string][]string `json:",omitempty"`
+
+	// ReferenceTitles holds the link text of References keyed by the URL. See VulnerabilityDetail.ReferenceTitles.
+	ReferenceTitles map[string]string `json:",omitempty"`
 
 	// Custom is basically for extensibility and is not supposed to be used in OSS
 	Custom interface{} `json:",omitempty"`
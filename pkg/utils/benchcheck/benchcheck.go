@@ -0,0 +1,72 @@
+package benchcheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// e.g. "BenchmarkNodeCommit-8   100   11017836 ns/op   59152 B/op   390 allocs/op"
+var resultRegexp = regexp.MustCompile(`^(Benchmark\S+)\s+\d+\s+([\d.]+) ns/op`)
+
+// e.g. "-8" appended by go test with GOMAXPROCS
+var procsRegexp = regexp.MustCompile(`-\d+$`)
+
+// Regression is a benchmark slower than the baseline by more than the allowed ratio
+type Regression struct {
+	Name     string
+	Baseline float64 // ns/op
+	Current  float64 // ns/op
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: %.0f ns/op => %.0f ns/op (x%.2f)", r.Name, r.Baseline, r.Current, r.Current/r.Baseline)
+}
+
+// Parse reads the output of "go test -bench" and returns ns/op keyed by the benchmark name without the GOMAXPROCS suffix.
+// Lines other than the results are ignored, so the output can be given as is.
+// The last result wins if a benchmark is run several times, e.g. with -count.
+func Parse(r io.Reader) (map[string]float64, error) {
+	results := map[string]float64{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m := resultRegexp.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if m == nil {
+			continue
+		}
+		nsPerOp, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid ns/op of %s: %w", m[1], err)
+		}
+		results[procsRegexp.ReplaceAllString(m[1], "")] = nsPerOp
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("scan error: %w", err)
+	}
+	return results, nil
+}
+
+// Compare returns the benchmarks slower than maxRatio times the baseline, sorted by name.
+// Benchmarks missing in either results are skipped, so that benchmarks can be added before updating the baseline.
+func Compare(baseline, current map[string]float64, maxRatio float64) []Regression {
+	var regressions []Regression
+	for name, cur := range current {
+		base, ok := baseline[name]
+		if !ok || base <= 0 {
+			continue
+		}
+		if cur > base*maxRatio {
+			regressions = append(regressions, Regression{Name: name, Baseline: base, Current: cur})
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool {
+		return regressions[i].Name < regressions[j].Name
+	})
+	return regressions
+}
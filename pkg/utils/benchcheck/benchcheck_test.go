@@ -0,0 +1,46 @@
+package benchcheck_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/utils/benchcheck"
+)
+
+func TestParse(t *testing.T) {
+	output := `goos: linux
+goarch: amd64
+pkg: github.com/aquasecurity/trivy-db/pkg/vulnsrc/node
+BenchmarkNodeCommit-8   	     100	  11017836 ns/op	   59152 B/op	     390 allocs/op
+BenchmarkConfig_PutAdvisoryDetail/no_dedup 	 50	  22000000.5 ns/op
+PASS
+ok  	github.com/aquasecurity/trivy-db/pkg/vulnsrc/node	1.124s
+`
+	got, err := benchcheck.Parse(strings.NewReader(output))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{
+		"BenchmarkNodeCommit":                        11017836,
+		"BenchmarkConfig_PutAdvisoryDetail/no_dedup": 22000000.5,
+	}, got)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := map[string]float64{
+		"BenchmarkNodeCommit":        10000000,
+		"BenchmarkPutAdvisoryDetail": 10000000,
+		"BenchmarkRemoved":           10000000,
+	}
+	current := map[string]float64{
+		"BenchmarkNodeCommit":        25000000,
+		"BenchmarkPutAdvisoryDetail": 15000000,
+		"BenchmarkAdded":             99000000,
+	}
+	got := benchcheck.Compare(baseline, current, 2)
+	assert.Equal(t, []benchcheck.Regression{
+		{Name: "BenchmarkNodeCommit", Baseline: 10000000, Current: 25000000},
+	}, got)
+	assert.Equal(t, "BenchmarkNodeCommit: 10000000 ns/op => 25000000 ns/op (x2.50)", got[0].String())
+}
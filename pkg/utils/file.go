@@ -7,10 +7,13 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"golang.org/x/xerrors"
 )
 
+// FileWalk calls walkFn for each non-empty file under root.
+// Symlinks are followed only when they point inside root, and an error is returned otherwise.
 func FileWalk(root string, walkFn func(r io.Reader, path string) error) error {
 	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -19,8 +22,18 @@ func FileWalk(root string, walkFn func(r io.Reader, path string) error) error {
 			return nil
 		}
 
-		info, err := d.Info()
-		if err != nil {
+		var info fs.FileInfo
+		if d.Type()&fs.ModeSymlink != 0 {
+			if err = SafePath(root, path); err != nil {
+				return err
+			}
+			// Symlinked directories are not walked
+			if info, err = os.Stat(path); err != nil {
+				return xerrors.Errorf("file info error: %w", err)
+			} else if info.IsDir() {
+				return nil
+			}
+		} else if info, err = d.Info(); err != nil {
 			return xerrors.Errorf("file info error: %w", err)
 		}
 
@@ -46,6 +59,24 @@ func FileWalk(root string, walkFn func(r io.Reader, path string) error) error {
 	return nil
 }
 
+// SafePath returns an error if the path resolves outside root, e.g. a symlink to /etc/passwd in a feed directory.
+func SafePath(root, path string) error {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve %s: %w", root, err)
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	rel, err := filepath.Rel(realRoot, realPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return xerrors.Errorf("%s points outside %s", path, root)
+	}
+	return nil
+}
+
 func Exists(path string) (bool, error) {
 	_, err := os.Stat(path)
 	if err == nil {
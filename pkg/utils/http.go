@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/xerrors"
+)
+
+// NewHTTPClient returns an HTTP client for downloading feeds.
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY are honored unless proxyURL is specified.
+// caFile is a PEM bundle trusted in addition to the system certificates.
+func NewHTTPClient(proxyURL, caFile string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid proxy URL (%s): %w", proxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to read the CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, xerrors.Errorf("no certificate found in %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,71 @@
+package utils_test
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/utils"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("proxy", func(t *testing.T) {
+		var requested string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = r.URL.String()
+			_, _ = io.WriteString(w, "via proxy")
+		}))
+		defer proxy.Close()
+
+		client, err := utils.NewHTTPClient(proxy.URL, "")
+		require.NoError(t, err)
+
+		resp, err := client.Get("http://feed.example.com/advisories.json")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "via proxy", string(body))
+		assert.Equal(t, "http://feed.example.com/advisories.json", requested)
+	})
+
+	t.Run("CA bundle", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "ok")
+		}))
+		defer ts.Close()
+
+		// Untrusted without the CA bundle
+		client, err := utils.NewHTTPClient("", "")
+		require.NoError(t, err)
+		_, err = client.Get(ts.URL)
+		require.Error(t, err)
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+		require.NoError(t, os.WriteFile(caFile, b, 0600))
+
+		client, err = utils.NewHTTPClient("", caFile)
+		require.NoError(t, err)
+		resp, err := client.Get(ts.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("sad path", func(t *testing.T) {
+		_, err := utils.NewHTTPClient("", filepath.Join(t.TempDir(), "missing.pem"))
+		assert.Error(t, err)
+
+		_, err = utils.NewHTTPClient("://bad", "")
+		assert.Error(t, err)
+	})
+}
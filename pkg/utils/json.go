@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"unicode"
+
+	"golang.org/x/xerrors"
+)
+
+// DecodeJSONStream decodes the JSON value one record at a time so that huge feeds are never held in memory as a whole.
+// If the value is an array, decodeFn is called for each element with its index. Otherwise, it is called once
+// with the index -1 to decode the whole value. decodeFn must decode exactly one value from the decoder.
+func DecodeJSONStream(r io.Reader, decodeFn func(dec *json.Decoder, index int) error) error {
+	br := bufio.NewReader(r)
+	isArray, err := peekArray(br)
+	if err != nil {
+		return xerrors.Errorf("read error: %w", err)
+	}
+
+	dec := json.NewDecoder(br)
+	if !isArray {
+		return decodeFn(dec, -1)
+	}
+	return DecodeJSONArray(dec, decodeFn)
+}
+
+// DecodeJSONArray decodes the array at the current position of the decoder, calling decodeFn for each element
+// with its index, e.g. for an array nested in an object such as "vulnerabilities" of the NVD CVE API.
+func DecodeJSONArray(dec *json.Decoder, decodeFn func(dec *json.Decoder, index int) error) error {
+	// Opening bracket
+	if t, err := dec.Token(); err != nil {
+		return xerrors.Errorf("JSON token error: %w", err)
+	} else if t != json.Delim('[') {
+		return xerrors.Errorf("unexpected JSON token: %v", t)
+	}
+	for i := 0; dec.More(); i++ {
+		if err := decodeFn(dec, i); err != nil {
+			return err
+		}
+	}
+	// Closing bracket
+	if _, err := dec.Token(); err != nil {
+		return xerrors.Errorf("JSON token error: %w", err)
+	}
+	return nil
+}
+
+// peekArray returns true if the first non-space character is '['. Nothing is consumed but leading spaces.
+func peekArray(br *bufio.Reader) (bool, error) {
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		if unicode.IsSpace(rune(c)) {
+			continue
+		}
+		return c == '[', br.UnreadByte()
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReader counts the bytes read so far
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestDecodeJSONStream(t *testing.T) {
+	t.Run("large array", func(t *testing.T) {
+		const records = 100000
+		var sb strings.Builder
+		sb.WriteString(" [")
+		for i := 0; i < records; i++ {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, `{"id": %d, "name": "package-%d"}`, i, i)
+		}
+		sb.WriteString("]\n")
+
+		r := &countingReader{r: strings.NewReader(sb.String())}
+		var got, firstRead int
+		err := DecodeJSONStream(r, func(dec *json.Decoder, index int) error {
+			var v struct {
+				ID int `json:"id"`
+			}
+			require.NoError(t, dec.Decode(&v))
+			assert.Equal(t, index, v.ID)
+			if index == 0 {
+				firstRead = r.n
+			}
+			got++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, records, got)
+
+		// The first record is decoded before the whole array is read
+		assert.Less(t, firstRead, sb.Len()/100)
+		assert.Equal(t, sb.Len(), r.n)
+	})
+
+	t.Run("single object", func(t *testing.T) {
+		var got []int
+		err := DecodeJSONStream(strings.NewReader(`{"id": 1}`), func(dec *json.Decoder, index int) error {
+			var v map[string]interface{}
+			require.NoError(t, dec.Decode(&v))
+			got = append(got, index)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{-1}, got)
+	})
+
+	t.Run("truncated array", func(t *testing.T) {
+		err := DecodeJSONStream(strings.NewReader(`[{"id": 1}, {"id"`), func(dec *json.Decoder, index int) error {
+			var v map[string]interface{}
+			return dec.Decode(&v)
+		})
+		require.Error(t, err)
+	})
+}
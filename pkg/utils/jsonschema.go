@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// ValidateJSONSchema validates the JSON document read from r against the schema.
+// Only a subset of JSON Schema is supported: "type", "required", "properties" and "items".
+// It is enough to detect format drift of upstream feeds without an external dependency.
+func ValidateJSONSchema(r io.Reader, schema []byte) error {
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return xerrors.Errorf("invalid JSON schema: %w", err)
+	}
+
+	var doc interface{}
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	if err := d.Decode(&doc); err != nil {
+		return xerrors.Errorf("JSON decode error: %w", err)
+	}
+
+	if errs := s.validate("$", doc); len(errs) > 0 {
+		return xerrors.Errorf("JSON schema validation error: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+type jsonSchema struct {
+	Type       schemaTypes            `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Items      *jsonSchema            `json:"items"`
+}
+
+// schemaTypes can be a string or an array of strings, e.g. "string" or ["string", "null"]
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*t = []string{s}
+		return nil
+	}
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+	*t = ss
+	return nil
+}
+
+func (s *jsonSchema) validate(path string, v interface{}) []string {
+	if len(s.Type) > 0 && !s.matchType(v) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), jsonType(v))}
+	}
+
+	var errs []string
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for _, req := range s.Required {
+			if _, ok := vv[req]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required property %q", path, req))
+			}
+		}
+
+		// Sort keys for deterministic error messages
+		var keys []string
+		for k := range s.Properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if pv, ok := vv[k]; ok {
+				errs = append(errs, s.Properties[k].validate(path+"."+k, pv)...)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range vv {
+				errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+	}
+	return errs
+}
+
+func (s *jsonSchema) matchType(v interface{}) bool {
+	actual := jsonType(v)
+	for _, t := range s.Type {
+		switch {
+		case t == actual:
+			return true
+		case t == "number" && actual == "integer":
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(v interface{}) string {
+	switch vv := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := vv.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
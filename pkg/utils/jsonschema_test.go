@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateJSONSchema(t *testing.T) {
+	schema := []byte(`{
+  "type": "object",
+  "required": ["id", "name", "tags"],
+  "properties": {
+    "id": {"type": "integer"},
+    "name": {"type": "string"},
+    "score": {"type": ["number", "null"]},
+    "tags": {"type": "array", "items": {"type": "string"}}
+  }
+}`)
+	tests := []struct {
+		name    string
+		input   string
+		schema  []byte
+		wantErr string
+	}{
+		{
+			name:   "happy path",
+			input:  `{"id": 1, "name": "foo", "score": 4.8, "tags": ["a", "b"]}`,
+			schema: schema,
+		},
+		{
+			name:   "null is allowed",
+			input:  `{"id": 1, "name": "foo", "score": null, "tags": []}`,
+			schema: schema,
+		},
+		{
+			name:    "missing required property",
+			input:   `{"id": 1, "tags": []}`,
+			schema:  schema,
+			wantErr: `$: missing required property "name"`,
+		},
+		{
+			name:    "wrong type",
+			input:   `{"id": "1", "name": "foo", "tags": "a"}`,
+			schema:  schema,
+			wantErr: "$.id: expected integer, got string, $.tags: expected array, got string",
+		},
+		{
+			name:    "wrong item type",
+			input:   `{"id": 1, "name": "foo", "tags": ["a", 2]}`,
+			schema:  schema,
+			wantErr: "$.tags[1]: expected string, got integer",
+		},
+		{
+			name:    "wrong root type",
+			input:   `[]`,
+			schema:  schema,
+			wantErr: "$: expected object, got array",
+		},
+		{
+			name:    "invalid JSON",
+			input:   `{`,
+			schema:  schema,
+			wantErr: "JSON decode error",
+		},
+		{
+			name:    "invalid schema",
+			input:   `{}`,
+			schema:  []byte(`{"type": 1}`),
+			wantErr: "invalid JSON schema",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSONSchema(strings.NewReader(tt.input), tt.schema)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
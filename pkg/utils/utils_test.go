@@ -77,3 +77,48 @@ func TestFileWalk(t *testing.T) {
 		t.Error("The file content is wrong")
 	}
 }
+
+func TestFileWalk_Symlink(t *testing.T) {
+	td := t.TempDir()
+	root := filepath.Join(td, "root")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write(t, filepath.Join(root, "inside"), "inside")
+	write(t, filepath.Join(td, "outside"), "outside")
+
+	// A symlink inside the root is followed
+	if err := os.Symlink(filepath.Join(root, "inside"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	walker := func(r io.Reader, path string) error {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		contents = append(contents, string(b))
+		return nil
+	}
+	if err := FileWalk(root, walker); err != nil {
+		t.Fatal(err)
+	}
+	if len(contents) != 2 || contents[0] != "inside" || contents[1] != "inside" {
+		t.Errorf("unexpected contents: %v", contents)
+	}
+
+	// A symlink escaping the root is refused
+	if err := os.Symlink(filepath.Join(td, "outside"), filepath.Join(root, "malicious")); err != nil {
+		t.Fatal(err)
+	}
+	contents = nil
+	err := FileWalk(root, walker)
+	if err == nil || !strings.Contains(err.Error(), "points outside") {
+		t.Errorf("expected an error about the symlink, got %v", err)
+	}
+	for _, c := range contents {
+		if c == "outside" {
+			t.Error("the file outside the root must not be read")
+		}
+	}
+}
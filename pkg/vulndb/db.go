@@ -1,7 +1,11 @@
 package vulndb
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
 	bolt "go.etcd.io/bbolt"
@@ -19,14 +23,30 @@ type VulnDB interface {
 	Build(targets []string) error
 }
 
+// Stats holds statistics of the build
+type Stats struct {
+	// Durations holds the time taken to update each data source
+	Durations map[types.SourceID]time.Duration
+
+	// LatestModified holds the latest modified date of vulnerabilities per data source
+	LatestModified map[types.SourceID]time.Time
+
+	// TimedOut holds the data sources aborted on db.Config.SourceTimeout
+	TimedOut []types.SourceID
+}
+
 type TrivyDB struct {
 	dbc            db.Config
+	stats          *Stats
 	metadata       metadata.Client
 	vulnClient     vulnerability.Vulnerability
 	vulnSrcs       map[types.SourceID]vulnsrc.VulnSrc
+	nvdDetails     map[string]types.VulnerabilityDetail
+	pluginRefs     map[string]map[string][]string
 	cacheDir       string
 	updateInterval time.Duration
 	clock          clock.Clock
+	force          bool
 }
 
 type Option func(*TrivyDB)
@@ -37,25 +57,52 @@ func WithClock(clock clock.Clock) Option {
 	}
 }
 
+// WithDBConfig sets the DB config used for the build. It is also passed to data sources,
+// so that e.g. ProgressFn is called while they are updated.
+func WithDBConfig(dbc db.Config) Option {
+	return func(core *TrivyDB) {
+		core.dbc = dbc
+	}
+}
+
+// WithForce makes Insert update the data sources completed by a previous build that failed halfway,
+// instead of resuming from the checkpoints.
+func WithForce() Option {
+	return func(core *TrivyDB) {
+		core.force = true
+	}
+}
+
 func WithVulnSrcs(srcs map[types.SourceID]vulnsrc.VulnSrc) Option {
 	return func(core *TrivyDB) {
 		core.vulnSrcs = srcs
 	}
 }
 
-func New(cacheDir string, updateInterval time.Duration, opts ...Option) *TrivyDB {
-	// Initialize map
-	vulnSrcs := map[types.SourceID]vulnsrc.VulnSrc{}
-	for _, v := range vulnsrc.All {
-		vulnSrcs[v.Name()] = v
+// WithNVDEnrichment fills vulnerabilities lacking a title from the NVD details, e.g. loaded by nvd.Load.
+// The title is taken from the first sentence of the NVD description.
+func WithNVDEnrichment(details map[string]types.VulnerabilityDetail) Option {
+	return func(core *TrivyDB) {
+		core.nvdDetails = details
 	}
+}
 
-	dbc := db.Config{}
+// WithPluginRefs stores the plugin IDs of vulnerability scanners, e.g. loaded by pluginref.Load,
+// as the detail of the vulnerabilities. Vulnerabilities not in the DB are skipped.
+func WithPluginRefs(refs map[string]map[string][]string) Option {
+	return func(core *TrivyDB) {
+		core.pluginRefs = refs
+	}
+}
+
+func New(cacheDir string, updateInterval time.Duration, opts ...Option) *TrivyDB {
 	tdb := &TrivyDB{
-		dbc:            dbc,
+		dbc: db.Config{},
+		stats: &Stats{
+			Durations:      map[types.SourceID]time.Duration{},
+			LatestModified: map[types.SourceID]time.Time{},
+		},
 		metadata:       metadata.NewClient(cacheDir),
-		vulnClient:     vulnerability.New(dbc),
-		vulnSrcs:       vulnSrcs,
 		cacheDir:       cacheDir,
 		updateInterval: updateInterval,
 		clock:          clock.RealClock{},
@@ -65,21 +112,68 @@ func New(cacheDir string, updateInterval time.Duration, opts ...Option) *TrivyDB
 		opt(tdb)
 	}
 
+	tdb.vulnClient = vulnerability.New(tdb.dbc)
+
+	// Initialize map
+	if tdb.vulnSrcs == nil {
+		tdb.vulnSrcs = map[types.SourceID]vulnsrc.VulnSrc{}
+		for _, v := range vulnsrc.NewAll(tdb.dbc) {
+			tdb.vulnSrcs[v.Name()] = v
+		}
+	}
+
 	return tdb
 }
 
 func (t TrivyDB) Insert(targets []string) error {
 	log.Println("Updating vulnerability database...")
+	var errs []string
 	for _, target := range targets {
 		src, ok := t.vulnSrc(target)
 		if !ok {
 			return xerrors.Errorf("%s is not supported", target)
 		}
+
+		// Resume the build failed halfway
+		if !t.force {
+			done, err := t.dbc.Checkpointed(src.Name())
+			if err != nil {
+				return xerrors.Errorf("checkpoint error: %w", err)
+			} else if done {
+				log.Printf("Skipping %s completed by the previous build\n", target)
+				continue
+			}
+		}
 		log.Printf("Updating %s data...\n", target)
 
-		if err := src.Update(t.cacheDir); err != nil {
+		start := t.clock.Now()
+		if err := t.update(src); xerrors.Is(err, context.DeadlineExceeded) {
+			log.Printf("Aborted %s after %s, continuing with the other sources\n", target, t.dbc.SourceTimeout)
+			t.stats.TimedOut = append(t.stats.TimedOut, src.Name())
+			if t.dbc.FailOnSourceError {
+				errs = append(errs, fmt.Sprintf("%s timed out after %s", target, t.dbc.SourceTimeout))
+			}
+			continue
+		} else if err != nil && t.dbc.FailOnSourceError {
+			log.Printf("Failed to update %s, continuing with the other sources: %s\n", target, err)
+			errs = append(errs, fmt.Sprintf("%s update error: %s", target, err))
+			continue
+		} else if err != nil {
 			return xerrors.Errorf("%s update error: %w", target, err)
 		}
+		t.stats.Durations[src.Name()] = t.clock.Since(start)
+		if t.dbc.MetricsRegistry != nil {
+			t.dbc.MetricsRegistry.SetGauge(db.MetricSourceDuration, map[string]string{"source": string(src.Name())},
+				t.stats.Durations[src.Name()].Seconds())
+		}
+
+		if err := t.dbc.PutCheckpoint(src.Name()); err != nil {
+			return xerrors.Errorf("checkpoint error: %w", err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return xerrors.Errorf("%d source(s) failed: %s", len(errs), strings.Join(errs, "; "))
 	}
 
 	md := metadata.Metadata{
@@ -95,25 +189,120 @@ func (t TrivyDB) Insert(targets []string) error {
 	return nil
 }
 
+// update updates the source within db.Config.SourceTimeout
+func (t TrivyDB) update(src vulnsrc.VulnSrc) error {
+	if t.dbc.SourceTimeout <= 0 {
+		return src.Update(t.cacheDir)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.dbc.SourceTimeout)
+	defer cancel()
+	return vulnsrc.Update(ctx, src, t.cacheDir)
+}
+
+// Stats returns statistics of the last build
+func (t TrivyDB) Stats() Stats {
+	return *t.stats
+}
+
+func (t TrivyDB) printSummary() {
+	var ids []string
+	for id := range t.stats.Durations {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+
+	log.Println("Build summary:")
+	for _, id := range ids {
+		log.Printf("  %s: %s\n", id, t.stats.Durations[types.SourceID(id)])
+	}
+}
+
 func (t TrivyDB) Build(targets []string) error {
+	start := t.clock.Now()
+
 	// Insert all security advisories
 	if err := t.Insert(targets); err != nil {
 		return xerrors.Errorf("insert error: %w", err)
 	}
 
+	if err := t.putPluginRefs(); err != nil {
+		return xerrors.Errorf("plugin refs error: %w", err)
+	}
+
+	// Union the vulnerable versions split across records
+	if t.dbc.CoalesceRanges {
+		if err := t.dbc.CoalesceAdvisoryRanges(); err != nil {
+			return xerrors.Errorf("coalesce error: %w", err)
+		}
+	}
+
 	// Remove unnecessary details
 	if err := t.optimize(); err != nil {
 		return xerrors.Errorf("optimize error: %w", err)
 	}
 
+	// Keep the advisories removed upstream as obsolete
+	if n, err := t.dbc.MarkObsoleteAdvisories(); err != nil {
+		return xerrors.Errorf("obsolete error: %w", err)
+	} else if n > 0 {
+		log.Printf("Marked %d advisories no longer given by upstream as obsolete\n", n)
+	}
+
 	// Remove unnecessary buckets
 	if err := t.cleanup(); err != nil {
 		return xerrors.Errorf("cleanup error: %w", err)
 	}
 
+	if t.dbc.BucketHashes {
+		if err := t.dbc.PutBucketHashes(); err != nil {
+			return xerrors.Errorf("bucket hash error: %w", err)
+		}
+	}
+
+	t.warnStaleSources()
+	t.printSummary()
+
+	if t.dbc.MetricsRegistry != nil {
+		t.dbc.MetricsRegistry.SetGauge(db.MetricBuildDuration, nil, t.clock.Since(start).Seconds())
+	}
+
 	return nil
 }
 
+// warnStaleSources warns about data sources not modified within StaleAfter
+func (t TrivyDB) warnStaleSources() {
+	if t.dbc.StaleAfter == 0 {
+		return
+	}
+
+	var ids []string
+	for id := range t.stats.LatestModified {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+
+	now := t.clock.Now()
+	for _, id := range ids {
+		latest := t.stats.LatestModified[types.SourceID(id)]
+		if age := now.Sub(latest); age > t.dbc.StaleAfter {
+			log.Printf("WARN: %s may be stale: the latest modified date is %s (%s ago)\n",
+				id, latest.Format(time.RFC3339), age.Round(time.Hour))
+		}
+	}
+}
+
+// recordModified keeps the latest modified date per data source
+func (t TrivyDB) recordModified(details map[types.SourceID]types.VulnerabilityDetail) {
+	for source, d := range details {
+		if d.LastModifiedDate == nil {
+			continue
+		}
+		if d.LastModifiedDate.After(t.stats.LatestModified[source]) {
+			t.stats.LatestModified[source] = *d.LastModifiedDate
+		}
+	}
+}
+
 func (t TrivyDB) vulnSrc(target string) (vulnsrc.VulnSrc, bool) {
 	for _, src := range t.vulnSrcs {
 		if target == string(src.Name()) {
@@ -128,10 +317,11 @@ func (t TrivyDB) optimize() error {
 	// Trivy DB will not store them so that it could reduce the database size.
 	// This bucket has only vulnerability IDs provided by vendors. They must be stored.
 	err := t.dbc.ForEachVulnerabilityID(func(tx *bolt.Tx, cveID string) error {
-		details := t.vulnClient.GetDetails(cveID)
+		details := t.enrich(cveID, t.vulnClient.GetDetails(cveID))
 		if t.vulnClient.IsRejected(details) {
 			return nil
 		}
+		t.recordModified(details)
 
 		if err := t.dbc.SaveAdvisoryDetails(tx, cveID); err != nil {
 			return xerrors.Errorf("failed to save advisories: %w", err)
@@ -142,6 +332,8 @@ func (t TrivyDB) optimize() error {
 		}
 
 		vuln := t.vulnClient.Normalize(details)
+		now := t.clock.Now().UTC()
+		vuln.FirstSeen = &now // overwritten by PutVulnerability if the vulnerability already exists
 		if err := t.dbc.PutVulnerability(tx, cveID, vuln); err != nil {
 			return xerrors.Errorf("failed to put vulnerability: %w", err)
 		}
@@ -156,12 +348,76 @@ func (t TrivyDB) optimize() error {
 	return nil
 }
 
+// putPluginRefs stores the plugin IDs of the vulnerabilities having details
+func (t TrivyDB) putPluginRefs() error {
+	var vulnIDs []string
+	for vulnID := range t.pluginRefs {
+		details, err := t.dbc.GetVulnerabilityDetail(vulnID)
+		if err != nil {
+			return xerrors.Errorf("vulnerability detail error: %w", err)
+		} else if len(details) == 0 {
+			continue
+		}
+		vulnIDs = append(vulnIDs, vulnID)
+	}
+	if len(vulnIDs) == 0 {
+		return nil
+	}
+	sort.Strings(vulnIDs)
+
+	return t.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		for _, vulnID := range vulnIDs {
+			detail := types.VulnerabilityDetail{PluginRefs: t.pluginRefs[vulnID]}
+			if err := t.dbc.PutVulnerabilityDetail(tx, vulnID, vulnerability.PluginRefs, detail); err != nil {
+				return xerrors.Errorf("failed to put plugin refs: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// enrich adds the NVD detail to the details if none of them has a title
+func (t TrivyDB) enrich(cveID string, details map[types.SourceID]types.VulnerabilityDetail) map[types.SourceID]types.VulnerabilityDetail {
+	nvdDetail, ok := t.nvdDetails[cveID]
+	if !ok {
+		return details
+	}
+	for _, d := range details {
+		if d.Title != "" {
+			return details
+		}
+	}
+
+	enriched := map[types.SourceID]types.VulnerabilityDetail{}
+	for source, d := range details {
+		enriched[source] = d
+	}
+	if d, ok := enriched[vulnerability.NVD]; ok {
+		nvdDetail = d
+	}
+	nvdDetail.Title = firstSentence(nvdDetail.Description)
+	enriched[vulnerability.NVD] = nvdDetail
+	return enriched
+}
+
+// firstSentence returns the first sentence without the period
+// e.g. "A flaw was found in Jinja. Python ..." => "A flaw was found in Jinja"
+func firstSentence(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ". "); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSuffix(s, ".")
+}
+
 func (t TrivyDB) cleanup() error {
 	if err := t.dbc.DeleteVulnerabilityIDBucket(); err != nil {
 		return xerrors.Errorf("failed to delete severity bucket: %w", err)
 	}
 
-	if err := t.dbc.DeleteVulnerabilityDetailBucket(); err != nil {
+	// No vulnerability detail is written with AdvisoriesOnly
+	err := t.dbc.DeleteVulnerabilityDetailBucket()
+	if err != nil && !(t.dbc.AdvisoriesOnly && xerrors.Is(err, bolt.ErrBucketNotFound)) {
 		return xerrors.Errorf("failed to delete vulnerability detail bucket: %w", err)
 	}
 
@@ -169,5 +425,9 @@ func (t TrivyDB) cleanup() error {
 		return xerrors.Errorf("failed to delete advisory detail bucket: %w", err)
 	}
 
+	if err := t.dbc.DeleteCheckpoints(); err != nil {
+		return xerrors.Errorf("failed to delete checkpoints: %w", err)
+	}
+
 	return nil
 }
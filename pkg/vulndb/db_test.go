@@ -1,15 +1,22 @@
 package vulndb_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	fixtures "github.com/aquasecurity/bolt-fixtures"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
 	"golang.org/x/xerrors"
 	"k8s.io/utils/clock"
 	fake "k8s.io/utils/clock/testing"
@@ -20,6 +27,8 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulndb"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/node"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/nvd"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 )
 
@@ -34,6 +43,13 @@ func (f fakeVulnSrc) Update(dir string) error {
 	return nil
 }
 
+type fakeNamedVulnSrc struct {
+	fakeVulnSrc
+	name types.SourceID
+}
+
+func (f fakeNamedVulnSrc) Name() types.SourceID { return f.name }
+
 func TestTrivyDB_Insert(t *testing.T) {
 	type fields struct {
 		cacheDir string
@@ -123,6 +139,7 @@ func TestTrivyDB_Insert(t *testing.T) {
 func TestTrivyDB_Build(t *testing.T) {
 	modified := time.Date(2020, 8, 24, 17, 37, 0, 0, time.UTC)
 	published := time.Date(2019, 4, 7, 0, 29, 0, 0, time.UTC)
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
 
 	type wantKV struct {
 		key   []string
@@ -151,15 +168,18 @@ func TestTrivyDB_Build(t *testing.T) {
 				{
 					key: []string{"vulnerability", "CVE-2019-10906"},
 					value: types.Vulnerability{
-						Title:       "python-jinja2: str.format_map allows sandbox escape",
-						Description: "In Pallets Jinja before 2.10.1, str.format_map allows a sandbox escape.",
-						Severity:    "HIGH",
+						Title:          "python-jinja2: str.format_map allows sandbox escape",
+						Description:    "In Pallets Jinja before 2.10.1, str.format_map allows a sandbox escape.",
+						Severity:       "HIGH",
+						SeveritySource: "nvd",
+						SeverityRank:   3,
 						VendorSeverity: map[types.SourceID]types.Severity{
 							vulnerability.NVD:    types.SeverityHigh,
 							vulnerability.RedHat: types.SeverityCritical,
 						},
 						PublishedDate:    &published,
 						LastModifiedDate: &modified,
+						FirstSeen:        &now,
 					},
 				},
 			},
@@ -188,7 +208,7 @@ func TestTrivyDB_Build(t *testing.T) {
 			cacheDir := dbtest.InitDB(t, tt.fixtures)
 			defer db.Close()
 
-			full := vulndb.New(cacheDir, 12*time.Hour)
+			full := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithClock(fake.NewFakeClock(now)))
 			err := full.Build(nil)
 			if tt.wantErr != "" {
 				require.NotNil(t, err)
@@ -211,3 +231,771 @@ func TestTrivyDB_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestTrivyDB_BuildWithNVDEnrichment(t *testing.T) {
+	modified := time.Date(2020, 8, 24, 17, 37, 0, 0, time.UTC)
+	published := time.Date(2019, 4, 7, 0, 29, 0, 0, time.UTC)
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// CVE-2019-10906 has no vulnerability detail
+	cacheDir := dbtest.InitDB(t, []string{
+		"testdata/fixtures/happy/vulnid.yaml",
+		"testdata/fixtures/enrichment/vulnerability-detail.yaml",
+		"testdata/fixtures/happy/advisory-detail.yaml",
+	})
+	defer db.Close()
+
+	details, err := nvd.Load("testdata/nvd")
+	require.NoError(t, err)
+
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithClock(fake.NewFakeClock(now)), vulndb.WithNVDEnrichment(details))
+	require.NoError(t, c.Build(nil))
+	require.NoError(t, db.Close())
+
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"vulnerability", "CVE-2019-10906"}, types.Vulnerability{
+		Title:          "In Pallets Jinja before 2.10.1, str.format_map allows a sandbox escape",
+		Description:    "In Pallets Jinja before 2.10.1, str.format_map allows a sandbox escape.",
+		Severity:       "HIGH",
+		SeveritySource: "nvd",
+		SeverityRank:   3,
+		VendorSeverity: types.VendorSeverity{
+			vulnerability.NVD: types.SeverityHigh,
+		},
+		CVSS: types.VendorCVSS{
+			vulnerability.NVD: {
+				V3Vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:N/A:N",
+				V3Score:  8.6,
+			},
+		},
+		References:       []string{"https://palletsprojects.com/blog/jinja-2-10-1-released"},
+		PublishedDate:    &published,
+		LastModifiedDate: &modified,
+		FirstSeen:        &now,
+	})
+
+	// CVE-2021-3669 already has a title
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"vulnerability", "CVE-2021-3669"}, types.Vulnerability{
+		Title:          "CVE-2021-3669 kernel: reading /proc/sysvipc/shm does not scale with large shared memory segment counts",
+		Severity:       "MEDIUM",
+		SeveritySource: "redhat",
+		SeverityRank:   2,
+		VendorSeverity: types.VendorSeverity{
+			vulnerability.RedHat: types.SeverityMedium,
+		},
+		FirstSeen: &now,
+	})
+}
+
+func TestTrivyDB_BuildFirstSeen(t *testing.T) {
+	fixtureFiles := []string{
+		"testdata/fixtures/happy/vulnid.yaml",
+		"testdata/fixtures/happy/vulnerability-detail.yaml",
+		"testdata/fixtures/happy/advisory-detail.yaml",
+	}
+	cacheDir := dbtest.InitDB(t, fixtureFiles)
+
+	first := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithClock(fake.NewFakeClock(first)))
+	require.NoError(t, c.Build(nil))
+
+	got, err := db.Config{}.GetVulnerability("CVE-2019-10906")
+	require.NoError(t, err)
+	require.NotNil(t, got.FirstSeen)
+	assert.Equal(t, first, *got.FirstSeen)
+	require.NoError(t, db.Close())
+
+	// The next build inserts the same CVE again
+	loader, err := fixtures.New(db.Path(cacheDir), fixtureFiles)
+	require.NoError(t, err)
+	require.NoError(t, loader.Load())
+	require.NoError(t, loader.Close())
+
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	second := first.Add(24 * time.Hour)
+	c = vulndb.New(cacheDir, 12*time.Hour, vulndb.WithClock(fake.NewFakeClock(second)))
+	require.NoError(t, c.Build(nil))
+
+	got, err = db.Config{}.GetVulnerability("CVE-2019-10906")
+	require.NoError(t, err)
+	require.NotNil(t, got.FirstSeen)
+	assert.Equal(t, first, *got.FirstSeen)
+}
+
+// runawayVulnSrc stores the given number of advisories for one package
+type runawayVulnSrc struct {
+	dbc   db.Operation
+	count int
+}
+
+func (s runawayVulnSrc) Name() types.SourceID { return "runaway" }
+
+func (s runawayVulnSrc) Update(string) error {
+	return s.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		for i := 0; i < s.count; i++ {
+			vulnID := fmt.Sprintf("CVE-2021-%04d", i)
+			err := s.dbc.PutAdvisoryDetail(tx, vulnID, "example", []string{"runaway"}, types.Advisory{FixedVersion: "1.0.0"})
+			if err != nil {
+				return err
+			}
+			err = s.dbc.PutVulnerabilityDetail(tx, vulnID, "runaway", types.VulnerabilityDetail{Title: vulnID})
+			if err != nil {
+				return err
+			}
+			if err = s.dbc.PutVulnerabilityID(tx, vulnID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// pluginVulnSrc is a source implemented outside this repository and added by vulnsrc.Register
+type pluginVulnSrc struct{}
+
+func (pluginVulnSrc) Name() types.SourceID { return "plugin" }
+
+func (pluginVulnSrc) Update(string) error {
+	dbc := db.Config{}
+	return dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		err := dbc.PutAdvisoryDetail(tx, "CVE-2021-0001", "example", []string{"plugin"}, types.Advisory{FixedVersion: "1.0.0"})
+		if err != nil {
+			return err
+		}
+		if err = dbc.PutVulnerabilityDetail(tx, "CVE-2021-0001", "plugin", types.VulnerabilityDetail{Title: "plugin"}); err != nil {
+			return err
+		}
+		return dbc.PutVulnerabilityID(tx, "CVE-2021-0001")
+	})
+}
+
+var registerPlugin sync.Once
+
+func TestTrivyDB_BuildRegisteredSource(t *testing.T) {
+	registerPlugin.Do(func() {
+		vulnsrc.Register(pluginVulnSrc{})
+	})
+
+	// Names must be unique
+	assert.Panics(t, func() { vulnsrc.Register(pluginVulnSrc{}) })
+	assert.Panics(t, func() { vulnsrc.Register(fakeNamedVulnSrc{name: vulnerability.NVD}) })
+
+	cacheDir := t.TempDir()
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	c := vulndb.New(cacheDir, 12*time.Hour)
+	require.NoError(t, c.Build([]string{"plugin"}))
+	db.Close()
+
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"plugin", "example", "CVE-2021-0001"}, types.Advisory{FixedVersion: "1.0.0"})
+}
+
+func TestTrivyDB_BuildMaxAdvisoriesPerPackage(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		wantErr string
+	}{
+		{
+			name:  "within the limit",
+			count: 3,
+		},
+		{
+			name:    "exceeding the limit",
+			count:   4,
+			wantErr: "too many advisories for runaway/example (more than 3)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			require.NoError(t, db.Init(cacheDir))
+			defer db.Close()
+
+			dbc := db.Config{MaxAdvisoriesPerPackage: 3}
+			vulnsrcs := map[types.SourceID]vulnsrc.VulnSrc{
+				"runaway": runawayVulnSrc{dbc: dbc, count: tt.count},
+			}
+			c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithDBConfig(dbc), vulndb.WithVulnSrcs(vulnsrcs))
+			err := c.Build([]string{"runaway"})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTrivyDB_BuildFilteredIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		dbc        db.Config
+		wantValues map[string]types.Advisory
+		skipIDs    []string
+	}{
+		{
+			name: "excluded",
+			dbc:  db.Config{ExcludeIDs: []string{"CVE-2019-14905"}},
+			wantValues: map[string]types.Advisory{
+				"CVE-2019-14904": {FixedVersion: "2.9.3-r0"},
+				"CVE-2020-1737":  {FixedVersion: "2.9.6-r0"},
+			},
+			skipIDs: []string{"CVE-2019-14905"},
+		},
+		{
+			name: "included",
+			dbc:  db.Config{IncludeIDs: []string{"CVE-2019-10906", "CVE-2020-1737"}},
+			wantValues: map[string]types.Advisory{
+				"CVE-2020-1737": {FixedVersion: "2.9.6-r0"},
+			},
+			skipIDs: []string{"CVE-2019-14904", "CVE-2019-14905"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			copyFile(t, "testdata/alpine/vuln-list/alpine/ansible.json", filepath.Join(cacheDir, "vuln-list", "alpine", "ansible.json"))
+			copyFile(t, "testdata/nvd/vuln-list/nvd/CVE-2019-10906.json", filepath.Join(cacheDir, "vuln-list", "nvd", "CVE-2019-10906.json"))
+			require.NoError(t, db.Init(cacheDir))
+			defer db.Close()
+
+			// The filters reach every built-in source through the build config
+			c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithDBConfig(tt.dbc))
+			require.NoError(t, c.Build([]string{"alpine", "nvd"}))
+			require.NoError(t, db.Close())
+
+			dbPath := db.Path(cacheDir)
+			for vulnID, want := range tt.wantValues {
+				dbtest.JSONEq(t, dbPath, []string{"alpine 3.12", "ansible", vulnID}, want)
+			}
+			for _, vulnID := range tt.skipIDs {
+				dbtest.NoKey(t, dbPath, []string{"alpine 3.12", "ansible", vulnID})
+			}
+		})
+	}
+}
+
+func TestTrivyDB_BuildNVDOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		dbc   db.Config
+		check func(t *testing.T, dbc db.Config, cacheDir string)
+	}{
+		{
+			name: "CPE index",
+			dbc:  db.Config{BuildCPEIndex: true},
+			check: func(t *testing.T, dbc db.Config, _ string) {
+				got, err := dbc.GetByCPE("cpe:2.3:a:palletsprojects:jinja:*:*:*:*:*:*:*:*")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"CVE-2019-10906"}, got)
+			},
+		},
+		{
+			name: "text index",
+			dbc:  db.Config{BuildTextIndex: true},
+			check: func(t *testing.T, dbc db.Config, _ string) {
+				got, err := dbc.SearchText("sandbox escape")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"CVE-2019-10906"}, got)
+			},
+		},
+		{
+			name: "advisories only",
+			dbc:  db.Config{AdvisoriesOnly: true},
+			check: func(t *testing.T, _ db.Config, cacheDir string) {
+				require.NoError(t, db.Close())
+				dbtest.NoBucket(t, db.Path(cacheDir), []string{"vulnerability"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			copyFile(t, "testdata/alpine/vuln-list/alpine/ansible.json", filepath.Join(cacheDir, "vuln-list", "alpine", "ansible.json"))
+			copyFile(t, "testdata/nvd/vuln-list/nvd/CVE-2019-10906.json", filepath.Join(cacheDir, "vuln-list", "nvd", "CVE-2019-10906.json"))
+			require.NoError(t, db.Init(cacheDir))
+			defer db.Close()
+
+			c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithDBConfig(tt.dbc))
+			require.NoError(t, c.Build([]string{"alpine", "nvd"}))
+			tt.check(t, tt.dbc, cacheDir)
+		})
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	b, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0700))
+	require.NoError(t, os.WriteFile(dst, b, 0600))
+}
+
+func TestTrivyDB_BuildConcurrently(t *testing.T) {
+	counts := []int{2, 3}
+	cacheDirs := make([]string, len(counts))
+	errs := make([]error, len(counts))
+
+	var wg sync.WaitGroup
+	for i, count := range counts {
+		cacheDirs[i] = t.TempDir()
+		wg.Add(1)
+		go func(i, count int) {
+			defer wg.Done()
+			dbc, err := db.Config{OutputPath: db.Path(cacheDirs[i])}.Open()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			vulnsrcs := map[types.SourceID]vulnsrc.VulnSrc{
+				"runaway": runawayVulnSrc{dbc: dbc, count: count},
+			}
+			c := vulndb.New(cacheDirs[i], 12*time.Hour, vulndb.WithDBConfig(dbc), vulndb.WithVulnSrcs(vulnsrcs))
+			if errs[i] = c.Build([]string{"runaway"}); errs[i] != nil {
+				_ = dbc.Discard()
+				return
+			}
+			errs[i] = dbc.Finalize()
+		}(i, count)
+	}
+	wg.Wait()
+
+	for i, count := range counts {
+		require.NoError(t, errs[i])
+
+		dbPath := db.Path(cacheDirs[i])
+		for j := 0; j < count; j++ {
+			vulnID := fmt.Sprintf("CVE-2021-%04d", j)
+			dbtest.JSONEq(t, dbPath, []string{"runaway", "example", vulnID}, types.Advisory{FixedVersion: "1.0.0"})
+		}
+		dbtest.NoKey(t, dbPath, []string{"runaway", "example", fmt.Sprintf("CVE-2021-%04d", count)})
+
+		// No temporary file is left
+		files, err := filepath.Glob(dbPath + ".*.tmp")
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	}
+}
+
+func TestTrivyDB_BuildConcurrentlyBuiltin(t *testing.T) {
+	// Each build excludes a different vulnerability, so a DB written with the config of the other build is detected
+	excludeIDs := []string{"CVE-2019-14904", "CVE-2020-1737"}
+	fixedVersions := []string{"2.9.3-r0", "2.9.6-r0"}
+	cacheDirs := make([]string, len(excludeIDs))
+	errs := make([]error, len(excludeIDs))
+
+	var wg sync.WaitGroup
+	for i, excludeID := range excludeIDs {
+		cacheDirs[i] = t.TempDir()
+		copyFile(t, "testdata/alpine/vuln-list/alpine/ansible.json", filepath.Join(cacheDirs[i], "vuln-list", "alpine", "ansible.json"))
+		copyFile(t, "testdata/nvd/vuln-list/nvd/CVE-2019-10906.json", filepath.Join(cacheDirs[i], "vuln-list", "nvd", "CVE-2019-10906.json"))
+		wg.Add(1)
+		go func(i int, excludeID string) {
+			defer wg.Done()
+			dbc, err := db.Config{OutputPath: db.Path(cacheDirs[i]), ExcludeIDs: []string{excludeID}}.Open()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			c := vulndb.New(cacheDirs[i], 12*time.Hour, vulndb.WithDBConfig(dbc))
+			if errs[i] = c.Build([]string{"alpine", "nvd"}); errs[i] != nil {
+				_ = dbc.Discard()
+				return
+			}
+			errs[i] = dbc.Finalize()
+		}(i, excludeID)
+	}
+	wg.Wait()
+
+	for i, excludeID := range excludeIDs {
+		require.NoError(t, errs[i])
+
+		dbPath := db.Path(cacheDirs[i])
+		dbtest.JSONEq(t, dbPath, []string{"alpine 3.12", "ansible", "CVE-2019-14905"}, types.Advisory{FixedVersion: "2.9.3-r0"})
+		dbtest.NoKey(t, dbPath, []string{"alpine 3.12", "ansible", excludeID})
+		dbtest.JSONEq(t, dbPath, []string{"alpine 3.12", "ansible", excludeIDs[1-i]}, types.Advisory{FixedVersion: fixedVersions[1-i]})
+	}
+}
+
+func TestTrivyDB_InsertWithProgress(t *testing.T) {
+	type progress struct {
+		source      string
+		done, total int
+	}
+	var got []progress
+	dbc := db.Config{
+		ProgressFn: func(source string, done, total int) {
+			got = append(got, progress{source: source, done: done, total: total})
+		},
+	}
+
+	cacheDir := t.TempDir()
+	vulnDir := filepath.Join(cacheDir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	advisory := `{"id": 100, "module_name": "example", "cves": ["CVE-2016-10000"], "vulnerable_versions": "<1.0.1", "patched_versions": ">=1.0.1"}`
+	require.NoError(t, os.WriteFile(filepath.Join(vulnDir, "100.json"), []byte(advisory), 0600))
+
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithDBConfig(dbc))
+	err := c.Insert([]string{string(vulnerability.NodejsSecurityWg)})
+	require.NoError(t, err)
+
+	want := []progress{
+		{source: "nodejs-security-wg", done: 1, total: 1},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestTrivyDB_BuildObsolete(t *testing.T) {
+	cacheDir := t.TempDir()
+	vulnDir := filepath.Join(cacheDir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	advisories := map[string]string{
+		"100.json": `{"id": 100, "module_name": "example", "cves": ["CVE-2016-10000"], "vulnerable_versions": "<1.0.1", "patched_versions": ">=1.0.1"}`,
+		"101.json": `{"id": 101, "module_name": "example", "cves": ["CVE-2016-10001"], "vulnerable_versions": "<1.0.2", "patched_versions": ">=1.0.2"}`,
+	}
+	for name, advisory := range advisories {
+		require.NoError(t, os.WriteFile(filepath.Join(vulnDir, name), []byte(advisory), 0600))
+	}
+
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	targets := []string{string(vulnerability.NodejsSecurityWg)}
+	require.NoError(t, vulndb.New(cacheDir, 12*time.Hour).Build(targets))
+
+	// The advisory is withdrawn upstream
+	require.NoError(t, os.Remove(filepath.Join(vulnDir, "101.json")))
+	require.NoError(t, vulndb.New(cacheDir, 12*time.Hour).Build(targets))
+
+	ns := "npm::Node.js Ecosystem Security Working Group"
+	got, err := db.Config{}.GetAdvisories(ns, "example")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "CVE-2016-10000", got[0].VulnerabilityID)
+
+	// The obsolete advisory is kept for auditing
+	got, err = db.Config{IncludeObsolete: true}.GetAdvisories(ns, "example")
+	require.NoError(t, err)
+	obsolete := map[string]bool{}
+	for _, adv := range got {
+		obsolete[adv.VulnerabilityID] = adv.Obsolete
+	}
+	assert.Equal(t, map[string]bool{
+		"CVE-2016-10000": false,
+		"CVE-2016-10001": true,
+	}, obsolete)
+}
+
+// countingVulnSrc counts the updates of the wrapped source
+type countingVulnSrc struct {
+	vulnsrc.VulnSrc
+	count *int
+}
+
+func (s countingVulnSrc) Update(dir string) error {
+	*s.count++
+	return s.VulnSrc.Update(dir)
+}
+
+// crashingVulnSrc fails while crash is true, e.g. killed halfway
+type crashingVulnSrc struct {
+	crash *bool
+}
+
+func (s crashingVulnSrc) Name() types.SourceID { return "crashing" }
+
+func (s crashingVulnSrc) Update(string) error {
+	if *s.crash {
+		return xerrors.New("crashed")
+	}
+	return nil
+}
+
+func TestTrivyDB_InsertResume(t *testing.T) {
+	cacheDir := t.TempDir()
+	require.NoError(t, node.WriteSelfTestData(cacheDir))
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	var nodeCount int
+	crash := true
+	vulnsrcs := map[types.SourceID]vulnsrc.VulnSrc{
+		vulnerability.NodejsSecurityWg: countingVulnSrc{VulnSrc: node.NewVulnSrc(), count: &nodeCount},
+		"crashing":                     crashingVulnSrc{crash: &crash},
+	}
+	targets := []string{string(vulnerability.NodejsSecurityWg), "crashing"}
+
+	// The build fails after the node source
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithVulnSrcs(vulnsrcs))
+	err := c.Insert(targets)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "crashing update error")
+	assert.Equal(t, 1, nodeCount)
+
+	// The restarted build skips the node source
+	crash = false
+	c = vulndb.New(cacheDir, 12*time.Hour, vulndb.WithVulnSrcs(vulnsrcs))
+	require.NoError(t, c.Insert(targets))
+	assert.Equal(t, 1, nodeCount)
+
+	details, err := db.Config{}.GetVulnerabilityDetail("CVE-2014-7205")
+	require.NoError(t, err)
+	assert.Contains(t, details, vulnerability.NodejsSecurityWg)
+
+	// --force updates every source
+	c = vulndb.New(cacheDir, 12*time.Hour, vulndb.WithVulnSrcs(vulnsrcs), vulndb.WithForce())
+	require.NoError(t, c.Insert(targets))
+	assert.Equal(t, 2, nodeCount)
+}
+
+func TestTrivyDB_BuildWithPluginRefs(t *testing.T) {
+	cacheDir := t.TempDir()
+	require.NoError(t, node.WriteSelfTestData(cacheDir))
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	refs := map[string]map[string][]string{
+		"CVE-2014-7205": {"tenable": {"78888"}},
+		"CVE-2099-0001": {"tenable": {"99999"}}, // not in the DB
+	}
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithPluginRefs(refs))
+	require.NoError(t, c.Build([]string{string(vulnerability.NodejsSecurityWg)}))
+
+	got, err := db.Config{}.GetVulnerability("CVE-2014-7205")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"tenable": {"78888"}}, got.PluginRefs)
+	assert.Equal(t, "MEDIUM", got.Severity)
+
+	require.NoError(t, db.Close())
+	dbtest.NoKey(t, db.Path(cacheDir), []string{"vulnerability", "CVE-2099-0001"})
+}
+
+type slowVulnSrc struct{}
+
+func (s slowVulnSrc) Name() types.SourceID { return "slow" }
+
+func (s slowVulnSrc) Update(string) error {
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+// hangingVulnSrc keeps writing in a transaction until released, e.g. on a stalled download of a huge feed.
+// done is closed when it returns.
+type hangingVulnSrc struct {
+	dbc     db.Config
+	release chan struct{}
+	done    chan struct{}
+}
+
+func newHangingVulnSrc() hangingVulnSrc {
+	return hangingVulnSrc{release: make(chan struct{}), done: make(chan struct{})}
+}
+
+func (s hangingVulnSrc) Name() types.SourceID { return "hanging" }
+
+func (s hangingVulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	s.dbc = s.dbc.WithContext(ctx)
+	return s.Update(dir)
+}
+
+func (s hangingVulnSrc) Update(string) error {
+	defer close(s.done)
+	return s.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		for i := 0; ; i++ {
+			select {
+			case <-s.release:
+				return nil
+			case <-time.After(time.Millisecond):
+			}
+			vulnID := fmt.Sprintf("CVE-2021-%04d", i)
+			if err := s.dbc.PutAdvisoryDetail(tx, vulnID, "example", []string{"hanging"}, types.Advisory{}); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func TestTrivyDB_InsertSourceTimeout(t *testing.T) {
+	cacheDir := t.TempDir()
+	require.NoError(t, node.WriteSelfTestData(cacheDir))
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	hanging := newHangingVulnSrc()
+	defer close(hanging.release)
+
+	vulnsrcs := map[types.SourceID]vulnsrc.VulnSrc{
+		"hanging":                      hanging,
+		vulnerability.NodejsSecurityWg: node.NewVulnSrc(),
+	}
+	dbc := db.Config{SourceTimeout: 50 * time.Millisecond}
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithDBConfig(dbc), vulndb.WithVulnSrcs(vulnsrcs))
+	require.NoError(t, c.Insert([]string{"hanging", string(vulnerability.NodejsSecurityWg)}))
+
+	stats := c.Stats()
+	assert.Equal(t, []types.SourceID{"hanging"}, stats.TimedOut)
+	assert.Contains(t, stats.Durations, vulnerability.NodejsSecurityWg)
+	assert.NotContains(t, stats.Durations, types.SourceID("hanging"))
+
+	details, err := db.Config{}.GetVulnerabilityDetail("CVE-2014-7205")
+	require.NoError(t, err)
+	assert.Contains(t, details, vulnerability.NodejsSecurityWg)
+
+	// The aborted source is updated again by the next build
+	done, err := db.Config{}.Checkpointed("hanging")
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	// The writes of the aborted source are rolled back
+	<-hanging.done
+	err = db.Config{}.Connection().View(func(tx *bolt.Tx) error {
+		assert.Nil(t, tx.Bucket([]byte("advisory-detail")).Bucket([]byte("CVE-2021-0000")))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestTrivyDB_BuildFailOnSourceError(t *testing.T) {
+	cacheDir := t.TempDir()
+	require.NoError(t, node.WriteSelfTestData(cacheDir))
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	hanging := newHangingVulnSrc()
+	defer close(hanging.release)
+
+	crash := true
+	vulnsrcs := map[types.SourceID]vulnsrc.VulnSrc{
+		"crashing":                     crashingVulnSrc{crash: &crash},
+		"hanging":                      hanging,
+		vulnerability.NodejsSecurityWg: node.NewVulnSrc(),
+	}
+	dbc := db.Config{
+		SourceTimeout:     50 * time.Millisecond,
+		FailOnSourceError: true,
+	}
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithDBConfig(dbc), vulndb.WithVulnSrcs(vulnsrcs))
+	err := c.Build([]string{"crashing", "hanging", string(vulnerability.NodejsSecurityWg)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 source(s) failed")
+	assert.Contains(t, err.Error(), "crashing update error: crashed")
+	assert.Contains(t, err.Error(), "hanging timed out after 50ms")
+
+	// The succeeding source is still updated, so that a restarted build can skip it
+	done, err := db.Config{}.Checkpointed(vulnerability.NodejsSecurityWg)
+	require.NoError(t, err)
+	assert.True(t, done)
+	for _, source := range []types.SourceID{"crashing", "hanging"} {
+		done, err = db.Config{}.Checkpointed(source)
+		require.NoError(t, err)
+		assert.False(t, done, source)
+	}
+}
+
+func TestTrivyDB_Stats(t *testing.T) {
+	cacheDir := t.TempDir()
+	require.NoError(t, db.Init(cacheDir))
+	defer db.Close()
+
+	vulnsrcs := map[types.SourceID]vulnsrc.VulnSrc{
+		"fake": fakeVulnSrc{},
+		"slow": slowVulnSrc{},
+	}
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithVulnSrcs(vulnsrcs))
+	require.NoError(t, c.Insert([]string{"fake", "slow"}))
+
+	durations := c.Stats().Durations
+	require.Len(t, durations, 2)
+	assert.GreaterOrEqual(t, durations["slow"], 10*time.Millisecond)
+	assert.Less(t, durations["fake"], durations["slow"])
+}
+
+func TestTrivyDB_BuildStaleSource(t *testing.T) {
+	// The NVD detail in the fixtures was last modified on 2020-08-24
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		staleAfter time.Duration
+		wantWarn   bool
+	}{
+		{
+			name:       "stale",
+			staleAfter: 30 * 24 * time.Hour,
+			wantWarn:   true,
+		},
+		{
+			name:       "fresh",
+			staleAfter: 365 * 24 * time.Hour,
+		},
+		{
+			name: "disabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := dbtest.InitDB(t, []string{
+				"testdata/fixtures/happy/vulnid.yaml",
+				"testdata/fixtures/happy/vulnerability-detail.yaml",
+				"testdata/fixtures/happy/advisory-detail.yaml",
+			})
+			defer db.Close()
+
+			var buf bytes.Buffer
+			log.SetOutput(&buf)
+			defer log.SetOutput(os.Stderr)
+
+			dbc := db.Config{StaleAfter: tt.staleAfter}
+			c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithDBConfig(dbc), vulndb.WithClock(fake.NewFakeClock(now)))
+			require.NoError(t, c.Build(nil))
+
+			assert.Equal(t, time.Date(2020, 8, 24, 17, 37, 0, 0, time.UTC), c.Stats().LatestModified[vulnerability.NVD])
+
+			warning := "WARN: nvd may be stale: the latest modified date is 2020-08-24T17:37:00Z"
+			if tt.wantWarn {
+				assert.Contains(t, buf.String(), warning)
+			} else {
+				assert.NotContains(t, buf.String(), "WARN:")
+			}
+		})
+	}
+}
+
+func TestTrivyDB_BuildBucketHashes(t *testing.T) {
+	cacheDir := dbtest.InitDB(t, []string{
+		"testdata/fixtures/happy/vulnid.yaml",
+		"testdata/fixtures/happy/vulnerability-detail.yaml",
+		"testdata/fixtures/happy/advisory-detail.yaml",
+	})
+	defer db.Close()
+
+	dbc := db.Config{BucketHashes: true}
+	c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithDBConfig(dbc))
+	require.NoError(t, c.Build(nil))
+
+	tampered, err := dbc.VerifyBucketHashes()
+	require.NoError(t, err)
+	assert.Empty(t, tampered)
+
+	// Lower the fixed version after the build
+	err = dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte("Red Hat Enterprise Linux 8")).Bucket([]byte("python-jinja2"))
+		return bkt.Put([]byte("CVE-2019-10906"), []byte(`{"FixedVersion":"2.10.0"}`))
+	})
+	require.NoError(t, err)
+
+	tampered, err = dbc.VerifyBucketHashes()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Red Hat Enterprise Linux 8"}, tampered)
+}
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, vulndb.SelfTest())
+}
@@ -0,0 +1,143 @@
+package vulndb
+
+import (
+	"os"
+	"reflect"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/node"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+const selfTestNamespace = "npm::Node.js Ecosystem Security Working Group"
+
+// selfTestAdvisories are the advisories expected in selfTestNamespace, keyed by package name and vulnerability ID
+var selfTestAdvisories = map[string]map[string]types.Advisory{
+	"bassmaster": {
+		"CVE-2014-7205": {
+			VulnerableVersions: []string{"<=1.5.1"},
+			PatchedVersions:    []string{">=1.5.2"},
+			FixCommits:         []string{"https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
+		},
+	},
+	"hubl-server": {
+		"NSWG-ECO-334": {
+			VulnerableVersions: []string{"<=99.999.99999"},
+			PatchedVersions:    []string{"<0.0.0"},
+		},
+	},
+	"lodash": {
+		"CVE-2018-16487": {
+			VulnerableVersions: []string{"<4.17.11"},
+			PatchedVersions:    []string{">=4.17.11"},
+		},
+	},
+}
+
+// selfTestSeverities are the severities expected in the vulnerability bucket
+var selfTestSeverities = map[string]string{
+	"CVE-2014-7205":  "MEDIUM",
+	"NSWG-ECO-334":   "UNKNOWN",
+	"CVE-2018-16487": "HIGH",
+}
+
+// SelfTest builds a DB from advisories embedded in the binary and checks the numbers of advisories and vulnerabilities
+// along with a few lookups, so that the build logic can be verified end-to-end before shipping a DB.
+// It runs without network and doesn't touch the DB opened by db.Init.
+func SelfTest() error {
+	dir, err := os.MkdirTemp("", "trivy-db-self-test-")
+	if err != nil {
+		return xerrors.Errorf("failed to create a temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = node.WriteSelfTestData(dir); err != nil {
+		return xerrors.Errorf("self-test data error: %w", err)
+	}
+
+	dbc, err := db.Config{OutputPath: db.Path(dir)}.Open()
+	if err != nil {
+		return xerrors.Errorf("failed to open the self-test DB: %w", err)
+	}
+	defer dbc.Discard()
+
+	src := node.NewVulnSrc(node.WithDBConfig(dbc))
+	tdb := New(dir, time.Hour, WithDBConfig(dbc), WithVulnSrcs(map[types.SourceID]vulnsrc.VulnSrc{
+		src.Name(): src,
+	}))
+	if err = tdb.Build([]string{string(src.Name())}); err != nil {
+		return xerrors.Errorf("self-test build error: %w", err)
+	}
+
+	if err = checkSelfTest(dbc); err != nil {
+		return xerrors.Errorf("self-test failed: %w", err)
+	}
+	return nil
+}
+
+func checkSelfTest(dbc db.Config) error {
+	var advisories, vulns int
+	err := dbc.Connection().View(func(tx *bolt.Tx) error {
+		if bkt := tx.Bucket([]byte(selfTestNamespace)); bkt != nil {
+			err := bkt.ForEach(func(pkgName, v []byte) error {
+				if v == nil {
+					advisories += bkt.Bucket(pkgName).Stats().KeyN
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if bkt := tx.Bucket([]byte("vulnerability")); bkt != nil {
+			vulns = bkt.Stats().KeyN
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to count: %w", err)
+	}
+
+	if advisories != len(selfTestSeverities) {
+		return xerrors.Errorf("got %d advisories, want %d", advisories, len(selfTestSeverities))
+	} else if vulns != len(selfTestSeverities) {
+		return xerrors.Errorf("got %d vulnerabilities, want %d", vulns, len(selfTestSeverities))
+	}
+
+	for pkgName, want := range selfTestAdvisories {
+		got, err := dbc.GetAdvisories(selfTestNamespace, pkgName)
+		if err != nil {
+			return xerrors.Errorf("failed to get advisories of %s: %w", pkgName, err)
+		} else if len(got) != len(want) {
+			return xerrors.Errorf("got %d advisories of %s, want %d", len(got), pkgName, len(want))
+		}
+		for _, adv := range got {
+			if adv.DataSource == nil || adv.DataSource.ID != vulnerability.NodejsSecurityWg {
+				return xerrors.Errorf("unexpected data source of %s: %+v", pkgName, adv.DataSource)
+			}
+			adv.DataSource = nil
+
+			wantAdv := want[adv.VulnerabilityID]
+			wantAdv.VulnerabilityID = adv.VulnerabilityID
+			if !reflect.DeepEqual(adv, wantAdv) {
+				return xerrors.Errorf("unexpected advisory of %s: got %+v, want %+v", pkgName, adv, wantAdv)
+			}
+		}
+	}
+
+	for vulnID, want := range selfTestSeverities {
+		vuln, err := dbc.GetVulnerability(vulnID)
+		if err != nil {
+			return xerrors.Errorf("failed to get %s: %w", vulnID, err)
+		} else if vuln.Severity != want {
+			return xerrors.Errorf("got %s severity of %s, want %s", vuln.Severity, vulnID, want)
+		}
+	}
+	return nil
+}
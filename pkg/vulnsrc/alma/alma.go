@@ -1,6 +1,7 @@
 package alma
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -32,20 +33,43 @@ var (
 	}
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", almaDir)
 	errata := map[string][]Erratum{}
@@ -99,9 +123,11 @@ func (vs VulnSrc) save(errataVer map[string][]Erratum) error {
 func (vs VulnSrc) commit(tx *bolt.Tx, platformName string, errata []Erratum) error {
 	for _, erratum := range errata {
 		var references []string
+		var refs []db.Reference
 		for _, ref := range erratum.References {
 			if ref.Type != "cve" {
 				references = append(references, ref.Href)
+				refs = append(refs, db.Reference{Type: ref.Type, URL: ref.Href, Title: ref.Title})
 			}
 		}
 
@@ -138,10 +164,11 @@ func (vs VulnSrc) commit(tx *bolt.Tx, platformName string, errata []Erratum) err
 				}
 
 				vuln := types.VulnerabilityDetail{
-					Severity:    generalizeSeverity(erratum.Severity),
-					Title:       erratum.Title,
-					Description: erratum.Description,
-					References:  references,
+					Severity:        generalizeSeverity(erratum.Severity),
+					Title:           erratum.Title,
+					Description:     erratum.Description,
+					References:      references,
+					ReferenceTitles: db.ReferenceTitles(refs),
 				}
 				if err := vs.dbc.PutVulnerabilityDetail(tx, cveID, source.ID, vuln); err != nil {
 					return xerrors.Errorf("failed to save Alma vulnerability: %w", err)
@@ -1,6 +1,7 @@
 package alpine
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -30,20 +31,43 @@ var (
 	}
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", alpineDir)
 	var advisories []advisory
@@ -1,6 +1,7 @@
 package amazon
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -33,6 +34,15 @@ var (
 	}
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc        db.Operation
 	advisories map[string][]ALAS
@@ -63,17 +73,31 @@ type Reference struct {
 	Href string `json:"href,omitempty"`
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc:        db.Config{},
 		advisories: map[string][]ALAS{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", amazonDir)
 
@@ -127,12 +151,10 @@ func (vs VulnSrc) commit(tx *bolt.Tx) error {
 			return xerrors.Errorf("failed to put data source: %w", err)
 		}
 		for _, alas := range alasList {
+			advisories := buildAdvisories(alas.Packages)
 			for _, cveID := range alas.CveIDs {
-				for _, pkg := range alas.Packages {
-					advisory := types.Advisory{
-						FixedVersion: utils.ConstructVersion(pkg.Epoch, pkg.Version, pkg.Release),
-					}
-					if err := vs.dbc.PutAdvisoryDetail(tx, cveID, pkg.Name, []string{platformName}, advisory); err != nil {
+				for pkgName, advisory := range advisories {
+					if err := vs.dbc.PutAdvisoryDetail(tx, cveID, pkgName, []string{platformName}, advisory); err != nil {
 						return xerrors.Errorf("failed to save Amazon advisory: %w", err)
 					}
 
@@ -162,6 +184,33 @@ func (vs VulnSrc) commit(tx *bolt.Tx) error {
 	return nil
 }
 
+// buildAdvisories builds an advisory per package name from the packages listed per architecture.
+// Source packages are skipped, and "noarch" makes the advisory apply to all architectures.
+func buildAdvisories(pkgs []Package) map[string]types.Advisory {
+	advisories := map[string]types.Advisory{}
+	noarch := map[string]bool{}
+	for _, pkg := range pkgs {
+		if pkg.Arch == "src" {
+			continue
+		}
+		advisory := advisories[pkg.Name]
+		advisory.FixedVersion = utils.ConstructVersion(pkg.Epoch, pkg.Version, pkg.Release)
+		if pkg.Arch == "" || pkg.Arch == "noarch" {
+			noarch[pkg.Name] = true
+		} else {
+			advisory.Arches = ustrings.Unique(append(advisory.Arches, pkg.Arch))
+		}
+		advisories[pkg.Name] = advisory
+	}
+
+	for name := range noarch {
+		advisory := advisories[name]
+		advisory.Arches = nil
+		advisories[name] = advisory
+	}
+	return advisories
+}
+
 // Get returns a security advisory
 func (vs VulnSrc) Get(version string, pkgName string) ([]types.Advisory, error) {
 	bucket := fmt.Sprintf(platformFormat, version)
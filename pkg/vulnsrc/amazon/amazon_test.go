@@ -49,22 +49,34 @@ func TestVulnSrc_Update(t *testing.T) {
 					key: []string{"advisory-detail", "CVE-2018-17456", "amazon linux 1", "git"},
 					value: types.Advisory{
 						FixedVersion: "2.14.5-1.59.amzn1",
+						Arches:       []string{"x86_64"},
 					},
 				},
 				{
 					key: []string{"advisory-detail", "CVE-2018-17456", "amazon linux 1", "git-debuginfo"},
 					value: types.Advisory{
 						FixedVersion: "1:2.14.5-1.59.amzn1",
+						Arches:       []string{"x86_64"},
 					},
 				},
 				{
 					key: []string{"advisory-detail", "CVE-2021-22543", "amazon linux 2", "kernel"},
 					value: types.Advisory{
 						FixedVersion: "4.14.243-185.433.amzn2",
+						Arches:       []string{"aarch64", "x86_64"},
 					},
 				},
 				{
+					// x86_64 only
 					key: []string{"advisory-detail", "CVE-2021-22543", "amazon linux 2", "kernel-headers"},
+					value: types.Advisory{
+						FixedVersion: "4.14.243-185.433.amzn2",
+						Arches:       []string{"x86_64"},
+					},
+				},
+				{
+					// all architectures
+					key: []string{"advisory-detail", "CVE-2021-22543", "amazon linux 2", "kernel-doc"},
 					value: types.Advisory{
 						FixedVersion: "4.14.243-185.433.amzn2",
 					},
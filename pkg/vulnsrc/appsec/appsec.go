@@ -0,0 +1,148 @@
+package appsec
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/bucket"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+const appsecDir = "appsec"
+
+var source = types.DataSource{
+	ID:   vulnerability.AppSec,
+	Name: "Application Security Advisories",
+}
+
+// Advisory is an entry of the YAML files under "vuln-list/appsec", e.g. postgresql.yaml
+type Advisory struct {
+	App            string   `yaml:"app"`
+	CVE            string   `yaml:"cve"`
+	AffectedRanges []string `yaml:"affected_ranges"`
+	Title          string   `yaml:"title"`
+	References     []string `yaml:"references"`
+}
+
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
+// VulnSrc ingests the advisories of applications shipped in images rather than as OS packages,
+// such as PostgreSQL, Redis and NGINX. They are stored under "app::<name>" namespaces with the application as the package.
+type VulnSrc struct {
+	dbc db.Operation
+}
+
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
+		dbc: db.Config{},
+	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
+}
+
+func (vs VulnSrc) Name() types.SourceID {
+	return source.ID
+}
+
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
+func (vs VulnSrc) Update(dir string) error {
+	rootDir := filepath.Join(dir, "vuln-list", appsecDir)
+	files, err := filepath.Glob(filepath.Join(rootDir, "*.yaml"))
+	if err != nil {
+		return xerrors.Errorf("glob error: %w", err)
+	}
+
+	var advisories []Advisory
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return xerrors.Errorf("file read error: %w", err)
+		}
+		var advs []Advisory
+		if err = yaml.Unmarshal(b, &advs); err != nil {
+			return xerrors.Errorf("YAML decode error (%s): %w", file, err)
+		}
+		advisories = append(advisories, advs...)
+	}
+
+	if err = vs.save(advisories); err != nil {
+		return xerrors.Errorf("save error: %w", err)
+	}
+	return nil
+}
+
+func (vs VulnSrc) save(advisories []Advisory) error {
+	log.Println("Saving application security advisories")
+	err := vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		for _, adv := range advisories {
+			if err := vs.commit(tx, adv); err != nil {
+				return xerrors.Errorf("commit error (%s): %w", adv.CVE, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("batch update error: %w", err)
+	}
+	return nil
+}
+
+func (vs VulnSrc) commit(tx *bolt.Tx, adv Advisory) error {
+	app := strings.ToLower(strings.TrimSpace(adv.App))
+	if app == "" || adv.CVE == "" || len(adv.AffectedRanges) == 0 {
+		return nil
+	}
+
+	bktName := bucket.Name(string(vulnerability.App), app)
+	if err := vs.dbc.PutDataSource(tx, bktName, source); err != nil {
+		return xerrors.Errorf("failed to put data source: %w", err)
+	}
+
+	advisory := types.Advisory{
+		VulnerableVersions: adv.AffectedRanges,
+	}
+	if err := vs.dbc.PutAdvisoryDetail(tx, adv.CVE, app, []string{bktName}, advisory); err != nil {
+		return xerrors.Errorf("failed to save the application advisory: %w", err)
+	}
+
+	vuln := types.VulnerabilityDetail{
+		Title:      adv.Title,
+		References: adv.References,
+	}
+	if err := vs.dbc.PutVulnerabilityDetail(tx, adv.CVE, source.ID, vuln); err != nil {
+		return xerrors.Errorf("failed to save the application vulnerability detail: %w", err)
+	}
+
+	// for optimization
+	if err := vs.dbc.PutVulnerabilityID(tx, adv.CVE); err != nil {
+		return xerrors.Errorf("failed to save the vulnerability ID: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package appsec_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/appsec"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+func TestVulnSrc_Update(t *testing.T) {
+	type wantKV struct {
+		key   []string
+		value interface{}
+	}
+	tests := []struct {
+		name    string
+		dir     string
+		want    []wantKV
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			dir:  filepath.Join("testdata", "happy"),
+			want: []wantKV{
+				{
+					key: []string{"data-source", "app::postgresql"},
+					value: types.DataSource{
+						ID:   vulnerability.AppSec,
+						Name: "Application Security Advisories",
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2023-2454", "app::postgresql", "postgresql"},
+					value: types.Advisory{
+						VulnerableVersions: []string{">=11.0, <11.20", ">=15.0, <15.3"},
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2023-2455", "app::postgresql", "postgresql"},
+					value: types.Advisory{
+						VulnerableVersions: []string{">=15.0, <15.3"},
+					},
+				},
+				{
+					key: []string{"vulnerability-detail", "CVE-2023-2454", string(vulnerability.AppSec)},
+					value: types.VulnerabilityDetail{
+						Title:      "CREATE SCHEMA ... schema_element defeats protective search_path changes",
+						References: []string{"https://www.postgresql.org/support/security/CVE-2023-2454/"},
+					},
+				},
+				{
+					key:   []string{"vulnerability-id", "CVE-2023-2455"},
+					value: map[string]interface{}{},
+				},
+			},
+		},
+		{
+			name:    "sad path",
+			dir:     filepath.Join("testdata", "sad"),
+			wantErr: "YAML decode error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			err := db.Init(tempDir)
+			require.NoError(t, err)
+			defer db.Close()
+
+			vs := appsec.NewVulnSrc()
+			err = vs.Update(tt.dir)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, db.Close())
+
+			for _, w := range tt.want {
+				dbtest.JSONEq(t, db.Path(tempDir), w.key, w.value, w.key)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package archlinux
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"path/filepath"
@@ -12,12 +13,18 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/utils"
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 )
 
 const (
 	archLinuxDir = "arch-linux"
 	platformName = "archlinux"
+
+	statusFixed       = "Fixed"
+	statusVulnerable  = "Vulnerable"
+	statusTesting     = "Testing" // The fixed version is still in the testing repository
+	statusNotAffected = "Not affected"
 )
 
 var (
@@ -28,20 +35,43 @@ var (
 	}
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", archLinuxDir)
 
@@ -86,9 +116,14 @@ func (vs VulnSrc) commit(tx *bolt.Tx, avgs []ArchVulnGroup) error {
 	for _, avg := range avgs {
 		for _, cveId := range avg.Issues {
 			advisory := types.Advisory{
+				VendorIDs:       ustrings.Unique(avg.Advisories),
+				Status:          convertStatus(avg.Status),
 				FixedVersion:    avg.Fixed,
 				AffectedVersion: avg.Affected,
 			}
+			if advisory.Status == types.StatusFixed && avg.Fixed != "" {
+				advisory.PatchedVersions = []string{avg.Fixed}
+			}
 
 			for _, pkg := range avg.Packages {
 				if err := vs.dbc.PutAdvisoryDetail(tx, cveId, pkg, []string{platformName}, advisory); err != nil {
@@ -121,6 +156,18 @@ func (vs VulnSrc) Get(pkgName string) ([]types.Advisory, error) {
 	return advisories, nil
 }
 
+func convertStatus(status string) types.Status {
+	switch status {
+	case statusFixed:
+		return types.StatusFixed
+	case statusVulnerable, statusTesting:
+		return types.StatusAffected
+	case statusNotAffected:
+		return types.StatusNotAffected
+	}
+	return types.StatusUnknown
+}
+
 func convertSeverity(sev string) types.Severity {
 	severity, _ := types.NewSeverity(strings.ToUpper(sev))
 	return severity
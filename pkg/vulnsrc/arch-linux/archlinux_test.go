@@ -39,22 +39,44 @@ func TestVulnSrc_Update(t *testing.T) {
 				{
 					key: []string{"advisory-detail", "CVE-2019-11479", "archlinux", "linux-lts"},
 					value: types.Advisory{
+						VendorIDs:       []string{"ASA-201906-14"},
+						Status:          types.StatusFixed,
 						FixedVersion:    "4.19.52-1",
 						AffectedVersion: "4.19.51-1",
+						PatchedVersions: []string{"4.19.52-1"},
 					},
 				},
 				{
 					key: []string{"advisory-detail", "CVE-2019-11478", "archlinux", "linux-lts"},
 					value: types.Advisory{
+						VendorIDs:       []string{"ASA-201906-14"},
+						Status:          types.StatusFixed,
 						FixedVersion:    "4.19.52-1",
 						AffectedVersion: "4.19.51-1",
+						PatchedVersions: []string{"4.19.52-1"},
 					},
 				},
 				{
 					key: []string{"advisory-detail", "CVE-2019-11477", "archlinux", "linux-lts"},
 					value: types.Advisory{
+						VendorIDs:       []string{"ASA-201906-14"},
+						Status:          types.StatusFixed,
 						FixedVersion:    "4.19.52-1",
 						AffectedVersion: "4.19.51-1",
+						PatchedVersions: []string{"4.19.52-1"},
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2021-29338", "archlinux", "openjpeg2"},
+					value: types.Advisory{
+						Status:          types.StatusAffected,
+						AffectedVersion: "2.4.0-1",
+					},
+				},
+				{
+					key: []string{"vulnerability-detail", "CVE-2021-29338", string(vulnerability.ArchLinux)},
+					value: types.VulnerabilityDetail{
+						Severity: types.SeverityMedium,
 					},
 				},
 			},
@@ -31,6 +31,10 @@ func Name(ecosystem, dataSource string) string {
 		prefix = vulnerability.Conan
 	case "cargo":
 		prefix = vulnerability.Cargo
+	case "openssl":
+		prefix = vulnerability.OpenSSLVersion
+	case "app":
+		prefix = vulnerability.App
 	default:
 		return ""
 	}
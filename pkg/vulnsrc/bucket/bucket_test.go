@@ -33,6 +33,12 @@ func TestBucketName(t *testing.T) {
 			dataSource: "GitLab Advisory Database",
 			want:       "maven::GitLab Advisory Database",
 		},
+		{
+			name:       "happy path app",
+			ecosystem:  "app",
+			dataSource: "postgresql",
+			want:       "app::postgresql",
+		},
 		{
 			name:       "sad path unknown",
 			ecosystem:  "unknown",
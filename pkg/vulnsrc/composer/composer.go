@@ -1,6 +1,7 @@
 package composer
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/utils"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/bucket"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 )
@@ -44,20 +46,43 @@ type Advisory struct {
 	Branches        map[string]Branch `json:",omitempty"`
 }
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) (err error) {
 	repoPath := filepath.Join(dir, composerDir)
 	if err := vs.update(repoPath); err != nil {
@@ -90,6 +115,11 @@ func (vs VulnSrc) walk(tx *bolt.Tx, root string) error {
 		if info.IsDir() || !strings.HasPrefix(info.Name(), "CVE-") {
 			return nil
 		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if err = utils.SafePath(root, path); err != nil {
+				return err
+			}
+		}
 		buf, err := os.ReadFile(path)
 		if err != nil {
 			return xerrors.Errorf("failed to read a file: %w", err)
@@ -110,7 +140,8 @@ func (vs VulnSrc) walk(tx *bolt.Tx, root string) error {
 
 		var vulnerableVersions []string
 		for _, branch := range advisory.Branches {
-			vulnerableVersions = append(vulnerableVersions, strings.Join(branch.Versions, ", "))
+			constraint := vulnerability.NormalizeConstraint(vulnerability.Composer, strings.Join(branch.Versions, ", "))
+			vulnerableVersions = append(vulnerableVersions, constraint)
 		}
 
 		a := types.Advisory{
@@ -1,12 +1,14 @@
 package debian
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	version "github.com/knqyf263/go-deb-version"
 	bolt "go.etcd.io/bbolt"
@@ -15,7 +17,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/utils"
-	"github.com/aquasecurity/trivy-db/pkg/utils/strings"
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 )
 
@@ -58,6 +60,13 @@ func WithCustomPut(put db.CustomPut) Option {
 	}
 }
 
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	put db.CustomPut
 	dbc db.Operation
@@ -82,6 +91,10 @@ type VulnSrc struct {
 	// Hold not-affected versions
 	// e.g. {"buster", "linux", "CVE-2021-3739"} => {}
 	notAffected map[bucket]struct{}
+
+	// Hold the highest severity among the packages in sid per CVE-ID
+	// e.g. "CVE-2015-2328" => SeverityLow
+	severities map[string]types.Severity
 }
 
 func NewVulnSrc(opts ...Option) VulnSrc {
@@ -93,6 +106,7 @@ func NewVulnSrc(opts ...Option) VulnSrc {
 		sidFixedVersions: map[bucket]string{},
 		bktAdvisories:    map[bucket]Advisory{},
 		notAffected:      map[bucket]struct{}{},
+		severities:       map[string]types.Severity{},
 	}
 
 	for _, opt := range opts {
@@ -106,6 +120,14 @@ func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	if err := vs.parse(dir); err != nil {
 		return xerrors.Errorf("parse error: %w", err)
@@ -192,7 +214,7 @@ func (vs VulnSrc) parseCVE(dir string) error {
 			}
 
 			// Skip not-affected, removed or undetermined advisories
-			if strings.InSlice(ann.Kind, skipStatuses) {
+			if ustrings.InSlice(ann.Kind, skipStatuses) {
 				vs.notAffected[bkt] = struct{}{}
 				continue
 			}
@@ -206,6 +228,9 @@ func (vs VulnSrc) parseCVE(dir string) error {
 				if ann.Severity != "" {
 					severities[ann.Package] = ann.Severity
 					sidBkt.severity = ann.Severity
+					if sev := severityFromUrgency(ann.Severity); sev > vs.severities[cveID] {
+						vs.severities[cveID] = sev
+					}
 				}
 
 				vs.sidFixedVersions[sidBkt] = ann.Version // it may be empty for unfixed vulnerabilities
@@ -282,7 +307,7 @@ func (vs VulnSrc) parseAdvisory(dir string) error {
 				}
 
 				// Skip not-affected, removed or undetermined advisories
-				if strings.InSlice(ann.Kind, skipStatuses) {
+				if ustrings.InSlice(ann.Kind, skipStatuses) {
 					vs.notAffected[bkt] = struct{}{}
 					continue
 				}
@@ -405,6 +430,17 @@ func (vs VulnSrc) commit(tx *bolt.Tx) error {
 			return xerrors.Errorf("put advisory error: %w", err)
 		}
 	}
+
+	// The urgency is stored as the Debian severity of the vulnerability, i.e. VendorSeverity["debian"].
+	for cveID, severity := range vs.severities {
+		detail := types.VulnerabilityDetail{
+			ID:       cveID,
+			Severity: severity,
+		}
+		if err := vs.dbc.PutVulnerabilityDetail(tx, cveID, source.ID, detail); err != nil {
+			return xerrors.Errorf("failed to save Debian vulnerability detail: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -437,16 +473,24 @@ func defaultPut(dbc db.Operation, tx *bolt.Tx, advisory interface{}) error {
 	}
 
 	detail := types.Advisory{
-		VendorIDs:    adv.VendorIDs,
-		State:        adv.State,
-		Severity:     severityFromUrgency(adv.Severity),
-		FixedVersion: adv.FixedVersion,
+		VendorIDs:     adv.VendorIDs,
+		State:         adv.State,
+		Severity:      severityFromUrgency(adv.Severity),
+		DebianUrgency: strings.TrimRight(adv.Severity, "*"),
+		FixedVersion:  adv.FixedVersion,
 	}
 
 	if err := dbc.PutAdvisoryDetail(tx, adv.VulnerabilityID, adv.PkgName, []string{adv.Platform}, detail); err != nil {
 		return xerrors.Errorf("failed to save Debian advisory: %w", err)
 	}
 
+	// A DSA/DLA may fix several packages, e.g. a library and the programs bundling it
+	for _, vendorID := range adv.VendorIDs {
+		if err := dbc.PutAdvisoryGroup(tx, vendorID, adv.Platform, adv.PkgName, adv.VulnerabilityID); err != nil {
+			return xerrors.Errorf("failed to save the Debian advisory group: %w", err)
+		}
+	}
+
 	// for optimization
 	if err := dbc.PutVulnerabilityID(tx, adv.VulnerabilityID); err != nil {
 		return xerrors.Errorf("failed to save the vulnerability ID: %w", err)
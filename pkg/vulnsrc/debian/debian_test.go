@@ -62,10 +62,43 @@ func TestVulnSrc_Update(t *testing.T) {
 				{
 					key: []string{"advisory-detail", "CVE-2021-29629", "debian 10", "dacs"},
 					value: types.Advisory{
-						State:    "ignored",
+						State:         "ignored",
+						Severity:      types.SeverityLow,
+						DebianUrgency: "unimportant",
+					},
+				},
+				{
+					key: []string{"vulnerability-detail", "CVE-2021-29629", "debian"},
+					value: types.VulnerabilityDetail{
+						ID:       "CVE-2021-29629",
 						Severity: types.SeverityLow,
 					},
 				},
+				// Ref. https://security-tracker.debian.org/tracker/CVE-2015-2328
+				{
+					key: []string{"advisory-detail", "CVE-2015-2328", "debian 10", "mongodb"},
+					value: types.Advisory{
+						State:         "ignored",
+						Severity:      types.SeverityLow,
+						DebianUrgency: "unimportant",
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2015-2328", "debian 10", "pcre3"},
+					value: types.Advisory{
+						State:         "no-dsa",
+						Severity:      types.SeverityMedium,
+						DebianUrgency: "medium",
+					},
+				},
+				{
+					// The highest urgency among the packages
+					key: []string{"vulnerability-detail", "CVE-2015-2328", "debian"},
+					value: types.VulnerabilityDetail{
+						ID:       "CVE-2015-2328",
+						Severity: types.SeverityMedium,
+					},
+				},
 				{
 					key: []string{"advisory-detail", "DSA-3714-1", "debian 8", "akonadi"},
 					value: types.Advisory{
@@ -190,3 +223,36 @@ func TestVulnSrc_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestVulnSrc_UpdateAdvisoryGroup(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	vs := debian.NewVulnSrc()
+	require.NoError(t, vs.Update(filepath.Join("testdata", "happy")))
+
+	// DSA-5122-1 fixes both gzip and xz-utils
+	got, err := db.Config{}.GetAdvisoryGroup("DSA-5122-1")
+	require.NoError(t, err)
+	want := []db.GroupedAdvisory{
+		{
+			Advisory: types.Advisory{
+				VulnerabilityID: "CVE-2022-1271",
+				VendorIDs:       []string{"DSA-5122-1"},
+				FixedVersion:    "1.10-4+deb11u1",
+			},
+			Namespace: "debian 11",
+			PkgName:   "gzip",
+		},
+		{
+			Advisory: types.Advisory{
+				VulnerabilityID: "CVE-2022-1271",
+				VendorIDs:       []string{"DSA-5122-1"},
+				FixedVersion:    "5.2.5-2.1~deb11u1",
+			},
+			Namespace: "debian 11",
+			PkgName:   "xz-utils",
+		},
+	}
+	assert.Equal(t, want, got)
+}
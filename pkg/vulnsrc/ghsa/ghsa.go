@@ -1,6 +1,7 @@
 package ghsa
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -33,20 +34,43 @@ var (
 	platformFormat = "GitHub Security Advisory %s"
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return sourceID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", ghsaDir)
 
@@ -115,7 +139,7 @@ func (vs VulnSrc) commit(tx *bolt.Tx, ecosystem types.Ecosystem, entries []Entry
 			if va.FirstPatchedVersion.Identifier != "" {
 				pvs = append(pvs, va.FirstPatchedVersion.Identifier)
 			}
-			avs = append(avs, va.VulnerableVersionRange)
+			avs = append(avs, vulnerability.NormalizeConstraint(ecosystem, va.VulnerableVersionRange))
 		}
 
 		vulnID := entry.Advisory.GhsaId
@@ -137,6 +161,11 @@ func (vs VulnSrc) commit(tx *bolt.Tx, ecosystem types.Ecosystem, entries []Entry
 			return xerrors.Errorf("failed to save GHSA: %w", err)
 		}
 
+		// A GHSA may cover several packages, e.g. a library and its forks
+		if err = vs.dbc.PutAdvisoryGroup(tx, entry.Advisory.GhsaId, bucketName, pkgName, vulnID); err != nil {
+			return xerrors.Errorf("failed to save the GHSA advisory group: %w", err)
+		}
+
 		var references []string
 		for _, ref := range entry.Advisory.References {
 			references = append(references, ref.Url)
@@ -158,6 +187,13 @@ func (vs VulnSrc) commit(tx *bolt.Tx, ecosystem types.Ecosystem, entries []Entry
 		if err = vs.dbc.PutVulnerabilityID(tx, vulnID); err != nil {
 			return xerrors.Errorf("failed to save the vulnerability ID: %w", err)
 		}
+
+		// e.g. GHSA-r4x3-g983-9g48 => CVE-2020-7598
+		for _, identifier := range entry.Advisory.Identifiers {
+			if err = vs.dbc.PutVulnerabilityAlias(tx, entry.Advisory.GhsaId, identifier.Value); err != nil {
+				return xerrors.Errorf("failed to save the GHSA alias: %w", err)
+			}
+		}
 	}
 
 	return nil
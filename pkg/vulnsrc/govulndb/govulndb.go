@@ -1,6 +1,7 @@
 package govulndb
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -31,20 +32,43 @@ var (
 	bucketName = bucket.Name(string(vulnerability.Go), source.Name)
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", govulndbDir)
 
@@ -140,9 +164,17 @@ func (vs VulnSrc) commit(tx *bolt.Tx, item Entry) error {
 		references = append(references, ref.URL)
 	}
 
+	// Symbols are qualified by the import path of the package
+	// e.g. MapClaims.VerifyAudience => github.com/dgrijalva/jwt-go/v4.MapClaims.VerifyAudience
+	var symbols []string
+	for _, symbol := range affected.EcosystemSpecific.Symbols {
+		symbols = append(symbols, affected.Package.Name+"."+symbol)
+	}
+
 	a := types.Advisory{
 		PatchedVersions:    patchedVersions,
 		VulnerableVersions: vulnerableVersions,
+		AffectedSymbols:    symbols,
 	}
 
 	// A module name must be filled.
@@ -168,6 +200,13 @@ func (vs VulnSrc) commit(tx *bolt.Tx, item Entry) error {
 		}
 	}
 
+	// e.g. GO-2021-0064 => CVE-2020-8565
+	for _, alias := range item.Aliases {
+		if err := vs.dbc.PutVulnerabilityAlias(tx, item.ID, alias); err != nil {
+			return xerrors.Errorf("failed to save the vulnerability alias (%s): %w", alias, err)
+		}
+	}
+
 	return nil
 }
 
@@ -41,6 +41,7 @@ func TestVulnSrc_Update(t *testing.T) {
 					value: types.Advisory{
 						PatchedVersions:    []string{"0.13.0"},
 						VulnerableVersions: []string{">=0.0.0-20151001171628-53dd39833a08, <0.13.0"},
+						AffectedSymbols:    []string{"github.com/apache/thrift/lib/go/thrift.TSimpleJSONProtocol.safePeekContains"},
 					},
 				},
 				{
@@ -59,6 +60,7 @@ func TestVulnSrc_Update(t *testing.T) {
 					value: types.Advisory{
 						PatchedVersions:    []string{"4.0.0-preview1"},
 						VulnerableVersions: []string{">=0, <4.0.0-preview1"},
+						AffectedSymbols:    []string{"github.com/dgrijalva/jwt-go/v4.MapClaims.VerifyAudience"},
 					},
 				},
 				{
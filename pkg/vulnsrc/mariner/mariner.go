@@ -1,6 +1,7 @@
 package mariner
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
+	tlog "github.com/aquasecurity/trivy-db/pkg/log"
 	"github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/mariner/oval"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
@@ -26,29 +28,59 @@ var (
 		URL:  "https://github.com/microsoft/CBL-MarinerVulnerabilityData",
 	}
 
-	ErrNotSupported = xerrors.New("format not supported")
+	ErrNotSupported = db.NewError(db.ErrUnsupportedFormat, xerrors.New("format not supported"))
+
+	// ErrUnsupportedOperator is for EVR operations other than supportedOperators. Such tests are skipped.
+	ErrUnsupportedOperator = xerrors.Errorf("operator: %w", ErrNotSupported)
 )
 
 type resolvedTest struct {
 	Name     string
 	Version  string
 	Operator operator
+
+	// AllVersions is true for release-only tests, which check the package is installed on the release
+	// without comparing its version
+	AllVersions bool
+}
+
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
 }
 
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", cblDir)
 	versions, err := os.ReadDir(rootDir)
@@ -97,10 +129,11 @@ func resolveDefinitions(defs []oval.Definition, tests map[string]resolvedTest) [
 			continue
 		}
 		entry := Entry{
-			PkgName:  test.Name,
-			Version:  test.Version,
-			Operator: test.Operator,
-			Metadata: def.Metadata,
+			PkgName:     test.Name,
+			Version:     test.Version,
+			Operator:    test.Operator,
+			AllVersions: test.AllVersions,
+			Metadata:    def.Metadata,
 		}
 
 		entries = append(entries, entry)
@@ -113,6 +146,13 @@ const (
 	lt  operator = "less than"
 )
 
+// supportedOperators are the EVR operations we can convert into advisories.
+// Others such as "greater than or equal" and "pattern match" are skipped.
+var supportedOperators = map[operator]struct{}{
+	lte: {},
+	lt:  {},
+}
+
 func resolveTests(dir string) (map[string]resolvedTest, error) {
 	objects, err := oval.ParseObjects(dir)
 	if err != nil {
@@ -129,6 +169,10 @@ func resolveTests(dir string) (map[string]resolvedTest, error) {
 		return nil, xerrors.Errorf("failed to parse tests: %w", err)
 	}
 
+	// Unsupported operators are usually repeated for many tests
+	skipped := tlog.NewDedup()
+	defer skipped.Flush()
+
 	tests := map[string]resolvedTest{}
 	for _, test := range tt.RpminfoTests {
 		// test directive has should be "at least one"
@@ -137,7 +181,10 @@ func resolveTests(dir string) (map[string]resolvedTest, error) {
 		}
 
 		t, err := followTestRefs(test, objects, states)
-		if err != nil {
+		if xerrors.Is(err, ErrUnsupportedOperator) {
+			skipped.Printf("    Skipping tests: %s", err)
+			continue
+		} else if err != nil {
 			return nil, xerrors.Errorf("unable to follow test refs: %w", err)
 		}
 		tests[test.ID] = t
@@ -169,12 +216,20 @@ func followTestRefs(test oval.RpmInfoTest, objects map[string]string, states map
 			test.State.StateRef, test.ID)
 	}
 
+	// The state has no EVR, meaning all versions on the release are affected
+	if state.Evr == (oval.Evr{}) {
+		return resolvedTest{
+			Name:        pkgName,
+			AllVersions: true,
+		}, nil
+	}
+
 	if state.Evr.Datatype != "evr_string" {
 		return resolvedTest{}, xerrors.Errorf("state data type (%s): %w", state.Evr.Datatype, ErrNotSupported)
 	}
 
-	if state.Evr.Operation != string(lte) && state.Evr.Operation != string(lt) {
-		return resolvedTest{}, xerrors.Errorf("state operation (%s): %w", state.Evr.Operation, ErrNotSupported)
+	if _, ok := supportedOperators[operator(state.Evr.Operation)]; !ok {
+		return resolvedTest{}, xerrors.Errorf("state operation (%s): %w", state.Evr.Operation, ErrUnsupportedOperator)
 	}
 
 	return resolvedTest{
@@ -206,7 +261,9 @@ func (vs VulnSrc) commit(tx *bolt.Tx, platformName string, entries []Entry) erro
 	for _, entry := range entries {
 		cveID := entry.Metadata.Reference.RefID
 		advisory := types.Advisory{}
-		if entry.Metadata.Patchable {
+		if entry.AllVersions {
+			advisory.VulnerableVersions = []string{types.AllVersions}
+		} else if entry.Metadata.Patchable {
 			advisory.FixedVersion = entry.Version
 		}
 
@@ -25,6 +25,7 @@ func TestVulnSrc_Update(t *testing.T) {
 		name       string
 		dir        string
 		wantValues []want
+		noBuckets  [][]string
 		wantErr    string
 	}{
 		{
@@ -87,6 +88,44 @@ func TestVulnSrc_Update(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "unsupported operator",
+			dir:  filepath.Join("testdata", "unsupported-operator"),
+			wantValues: []want{
+				{
+					key: []string{"advisory-detail", "CVE-2008-3914", "CBL-Mariner 1.0", "clamav"},
+					value: types.Advisory{
+						FixedVersion: "0:0.103.2-1.cm1",
+					},
+				},
+			},
+			noBuckets: [][]string{
+				{"advisory-detail", "CVE-2022-0001"},
+				{"vulnerability-id", "CVE-2022-0001"},
+			},
+		},
+		{
+			name: "release-only test",
+			dir:  filepath.Join("testdata", "release-only"),
+			wantValues: []want{
+				{
+					key: []string{"advisory-detail", "CVE-2008-3914", "CBL-Mariner 1.0", "clamav"},
+					value: types.Advisory{
+						FixedVersion: "0:0.103.2-1.cm1",
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2022-0001", "CBL-Mariner 1.0", "kernel"},
+					value: types.Advisory{
+						VulnerableVersions: []string{types.AllVersions},
+					},
+				},
+				{
+					key:   []string{"vulnerability-id", "CVE-2022-0001"},
+					value: map[string]interface{}{},
+				},
+			},
+		},
 		{
 			name:    "sad path invalid objects",
 			dir:     filepath.Join("testdata", "sad", "invalid-objects"),
@@ -134,6 +173,9 @@ func TestVulnSrc_Update(t *testing.T) {
 			for _, w := range tt.wantValues {
 				dbtest.JSONEq(t, db.Path(tempDir), w.key, w.value, w.key)
 			}
+			for _, key := range tt.noBuckets {
+				dbtest.NoBucket(t, db.Path(tempDir), key, key)
+			}
 		})
 	}
 }
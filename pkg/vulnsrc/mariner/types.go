@@ -5,8 +5,9 @@ import "github.com/aquasecurity/trivy-db/pkg/vulnsrc/mariner/oval"
 type operator string
 
 type Entry struct {
-	PkgName  string
-	Version  string
-	Operator operator
-	Metadata oval.Metadata
+	PkgName     string
+	Version     string
+	Operator    operator
+	AllVersions bool
+	Metadata    oval.Metadata
 }
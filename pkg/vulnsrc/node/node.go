@@ -1,26 +1,39 @@
 package node
 
 import (
+	"bytes"
+	"context"
+	_ "embed"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
 	bolt "go.etcd.io/bbolt"
+	"golang.org/x/mod/semver"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/utils"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/bucket"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 )
 
 const (
 	nodeDir = "nodejs-security-wg"
+
+	// Stdin given to Update makes it read a single advisory from stdin instead of the repository
+	Stdin = "-"
 )
 
+//go:embed schema.json
+var schema []byte // JSON schema of npm advisories, requiring the fields used in commit
+
 var (
 	source = types.DataSource{
 		ID:   vulnerability.NodejsSecurityWg,
@@ -29,6 +42,9 @@ var (
 	}
 
 	bucketName = bucket.Name(string(vulnerability.Npm), source.Name)
+
+	// e.g. "<= v1.5.2-beta.1", ">=2.0.0-rc.1+build.5"
+	preReleaseRegexp = regexp.MustCompile(`(<=|>=|<|>|=|\^|~)?\s*v?(\d+\.\d+\.\d+-[0-9A-Za-z.-]+(?:\+[0-9A-Za-z.-]+)?)`)
 )
 
 type Number struct {
@@ -55,7 +71,7 @@ func (n *Number) UnmarshalJSON(b []byte) error {
 		}
 		n.Value = f
 	default: // it can be null: https://github.com/nodejs/security-wg/blob/master/vuln/npm/334.json
-		n.Value = -1
+		n.Value = types.NoCvssScore
 	}
 	return nil
 }
@@ -70,25 +86,82 @@ type RawAdvisory struct {
 	Overview           string
 	Recommendation     string
 	References         []string
+	CvssVector         string `json:"cvss_vector"`
 	CvssScoreNumber    Number `json:"cvss_score"`
 	CvssScore          float64
 }
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config, e.g. to receive progress via ProgressFn.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+		src.config = dbc
+	}
+}
+
+// WithSchemaValidation validates npm advisories against the JSON schema,
+// so that a format change upstream fails the update instead of producing empty advisories.
+func WithSchemaValidation() Option {
+	return func(src *VulnSrc) {
+		src.schema = schema
+	}
+}
+
+// WithConcurrency decodes up to n files in parallel while the advisories are written in the order of the files,
+// so that the DB is identical to the one updated sequentially. Each file being decoded is held in memory.
+func WithConcurrency(n int) Option {
+	return func(src *VulnSrc) {
+		src.concurrency = n
+	}
+}
+
 type VulnSrc struct {
-	dbc db.Operation
+	dbc         db.Operation
+	config      db.Config
+	schema      []byte
+	concurrency int
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := &VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(src)
+	}
+
+	return *src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// Namespaces returns the namespace the advisories are stored in, i.e. "npm::Node.js Ecosystem Security Working Group"
+func (vs VulnSrc) Namespaces() []string {
+	return []string{bucketName}
+}
+
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	vs.config = vs.config.WithContext(ctx)
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
+	if dir == Stdin {
+		if err := vs.updateReader(os.Stdin); err != nil {
+			return xerrors.Errorf("failed to update node vulnerabilities from stdin: %w", err)
+		}
+		return nil
+	}
+
 	repoPath := filepath.Join(dir, nodeDir)
 	if err := vs.update(repoPath); err != nil {
 		return xerrors.Errorf("failed to update node vulnerabilities: %w", err)
@@ -98,13 +171,69 @@ func (vs VulnSrc) Update(dir string) error {
 
 func (vs VulnSrc) update(repoPath string) error {
 	root := filepath.Join(repoPath, "vuln")
+	files, err := listFiles(root)
+	if err != nil {
+		return xerrors.Errorf("failed to walk node advisories: %w", err)
+	}
 
-	err := vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+	// All the files are committed in a single transaction unless BatchSize is set
+	batches := [][]string{files}
+	if n := vs.config.BatchSize; n > 0 && len(files) > n {
+		batches = nil
+		rest := files
+		for len(rest) > n {
+			batches = append(batches, rest[:n])
+			rest = rest[n:]
+		}
+		batches = append(batches, rest)
+	}
+
+	var done int
+	for i, batch := range batches {
+		last := i == len(batches)-1
+		err = vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+			if err := vs.dbc.PutDataSource(tx, bucketName, source); err != nil {
+				return xerrors.Errorf("failed to put data source: %w", err)
+			}
+			err := vs.commitFiles(tx, batch, func(j int) {
+				vs.config.Progress(string(source.ID), done+j+1, len(files))
+			})
+			if err != nil {
+				return err
+			}
+			if !last {
+				return nil
+			}
+			if err := vs.config.PostProcess(tx, string(source.ID)); err != nil {
+				return xerrors.Errorf("failed to post-process node advisories: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return xerrors.Errorf("batch update failed: %w", err)
+		}
+		done += len(batch)
+	}
+	return nil
+}
+
+// updateReader commits a single npm advisory read from the reader in one transaction
+func (vs VulnSrc) updateReader(r io.Reader) error {
+	r, err := vs.validate(r, Stdin)
+	if err != nil {
+		return err
+	}
+
+	err = vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
 		if err := vs.dbc.PutDataSource(tx, bucketName, source); err != nil {
 			return xerrors.Errorf("failed to put data source: %w", err)
 		}
-		if err := vs.walk(tx, root); err != nil {
-			return xerrors.Errorf("failed to walk node advisories: %w", err)
+		if err := vs.commit(tx, r, Stdin); err != nil {
+			return xerrors.Errorf("failed to commit the advisory: %w", err)
+		}
+		vs.config.Progress(string(source.ID), 1, 1)
+		if err := vs.config.PostProcess(tx, string(source.ID)); err != nil {
+			return xerrors.Errorf("failed to post-process node advisories: %w", err)
 		}
 		return nil
 	})
@@ -114,31 +243,179 @@ func (vs VulnSrc) update(repoPath string) error {
 	return nil
 }
 
-func (vs VulnSrc) walk(tx *bolt.Tx, root string) error {
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+// listFiles returns the JSON files under the root
+func listFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if info.IsDir() || !strings.HasSuffix(info.Name(), ".json") {
 			return nil
 		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if err = utils.SafePath(root, path); err != nil {
+				return err
+			}
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
 
-		f, err := os.Open(path)
-		if err != nil {
-			return err
+// commitFiles commits the files in order, calling committed with the index of each committed file.
+// With WithConcurrency, the files are decoded by workers and written by the goroutine holding the transaction.
+func (vs VulnSrc) commitFiles(tx *bolt.Tx, files []string, committed func(i int)) error {
+	if vs.concurrency <= 1 {
+		for i, path := range files {
+			if err := vs.commitFile(tx, path); err != nil {
+				return err
+			}
+			committed(i)
+		}
+		return nil
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	for i, result := range vs.decodeFiles(files, stop) {
+		file := <-result
+		if file.err != nil {
+			return file.err
+		}
+		for _, adv := range file.advisories {
+			if err := vs.commitAdvisory(tx, adv.advisory, adv.provenance); err != nil {
+				return err
+			}
+		}
+		committed(i)
+	}
+	return nil
+}
+
+// decodedFile is the advisories decoded from a file
+type decodedFile struct {
+	advisories []decodedAdvisory
+	err        error
+}
+
+type decodedAdvisory struct {
+	advisory   RawAdvisory
+	provenance string
+}
+
+// decodeFiles decodes the files with vs.concurrency workers. The result of each file is sent to the channel
+// at the same index, so that the caller can receive them in order. As the channels are unbuffered,
+// workers wait for the caller and no more than vs.concurrency files are held in memory.
+// The workers exit once stop is closed.
+func (vs VulnSrc) decodeFiles(files []string, stop <-chan struct{}) []chan decodedFile {
+	results := make([]chan decodedFile, len(files))
+	for i := range results {
+		results[i] = make(chan decodedFile)
+	}
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range files {
+			select {
+			case jobs <- i:
+			case <-stop:
+				return
+			}
 		}
-		defer f.Close()
+	}()
 
-		return vs.commit(tx, f)
+	for w := 0; w < vs.concurrency; w++ {
+		go func() {
+			for i := range jobs {
+				var file decodedFile
+				file.err = vs.decodeFile(files[i], func(advisory RawAdvisory, provenance string) error {
+					file.advisories = append(file.advisories, decodedAdvisory{advisory: advisory, provenance: provenance})
+					return nil
+				})
+				select {
+				case results[i] <- file:
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+	return results
+}
+
+func (vs VulnSrc) commitFile(tx *bolt.Tx, path string) error {
+	return vs.decodeFile(path, func(advisory RawAdvisory, provenance string) error {
+		return vs.commitAdvisory(tx, advisory, provenance)
 	})
 }
 
-func (vs VulnSrc) commit(tx *bolt.Tx, f *os.File) error {
-	advisory := RawAdvisory{}
-	var err error
-	if err = json.NewDecoder(f).Decode(&advisory); err != nil {
+// decodeFile passes the advisories in the file to fn one by one
+func (vs VulnSrc) decodeFile(path string, fn func(advisory RawAdvisory, provenance string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
 		return err
 	}
+	defer f.Close()
+
+	// Node core advisories have another format
+	var r io.Reader = f
+	if filepath.Base(filepath.Dir(path)) == "npm" {
+		if r, err = vs.validate(f, path); err != nil {
+			return err
+		}
+	}
+
+	return decode(r, path, fn)
+}
+
+// validate validates the npm advisory against the JSON schema if WithSchemaValidation is given.
+// It returns a reader of the same content since the given one is consumed.
+func (vs VulnSrc) validate(r io.Reader, path string) (io.Reader, error) {
+	if vs.schema == nil {
+		return r, nil
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	}
+	if err = utils.ValidateJSONSchema(bytes.NewReader(b), vs.schema); err != nil {
+		return nil, xerrors.Errorf("invalid advisory %s: %w", path, db.NewError(db.ErrParse, err))
+	}
+	return bytes.NewReader(b), nil
+}
+
+// commit stores the advisory in the reader. The reader may also hold an array of advisories,
+// which are decoded and stored one by one so that large arrays are not loaded in memory at once.
+func (vs VulnSrc) commit(tx *bolt.Tx, r io.Reader, path string) error {
+	return decode(r, path, func(advisory RawAdvisory, provenance string) error {
+		return vs.commitAdvisory(tx, advisory, provenance)
+	})
+}
+
+// decode passes the advisory in the reader, or each advisory of an array, to fn with its provenance
+func decode(r io.Reader, path string, fn func(advisory RawAdvisory, provenance string) error) error {
+	return utils.DecodeJSONStream(r, func(dec *json.Decoder, index int) error {
+		advisory := RawAdvisory{}
+		if err := dec.Decode(&advisory); err != nil {
+			return db.NewError(db.ErrParse, err)
+		}
+		provenance := path
+		if index >= 0 {
+			provenance = fmt.Sprintf("%s#%d", path, index)
+		}
+		return fn(advisory, provenance)
+	})
+}
+
+func (vs VulnSrc) commitAdvisory(tx *bolt.Tx, advisory RawAdvisory, path string) error {
+	var err error
 
 	// Node.js itself
 	if advisory.ModuleName == "" {
@@ -152,6 +429,10 @@ func (vs VulnSrc) commit(tx *bolt.Tx, f *os.File) error {
 	}
 
 	adv := convertToGenericAdvisory(advisory)
+	if vs.config.TrackProvenance {
+		adv.Provenance = path
+	}
+	adv.FetchedAt = vs.config.FetchedAt()
 	for _, vulnID := range vulnerabilityIDs {
 		// for detecting vulnerabilities
 		err = vs.dbc.PutAdvisoryDetail(tx, vulnID, advisory.ModuleName, []string{bucketName}, adv)
@@ -159,22 +440,10 @@ func (vs VulnSrc) commit(tx *bolt.Tx, f *os.File) error {
 			return xerrors.Errorf("failed to save node advisory: %w", err)
 		}
 
-		// If an advisory is 0 override with -1
-		// https://github.com/nodejs/security-wg/pull/91/files
-		if advisory.CvssScoreNumber.Value <= 0 {
-			advisory.CvssScoreNumber.Value = -1
-		}
-
-		// for displaying vulnerability detail
-		vuln := types.VulnerabilityDetail{
-			ID:          vulnID,
-			CvssScore:   advisory.CvssScoreNumber.Value,
-			References:  advisory.References,
-			Title:       advisory.Title,
-			Description: advisory.Overview,
-		}
-		if err = vs.dbc.PutVulnerabilityDetail(tx, vulnID, source.ID, vuln); err != nil {
-			return xerrors.Errorf("failed to save node vulnerability detail: %w", err)
+		if !vs.config.AdvisoriesOnly {
+			if err = vs.putVulnerabilityDetail(tx, vulnID, advisory); err != nil {
+				return err
+			}
 		}
 
 		// for optimization
@@ -186,21 +455,72 @@ func (vs VulnSrc) commit(tx *bolt.Tx, f *os.File) error {
 	return nil
 }
 
+func (vs VulnSrc) putVulnerabilityDetail(tx *bolt.Tx, vulnID string, advisory RawAdvisory) error {
+	// If an advisory is 0 override with types.NoCvssScore, which is ranked as UNKNOWN
+	// https://github.com/nodejs/security-wg/pull/91/files
+	if advisory.CvssScoreNumber.Value <= 0 {
+		advisory.CvssScoreNumber.Value = types.NoCvssScore
+	}
+
+	// for displaying vulnerability detail
+	vuln := types.VulnerabilityDetail{
+		ID:          vulnID,
+		CvssScore:   advisory.CvssScoreNumber.Value,
+		References:  advisory.References,
+		Title:       advisory.Title,
+		Description: advisory.Overview,
+	}
+
+	// Older advisories have CVSS v2 vectors, whose base score takes precedence over the numeric score.
+	// CVSS v3 vectors are not stored since the numeric score is treated as v2 in this source.
+	if advisory.CvssVector != "" {
+		if score, err := vulnerability.ParseCVSSv2(advisory.CvssVector); err == nil {
+			vuln.CvssScore = score
+			vuln.CvssVector = advisory.CvssVector
+		}
+	}
+	if vuln.CvssScore > 0 {
+		vuln.SeveritySource = string(vulnerability.NodejsSecurityWg)
+	}
+
+	if err := vs.dbc.PutVulnerabilityDetail(tx, vulnID, source.ID, vuln); err != nil {
+		return xerrors.Errorf("failed to save node vulnerability detail: %w", err)
+	}
+	return nil
+}
+
 func convertToGenericAdvisory(advisory RawAdvisory) types.Advisory {
 	var vulnerable, patched []string
 	if advisory.VulnerableVersions != "" {
 		for _, ver := range strings.Split(advisory.VulnerableVersions, "||") {
-			vulnerable = append(vulnerable, strings.TrimSpace(ver))
+			ver = vulnerability.NormalizeConstraint(vulnerability.Npm, ver)
+			vulnerable = append(vulnerable, normalizePreRelease(ver))
 		}
 	}
 	if advisory.PatchedVersions != "" {
 		for _, ver := range strings.Split(advisory.PatchedVersions, "||") {
-			patched = append(patched, strings.TrimSpace(ver))
+			patched = append(patched, normalizePreRelease(strings.TrimSpace(ver)))
 		}
 	}
 
 	return types.Advisory{
 		VulnerableVersions: vulnerable,
 		PatchedVersions:    patched,
+		FixCommits:         vulnerability.FixCommits(advisory.References),
 	}
 }
+
+// normalizePreRelease rewrites pre-release versions in the constraint into the canonical semver form.
+// e.g. "<= v1.5.2-beta.1+build.3" => "<=1.5.2-beta.1"
+// The build metadata is dropped since it doesn't affect the precedence,
+// and the pre-release version is kept so that it is ordered before the release version.
+func normalizePreRelease(constraint string) string {
+	return preReleaseRegexp.ReplaceAllStringFunc(constraint, func(s string) string {
+		m := preReleaseRegexp.FindStringSubmatch(s)
+		canonical := semver.Canonical("v" + m[2])
+		if canonical == "" {
+			return s
+		}
+		return m[1] + strings.TrimPrefix(canonical, "v")
+	})
+}
@@ -1,9 +1,13 @@
 package node
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -11,8 +15,10 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
 	"github.com/stretchr/testify/assert"
 	bolt "go.etcd.io/bbolt"
+	"golang.org/x/mod/semver"
 )
 
 func TestVulnSrc_Commit(t *testing.T) {
@@ -23,6 +29,7 @@ func TestVulnSrc_Commit(t *testing.T) {
 		putVulnerabilityDetail []db.OperationPutVulnerabilityDetailExpectation
 		putVulnerabilityID     []db.OperationPutVulnerabilityIDExpectation
 		expectedErrorMsg       string
+		expectedErr            error
 	}{
 		{
 			name:      "happy path, npm package only includes CVSS score",
@@ -37,6 +44,7 @@ func TestVulnSrc_Commit(t *testing.T) {
 						Advisory: types.Advisory{
 							VulnerableVersions: []string{"<=1.5.1"},
 							PatchedVersions:    []string{">=1.5.2"},
+							FixCommits:         []string{"https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
 						},
 					},
 				},
@@ -48,11 +56,12 @@ func TestVulnSrc_Commit(t *testing.T) {
 						VulnerabilityID: "CVE-2014-7205",
 						Source:          vulnerability.NodejsSecurityWg,
 						Vulnerability: types.VulnerabilityDetail{
-							ID:          "CVE-2014-7205",
-							CvssScore:   6.5,
-							References:  []string{"https://www.npmjs.org/package/bassmaster", "https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
-							Title:       "Arbitrary JavaScript Execution",
-							Description: "A vulnerability exists in bassmaster <= 1.5.1 that allows for an attacker to provide arbitrary JavaScript that is then executed server side via eval.",
+							ID:             "CVE-2014-7205",
+							CvssScore:      6.5,
+							References:     []string{"https://www.npmjs.org/package/bassmaster", "https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
+							Title:          "Arbitrary JavaScript Execution",
+							Description:    "A vulnerability exists in bassmaster <= 1.5.1 that allows for an attacker to provide arbitrary JavaScript that is then executed server side via eval.",
+							SeveritySource: "nodejs-security-wg",
 						},
 					},
 				},
@@ -79,6 +88,7 @@ func TestVulnSrc_Commit(t *testing.T) {
 						Advisory: types.Advisory{
 							VulnerableVersions: []string{"<=1.5.1"},
 							PatchedVersions:    []string{">=1.5.2"},
+							FixCommits:         []string{"https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
 						},
 					},
 				},
@@ -90,11 +100,12 @@ func TestVulnSrc_Commit(t *testing.T) {
 						VulnerabilityID: "CVE-2014-7205",
 						Source:          vulnerability.NodejsSecurityWg,
 						Vulnerability: types.VulnerabilityDetail{
-							ID:          "CVE-2014-7205",
-							CvssScore:   6.5,
-							References:  []string{"https://www.npmjs.org/package/bassmaster", "https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
-							Title:       "Arbitrary JavaScript Execution",
-							Description: "A vulnerability exists in bassmaster <= 1.5.1 that allows for an attacker to provide arbitrary JavaScript that is then executed server side via eval.",
+							ID:             "CVE-2014-7205",
+							CvssScore:      6.5,
+							References:     []string{"https://www.npmjs.org/package/bassmaster", "https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
+							Title:          "Arbitrary JavaScript Execution",
+							Description:    "A vulnerability exists in bassmaster <= 1.5.1 that allows for an attacker to provide arbitrary JavaScript that is then executed server side via eval.",
+							SeveritySource: "nodejs-security-wg",
 						},
 					},
 				},
@@ -138,7 +149,7 @@ func TestVulnSrc_Commit(t *testing.T) {
 						Source:          vulnerability.NodejsSecurityWg,
 						Vulnerability: types.VulnerabilityDetail{
 							ID:          "NSWG-ECO-0",
-							CvssScore:   -1,
+							CvssScore:   types.NoCvssScore,
 							Description: "The c-ares function ares_parse_naptr_reply(), which is used for parsing NAPTR\nresponses, could be triggered to read memory outside of the given input buffer\nif the passed in DNS response packet was crafted in a particular way.\n\n",
 						},
 					},
@@ -178,7 +189,7 @@ func TestVulnSrc_Commit(t *testing.T) {
 						Source:          vulnerability.NodejsSecurityWg,
 						Vulnerability: types.VulnerabilityDetail{
 							ID:          "NSWG-ECO-334",
-							CvssScore:   -1,
+							CvssScore:   types.NoCvssScore,
 							Description: "The hubl-server module is a wrapper for the HubL Development Server.\n\nDuring installation hubl-server downloads a set of dependencies from api.hubapi.com. It appears in the code that these files are downloaded over HTTPS however the api.hubapi.com endpoint redirects to a HTTP url. Because of this behavior an attacker with the ability to man-in-the-middle a developer or system performing a package installation could compromise the integrity of the installation.",
 							Title:       "Downloads resources over HTTP",
 						},
@@ -194,10 +205,98 @@ func TestVulnSrc_Commit(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:      "happy path, npm package includes pre-release versions",
+			inputFile: "npm_prerelease.json",
+			putAdvisoryDetail: []db.OperationPutAdvisoryDetailExpectation{
+				{
+					Args: db.OperationPutAdvisoryDetailArgs{
+						TxAnything:      true,
+						NestedBktNames:  []string{"npm::Node.js Ecosystem Security Working Group"},
+						PkgName:         "prerelease-package",
+						VulnerabilityID: "CVE-2019-1000001",
+						Advisory: types.Advisory{
+							VulnerableVersions: []string{"<2.0.0-beta.2", ">=1.0.0-alpha <1.0.0"},
+							PatchedVersions:    []string{">=2.0.0-beta.2"},
+						},
+					},
+				},
+			},
+			putVulnerabilityDetail: []db.OperationPutVulnerabilityDetailExpectation{
+				{
+					Args: db.OperationPutVulnerabilityDetailArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2019-1000001",
+						Source:          vulnerability.NodejsSecurityWg,
+						Vulnerability: types.VulnerabilityDetail{
+							ID:             "CVE-2019-1000001",
+							CvssScore:      5.6,
+							References:     []string{},
+							Title:          "Prototype Pollution",
+							Description:    "Versions of prerelease-package before 2.0.0-beta.2 are vulnerable to prototype pollution.",
+							SeveritySource: "nodejs-security-wg",
+						},
+					},
+				},
+			},
+			putVulnerabilityID: []db.OperationPutVulnerabilityIDExpectation{
+				{
+					Args: db.OperationPutVulnerabilityIDArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2019-1000001",
+					},
+				},
+			},
+		},
+		{
+			name:      "happy path, npm package includes CVSS v2 vector",
+			inputFile: "npm_cvssv2vector.json",
+			putAdvisoryDetail: []db.OperationPutAdvisoryDetailExpectation{
+				{
+					Args: db.OperationPutAdvisoryDetailArgs{
+						TxAnything:      true,
+						NestedBktNames:  []string{"npm::Node.js Ecosystem Security Working Group"},
+						PkgName:         "uglify-js",
+						VulnerabilityID: "CVE-2015-8858",
+						Advisory: types.Advisory{
+							VulnerableVersions: []string{"<2.6.0"},
+							PatchedVersions:    []string{">=2.6.0"},
+						},
+					},
+				},
+			},
+			putVulnerabilityDetail: []db.OperationPutVulnerabilityDetailExpectation{
+				{
+					Args: db.OperationPutVulnerabilityDetailArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2015-8858",
+						Source:          vulnerability.NodejsSecurityWg,
+						Vulnerability: types.VulnerabilityDetail{
+							ID:             "CVE-2015-8858",
+							CvssScore:      5.0,
+							CvssVector:     "AV:N/AC:L/Au:N/C:N/I:N/A:P",
+							References:     []string{"https://github.com/mishoo/UglifyJS2/pull/986"},
+							Title:          "Regular Expression Denial of Service",
+							Description:    "The parse() function in uglify-js is vulnerable to regular expression denial of service.",
+							SeveritySource: "nodejs-security-wg",
+						},
+					},
+				},
+			},
+			putVulnerabilityID: []db.OperationPutVulnerabilityIDExpectation{
+				{
+					Args: db.OperationPutVulnerabilityIDArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2015-8858",
+					},
+				},
+			},
+		},
 		{
 			name:             "sad path, invalid json",
 			inputFile:        "invalidvuln.json",
 			expectedErrorMsg: "invalid character",
+			expectedErr:      db.ErrParse,
 		},
 	}
 
@@ -214,11 +313,14 @@ func TestVulnSrc_Commit(t *testing.T) {
 			filePath := fmt.Sprintf("testdata/%s", tc.inputFile)
 			f, err := os.Open(filePath)
 			require.NoError(t, err, tc.name)
-			err = ac.commit(tx, f)
+			err = ac.commit(tx, f, f.Name())
 
 			switch {
 			case tc.expectedErrorMsg != "":
 				assert.Contains(t, err.Error(), tc.expectedErrorMsg, tc.name)
+				if tc.expectedErr != nil {
+					assert.ErrorIs(t, err, tc.expectedErr, tc.name)
+				}
 			default:
 				assert.NoError(t, err, tc.name)
 			}
@@ -226,3 +328,721 @@ func TestVulnSrc_Commit(t *testing.T) {
 		})
 	}
 }
+
+func TestVulnSrc_CommitInMemory(t *testing.T) {
+	mdb := db.NewMemoryDB(db.Config{})
+	vs := NewVulnSrc()
+	vs.dbc = mdb
+
+	err := mdb.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := mdb.PutDataSource(tx, bucketName, source); err != nil {
+			return err
+		}
+		f, err := os.Open("testdata/npm_cvssnumberonly.json")
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return vs.commit(tx, f, f.Name())
+	})
+	require.NoError(t, err)
+
+	// Copy advisories into the namespace in the same way as vulndb
+	err = mdb.ForEachVulnerabilityID(func(tx *bolt.Tx, vulnID string) error {
+		return mdb.SaveAdvisoryDetails(tx, vulnID)
+	})
+	require.NoError(t, err)
+
+	got, err := mdb.GetAdvisories("npm::", "bassmaster")
+	require.NoError(t, err)
+	assert.Equal(t, []types.Advisory{
+		{
+			VulnerabilityID:    "CVE-2014-7205",
+			VulnerableVersions: []string{"<=1.5.1"},
+			PatchedVersions:    []string{">=1.5.2"},
+			FixCommits:         []string{"https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
+			DataSource:         &source,
+		},
+	}, got)
+
+	details, err := mdb.GetVulnerabilityDetail("CVE-2014-7205")
+	require.NoError(t, err)
+	assert.Equal(t, 6.5, details[vulnerability.NodejsSecurityWg].CvssScore)
+	assert.Equal(t, "Arbitrary JavaScript Execution", details[vulnerability.NodejsSecurityWg].Title)
+}
+
+func TestVulnSrc_CommitWithTextIndex(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	vs := NewVulnSrc(WithDBConfig(db.Config{BuildTextIndex: true}))
+	err := vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		f, err := os.Open("testdata/npm_cvssnumberonly.json")
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return vs.commit(tx, f, f.Name())
+	})
+	require.NoError(t, err)
+
+	got, err := vs.dbc.SearchText("eval")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CVE-2014-7205"}, got)
+}
+
+func TestVulnSrc_UpdateProgress(t *testing.T) {
+	type progress struct {
+		source      string
+		done, total int
+	}
+	tests := []struct {
+		name    string
+		files   map[string]string // file name in the vuln dir => fixture in testdata
+		want    []progress
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			files: map[string]string{
+				"1.json":   "npm_cvssnumberonly.json",
+				"334.json": "npm_nullcvssscore.json",
+				"493.json": "493.json",
+			},
+			want: []progress{
+				{source: "nodejs-security-wg", done: 1, total: 3},
+				{source: "nodejs-security-wg", done: 2, total: 3},
+				{source: "nodejs-security-wg", done: 3, total: 3},
+			},
+		},
+		{
+			name:  "empty directory",
+			files: map[string]string{},
+		},
+		{
+			name: "sad path, progress stops at the broken file",
+			files: map[string]string{
+				"1.json": "npm_cvssnumberonly.json",
+				"2.json": "invalidvuln.json",
+				"3.json": "493.json",
+			},
+			want: []progress{
+				{source: "nodejs-security-wg", done: 1, total: 3},
+			},
+			wantErr: "invalid character",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln")
+			require.NoError(t, os.MkdirAll(vulnDir, 0700))
+			for name, fixture := range tt.files {
+				b, err := os.ReadFile(filepath.Join("testdata", fixture))
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(filepath.Join(vulnDir, name), b, 0600))
+			}
+
+			require.NoError(t, db.Init(t.TempDir()))
+			defer db.Close()
+
+			var got []progress
+			vs := NewVulnSrc(WithDBConfig(db.Config{
+				ProgressFn: func(source string, done, total int) {
+					got = append(got, progress{source: source, done: done, total: total})
+				},
+			}))
+			err := vs.Update(dir)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				// bolt's Batch retries a failed function, so the same progress can be reported again
+				require.NotEmpty(t, got)
+				for _, p := range got {
+					assert.Contains(t, tt.want, p)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVulnSrc_ListNamespaces(t *testing.T) {
+	dir := t.TempDir()
+	vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	b, err := os.ReadFile("testdata/493.json")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(vulnDir, "493.json"), b, 0600))
+
+	require.NoError(t, db.Init(t.TempDir()))
+	defer db.Close()
+
+	vs := NewVulnSrc()
+	require.NoError(t, vs.Update(dir))
+
+	got, err := db.Config{}.ListNamespaces()
+	require.NoError(t, err)
+	assert.Contains(t, got, "npm::Node.js Ecosystem Security Working Group")
+}
+
+func TestVulnSrc_Namespaces(t *testing.T) {
+	vs := NewVulnSrc()
+	assert.Equal(t, types.SourceID("nodejs-security-wg"), vs.Name())
+	assert.Equal(t, []string{"npm::Node.js Ecosystem Security Working Group"}, vs.Namespaces())
+}
+
+func TestNormalizePreRelease(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{constraint: "<=1.5.1", want: "<=1.5.1"},
+		{constraint: "<= v1.5.2-beta.1", want: "<=1.5.2-beta.1"},
+		{constraint: ">=2.0.0-rc.1+build.5", want: ">=2.0.0-rc.1"},
+		{constraint: ">=1.0.0-alpha <1.0.0-beta", want: ">=1.0.0-alpha <1.0.0-beta"},
+		{constraint: "1.5.2-beta..1", want: "1.5.2-beta..1"}, // invalid
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizePreRelease(tt.constraint))
+		})
+	}
+
+	// The pre-release bound must be ordered before the release version
+	got := normalizePreRelease("< v1.5.2-beta.1")
+	assert.Equal(t, -1, semver.Compare("v"+strings.TrimPrefix(got, "<"), "v1.5.2"))
+}
+
+func TestVulnSrc_UpdateWithSchemaValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string // file path in the vuln dir => fixture in testdata
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			files: map[string]string{
+				"npm/1.json":   "npm_cvssnumberonly.json",
+				"npm/334.json": "npm_nullcvssscore.json",
+				"core/1.json":  "core_cvssnumberandstring.json",
+			},
+		},
+		{
+			name: "sad path, structurally wrong advisory",
+			files: map[string]string{
+				"npm/2.json": "npm_invalidschema.json",
+			},
+			wantErr: "$.cves: expected array, got string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln")
+			for name, fixture := range tt.files {
+				b, err := os.ReadFile(filepath.Join("testdata", fixture))
+				require.NoError(t, err)
+				path := filepath.Join(vulnDir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+				require.NoError(t, os.WriteFile(path, b, 0600))
+			}
+
+			require.NoError(t, db.Init(t.TempDir()))
+			defer db.Close()
+
+			vs := NewVulnSrc(WithSchemaValidation())
+			err := vs.Update(dir)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVulnSrc_UpdateWithMaliciousSymlink(t *testing.T) {
+	dir := t.TempDir()
+	vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+
+	// The symlink points to the testdata dir, outside the data dir
+	target, err := filepath.Abs(filepath.Join("testdata", "npm_cvssnumberonly.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.Symlink(target, filepath.Join(vulnDir, "1.json")))
+
+	require.NoError(t, db.Init(t.TempDir()))
+	defer db.Close()
+
+	vs := NewVulnSrc()
+	err = vs.Update(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "points outside")
+}
+
+func TestVulnSrc_UpdateWithProvenance(t *testing.T) {
+	tests := []struct {
+		name            string
+		trackProvenance bool
+		want            string
+	}{
+		{
+			name:            "tracked",
+			trackProvenance: true,
+			want:            filepath.Join("nodejs-security-wg", "vuln", "npm", "1.json"),
+		},
+		{
+			name: "not tracked",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+			require.NoError(t, os.MkdirAll(vulnDir, 0700))
+			b, err := os.ReadFile(filepath.Join("testdata", "npm_cvssnumberonly.json"))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(vulnDir, "1.json"), b, 0600))
+
+			cacheDir := dbtest.InitDB(t, nil)
+
+			vs := NewVulnSrc(WithDBConfig(db.Config{TrackProvenance: tt.trackProvenance}))
+			require.NoError(t, vs.Update(dir))
+			require.NoError(t, db.Close())
+
+			want := types.Advisory{
+				VulnerableVersions: []string{"<=1.5.1"},
+				PatchedVersions:    []string{">=1.5.2"},
+				FixCommits:         []string{"https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
+			}
+			if tt.want != "" {
+				want.Provenance = filepath.Join(dir, tt.want)
+			}
+			dbtest.JSONEq(t, db.Path(cacheDir), []string{"advisory-detail", "CVE-2014-7205", bucketName, "bassmaster"}, want)
+		})
+	}
+}
+
+func TestVulnSrc_UpdateArray(t *testing.T) {
+	const records = 1000
+	var advisories []string
+	for i := 0; i < records; i++ {
+		advisories = append(advisories, fmt.Sprintf(`{"id": %d, "module_name": "package-%d", "vulnerable_versions": "<1.0.0"}`, i+1, i))
+	}
+
+	dir := t.TempDir()
+	vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	path := filepath.Join(vulnDir, "all.json")
+	require.NoError(t, os.WriteFile(path, []byte("["+strings.Join(advisories, ",")+"]"), 0600))
+
+	cacheDir := dbtest.InitDB(t, nil)
+	vs := NewVulnSrc(WithDBConfig(db.Config{TrackProvenance: true}))
+	require.NoError(t, vs.Update(dir))
+	require.NoError(t, db.Close())
+
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"advisory-detail", "NSWG-ECO-1", bucketName, "package-0"}, types.Advisory{
+		VulnerableVersions: []string{"<1.0.0"},
+		Provenance:         path + "#0",
+	})
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"advisory-detail", "NSWG-ECO-1000", bucketName, "package-999"}, types.Advisory{
+		VulnerableVersions: []string{"<1.0.0"},
+		Provenance:         path + "#999",
+	})
+}
+
+func TestVulnSrc_UpdateWithSourceDate(t *testing.T) {
+	dir := t.TempDir()
+	vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	b, err := os.ReadFile(filepath.Join("testdata", "npm_cvssnumberonly.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(vulnDir, "1.json"), b, 0600))
+
+	cacheDir := dbtest.InitDB(t, nil)
+
+	// The build time in another time zone is stored in UTC
+	buildTime := time.Date(2022, 3, 4, 14, 6, 7, 0, time.FixedZone("JST", 9*60*60))
+	vs := NewVulnSrc(WithDBConfig(db.Config{SourceDate: buildTime}))
+	require.NoError(t, vs.Update(dir))
+	require.NoError(t, db.Close())
+
+	fetchedAt := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"advisory-detail", "CVE-2014-7205", bucketName, "bassmaster"}, types.Advisory{
+		VulnerableVersions: []string{"<=1.5.1"},
+		PatchedVersions:    []string{">=1.5.2"},
+		FixCommits:         []string{"https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
+		FetchedAt:          &fetchedAt,
+	})
+}
+
+func TestVulnSrc_UpdateWithPostProcessor(t *testing.T) {
+	dir := t.TempDir()
+	vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	b, err := os.ReadFile(filepath.Join("testdata", "npm_cvssnumberonly.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(vulnDir, "1.json"), b, 0600))
+
+	cacheDir := dbtest.InitDB(t, nil)
+
+	dbc := db.Config{}
+	dbc.PostProcessors = map[string]func(tx *bolt.Tx) error{
+		string(vulnerability.NodejsSecurityWg): func(tx *bolt.Tx) error {
+			// The advisory must be visible in the same transaction
+			if tx.Bucket([]byte("advisory-detail")).Bucket([]byte("CVE-2014-7205")) == nil {
+				return fmt.Errorf("no advisory")
+			}
+			return dbc.PutVulnerabilityDetail(tx, "CVE-2014-7205", "internal", types.VulnerabilityDetail{
+				References: []string{"https://internal.example.com/CVE-2014-7205"},
+			})
+		},
+	}
+
+	vs := NewVulnSrc(WithDBConfig(dbc))
+	require.NoError(t, vs.Update(dir))
+	require.NoError(t, db.Close())
+
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"vulnerability-detail", "CVE-2014-7205", "internal"}, types.VulnerabilityDetail{
+		References: []string{"https://internal.example.com/CVE-2014-7205"},
+	})
+}
+
+func TestVulnSrc_UpdateAdvisoriesOnly(t *testing.T) {
+	dir := t.TempDir()
+	vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	b, err := os.ReadFile(filepath.Join("testdata", "npm_cvssnumberonly.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(vulnDir, "1.json"), b, 0600))
+
+	cacheDir := dbtest.InitDB(t, nil)
+
+	vs := NewVulnSrc(WithDBConfig(db.Config{AdvisoriesOnly: true}))
+	require.NoError(t, vs.Update(dir))
+	require.NoError(t, db.Close())
+
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"advisory-detail", "CVE-2014-7205", bucketName, "bassmaster"}, types.Advisory{
+		VulnerableVersions: []string{"<=1.5.1"},
+		PatchedVersions:    []string{">=1.5.2"},
+		FixCommits:         []string{"https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"},
+	})
+	dbtest.JSONEq(t, db.Path(cacheDir), []string{"vulnerability-id", "CVE-2014-7205"}, map[string]interface{}{})
+	dbtest.NoBucket(t, db.Path(cacheDir), []string{"vulnerability-detail"})
+}
+
+func TestVulnSrc_UpdateWithIDFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		dbc       db.Config
+		wantIDs   []string
+		unwantIDs []string
+	}{
+		{
+			name:      "exclude",
+			dbc:       db.Config{ExcludeIDs: []string{"CVE-2014-7205"}},
+			wantIDs:   []string{"NSWG-ECO-334"},
+			unwantIDs: []string{"CVE-2014-7205"},
+		},
+		{
+			name:      "include",
+			dbc:       db.Config{IncludeIDs: []string{"CVE-2014-7205"}},
+			wantIDs:   []string{"CVE-2014-7205"},
+			unwantIDs: []string{"NSWG-ECO-334"},
+		},
+		{
+			name: "exclude wins",
+			dbc: db.Config{
+				ExcludeIDs: []string{"CVE-2014-7205"},
+				IncludeIDs: []string{"CVE-2014-7205", "NSWG-ECO-334"},
+			},
+			wantIDs:   []string{"NSWG-ECO-334"},
+			unwantIDs: []string{"CVE-2014-7205"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+			require.NoError(t, os.MkdirAll(vulnDir, 0700))
+			for name, fixture := range map[string]string{
+				"1.json":   "npm_cvssnumberonly.json",
+				"334.json": "npm_nullcvssscore.json",
+			} {
+				b, err := os.ReadFile(filepath.Join("testdata", fixture))
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(filepath.Join(vulnDir, name), b, 0600))
+			}
+
+			cacheDir := dbtest.InitDB(t, nil)
+
+			vs := NewVulnSrc(WithDBConfig(tt.dbc))
+			require.NoError(t, vs.Update(dir))
+			require.NoError(t, db.Close())
+
+			for _, id := range tt.wantIDs {
+				dbtest.JSONEq(t, db.Path(cacheDir), []string{"vulnerability-id", id}, map[string]interface{}{})
+			}
+			for _, id := range tt.unwantIDs {
+				dbtest.NoKey(t, db.Path(cacheDir), []string{"vulnerability-id", id})
+				dbtest.NoBucket(t, db.Path(cacheDir), []string{"advisory-detail", id})
+				dbtest.NoBucket(t, db.Path(cacheDir), []string{"vulnerability-detail", id})
+			}
+		})
+	}
+}
+
+type countingDB struct {
+	db.Config
+	batches int
+}
+
+func (c *countingDB) BatchUpdate(fn func(*bolt.Tx) error) error {
+	c.batches++
+	return c.Config.BatchUpdate(fn)
+}
+
+func TestVulnSrc_UpdateWithBatchSize(t *testing.T) {
+	dir := t.TempDir()
+	vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	for name, fixture := range map[string]string{
+		"1.json":   "npm_cvssnumberonly.json",
+		"334.json": "npm_nullcvssscore.json",
+		"493.json": "493.json",
+	} {
+		b, err := os.ReadFile(filepath.Join("testdata", fixture))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(vulnDir, name), b, 0600))
+	}
+
+	update := func(batchSize int) (int, map[string]string) {
+		cacheDir := dbtest.InitDB(t, nil)
+
+		dbc := &countingDB{Config: db.Config{BatchSize: batchSize}}
+		vs := NewVulnSrc(WithDBConfig(dbc.Config))
+		vs.dbc = dbc
+		require.NoError(t, vs.Update(dir))
+		require.NoError(t, db.Close())
+
+		return dbc.batches, dumpDB(t, db.Path(cacheDir))
+	}
+
+	batches, want := update(0)
+	assert.Equal(t, 1, batches)
+
+	batches, got := update(1)
+	assert.Equal(t, 3, batches)
+	assert.Equal(t, want, got)
+}
+
+func TestVulnSrc_UpdateWithConcurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string // path in the vuln dir => fixture in testdata
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			files: map[string]string{
+				"npm/1.json":    "npm_cvssnumberonly.json",
+				"npm/2.json":    "npm_cvssnumberandstring.json",
+				"npm/3.json":    "npm_cvssv2vector.json",
+				"npm/4.json":    "npm_prerelease.json",
+				"npm/334.json":  "npm_nullcvssscore.json",
+				"npm/493.json":  "493.json",
+				"core/1.json":   "core_cvssnumberandstring.json",
+				"core/100.json": "core_nocvssscorepresent.json",
+			},
+		},
+		{
+			name: "sad path",
+			files: map[string]string{
+				"npm/1.json": "npm_cvssnumberonly.json",
+				"npm/2.json": "invalidvuln.json",
+				"npm/3.json": "493.json",
+			},
+			wantErr: "invalid character",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, fixture := range tt.files {
+				path := filepath.Join(dir, "nodejs-security-wg", "vuln", name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+				b, err := os.ReadFile(filepath.Join("testdata", fixture))
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, b, 0600))
+			}
+
+			update := func(opts ...Option) (map[string]string, error) {
+				cacheDir := dbtest.InitDB(t, nil)
+				if err := NewVulnSrc(opts...).Update(dir); err != nil {
+					require.NoError(t, db.Close())
+					return nil, err
+				}
+				require.NoError(t, db.Close())
+				return dumpDB(t, db.Path(cacheDir)), nil
+			}
+
+			want, err := update()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for _, n := range []int{2, 3, 16} {
+				got, err := update(WithConcurrency(n))
+				if tt.wantErr != "" {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), tt.wantErr)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, want, got, "concurrency: %d", n)
+			}
+		})
+	}
+}
+
+// dumpDB returns all the values keyed by the slash-separated bucket names and key
+func dumpDB(t *testing.T, dbPath string) map[string]string {
+	bdb, err := bolt.Open(dbPath, 0600, nil)
+	require.NoError(t, err)
+	defer bdb.Close()
+
+	values := map[string]string{}
+	var walk func(prefix string, bkt *bolt.Bucket) error
+	walk = func(prefix string, bkt *bolt.Bucket) error {
+		return bkt.ForEach(func(k, v []byte) error {
+			if v == nil {
+				return walk(prefix+string(k)+"/", bkt.Bucket(k))
+			}
+			values[prefix+string(k)] = string(v)
+			return nil
+		})
+	}
+	err = bdb.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+			return walk(string(name)+"/", bkt)
+		})
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, values)
+	return values
+}
+
+func TestVulnSrc_UpdateFromStdin(t *testing.T) {
+	fixture := filepath.Join("testdata", "npm_cvssnumberonly.json")
+
+	dir := t.TempDir()
+	vulnDir := filepath.Join(dir, "nodejs-security-wg", "vuln", "npm")
+	require.NoError(t, os.MkdirAll(vulnDir, 0700))
+	b, err := os.ReadFile(fixture)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(vulnDir, "1.json"), b, 0600))
+
+	cacheDir := dbtest.InitDB(t, nil)
+	require.NoError(t, NewVulnSrc().Update(dir))
+	require.NoError(t, db.Close())
+	want := dumpDB(t, db.Path(cacheDir))
+
+	f, err := os.Open(fixture)
+	require.NoError(t, err)
+	defer f.Close()
+
+	stdin := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = stdin }()
+
+	cacheDir = dbtest.InitDB(t, nil)
+	require.NoError(t, NewVulnSrc(WithSchemaValidation()).Update(Stdin))
+	require.NoError(t, db.Close())
+	assert.Equal(t, want, dumpDB(t, db.Path(cacheDir)))
+}
+
+func TestVulnSrc_UpdateCoverage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteSelfTestData(dir))
+
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	vs := NewVulnSrc()
+	require.NoError(t, vs.Update(dir))
+
+	got, err := db.Config{}.Coverage()
+	require.NoError(t, err)
+	want := map[string]db.CoverageStats{
+		"npm::Node.js Ecosystem Security Working Group": {
+			Advisories: 3,
+			Packages:   3,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestVulnSrc_UpdateMetrics(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteSelfTestData(dir))
+
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	metrics := db.NewInProcessMetrics()
+	vs := NewVulnSrc(WithDBConfig(db.Config{MetricsRegistry: metrics}))
+	require.NoError(t, vs.Update(dir))
+
+	labels := map[string]string{"source": string(vulnerability.NodejsSecurityWg)}
+	assert.Equal(t, float64(3), metrics.Counter(db.MetricAdvisoriesWritten, labels))
+	assert.Equal(t, float64(3), metrics.Counter(db.MetricVulnerabilitiesWritten, labels))
+
+	// Identical advisories are not written again
+	require.NoError(t, vs.Update(dir))
+	assert.Equal(t, float64(3), metrics.Counter(db.MetricAdvisoriesWritten, labels))
+	assert.Equal(t, float64(6), metrics.Counter(db.MetricVulnerabilitiesWritten, labels))
+}
+
+// BenchmarkNodeCommit measures the commit path of representative npm and core advisories.
+// See benchmarks/baseline.txt for the baseline.
+func BenchmarkNodeCommit(b *testing.B) {
+	files := []string{
+		"testdata/npm_cvssnumberandstring.json",
+		"testdata/npm_cvssv2vector.json",
+		"testdata/npm_prerelease.json",
+		"testdata/core_cvssnumberandstring.json",
+	}
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		var err error
+		contents[i], err = os.ReadFile(file)
+		require.NoError(b, err)
+	}
+
+	require.NoError(b, db.Init(b.TempDir()))
+	defer db.Close()
+
+	vs := NewVulnSrc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+			for j, content := range contents {
+				if err := vs.commit(tx, bytes.NewReader(content), files[j]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(b, err)
+	}
+}
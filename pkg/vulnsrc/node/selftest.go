@@ -0,0 +1,39 @@
+package node
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+// selfTestData holds a few advisories of the test data, used by vulndb.SelfTest to build a DB without network
+//
+//go:embed testdata/npm_cvssnumberonly.json testdata/npm_nullcvssscore.json testdata/493.json
+var selfTestData embed.FS
+
+// WriteSelfTestData writes the advisories for the self-test into dir in the layout read by Update
+func WriteSelfTestData(dir string) error {
+	vulnDir := filepath.Join(dir, nodeDir, "vuln", "npm")
+	if err := os.MkdirAll(vulnDir, 0700); err != nil {
+		return xerrors.Errorf("failed to mkdir: %w", err)
+	}
+
+	return fs.WalkDir(selfTestData, "testdata", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() {
+			return nil
+		}
+		b, err := selfTestData.ReadFile(path)
+		if err != nil {
+			return xerrors.Errorf("failed to read %s: %w", path, err)
+		}
+		if err = os.WriteFile(filepath.Join(vulnDir, d.Name()), b, 0600); err != nil {
+			return xerrors.Errorf("failed to write %s: %w", d.Name(), err)
+		}
+		return nil
+	})
+}
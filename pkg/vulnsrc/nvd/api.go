@@ -0,0 +1,258 @@
+package nvd
+
+import (
+	"encoding/json"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/utils"
+)
+
+const (
+	vulnStatusRejected = "Rejected"
+
+	cveTagDisputed = "disputed"
+
+	// metricTypePrimary is the type of the metrics given by NVD, as opposed to "Secondary" given by CNAs
+	metricTypePrimary = "Primary"
+)
+
+// CVEResponse is a response of the CVE API 2.0,
+// e.g. https://services.nvd.nist.gov/rest/json/cves/2.0?cveId=CVE-2021-44228
+type CVEResponse struct {
+	Vulnerabilities []struct {
+		Cve CVE `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// CVE is a CVE record of the CVE API 2.0
+type CVE struct {
+	ID             string          `json:"id"`
+	VulnStatus     string          `json:"vulnStatus"`
+	Published      string          `json:"published"`
+	LastModified   string          `json:"lastModified"`
+	Descriptions   []LangString    `json:"descriptions"`
+	Metrics        Metrics         `json:"metrics"`
+	Weaknesses     []Weakness      `json:"weaknesses"`
+	Configurations []Configuration `json:"configurations"`
+	References     []Reference     `json:"references"`
+	CveTags        []CveTag        `json:"cveTags"`
+}
+
+// CveTag is a tag given by the CNA, e.g. "disputed"
+type CveTag struct {
+	SourceIdentifier string   `json:"sourceIdentifier"`
+	Tags             []string `json:"tags"`
+}
+
+type LangString struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+type Metrics struct {
+	CvssMetricV31 []CvssMetricV3 `json:"cvssMetricV31"`
+	CvssMetricV30 []CvssMetricV3 `json:"cvssMetricV30"`
+	CvssMetricV2  []CvssMetricV2 `json:"cvssMetricV2"`
+}
+
+type CvssMetricV3 struct {
+	Source   string `json:"source"`
+	Type     string `json:"type"`
+	CvssData struct {
+		VectorString string  `json:"vectorString"`
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+}
+
+type CvssMetricV2 struct {
+	Source   string `json:"source"`
+	Type     string `json:"type"`
+	CvssData struct {
+		VectorString string  `json:"vectorString"`
+		BaseScore    float64 `json:"baseScore"`
+	} `json:"cvssData"`
+	BaseSeverity string `json:"baseSeverity"`
+}
+
+type Weakness struct {
+	Source      string       `json:"source"`
+	Type        string       `json:"type"`
+	Description []LangString `json:"description"`
+}
+
+type Configuration struct {
+	Nodes []struct {
+		Operator string `json:"operator"`
+		CpeMatch []struct {
+			Vulnerable bool   `json:"vulnerable"`
+			Criteria   string `json:"criteria"`
+		} `json:"cpeMatch"`
+	} `json:"nodes"`
+}
+
+type Reference struct {
+	URL    string `json:"url"`
+	Source string `json:"source"`
+}
+
+// decodeItems decodes a file of the legacy JSON feed, a CVE record of the CVE API 2.0 or a response of the API,
+// and passes the items to fn. The "vulnerabilities" of a response, e.g. a full dump, are decoded one by one
+// so that the whole response is never held in memory. Rejected CVEs of the API are skipped.
+func decodeItems(r io.Reader, fn func(item Item) error) error {
+	return utils.DecodeJSONStream(r, func(dec *json.Decoder, _ int) error {
+		if t, err := dec.Token(); err != nil {
+			return xerrors.Errorf("JSON token error: %w", err)
+		} else if t != json.Delim('{') {
+			return xerrors.Errorf("unexpected JSON token: %v", t)
+		}
+
+		var response bool
+		fields := map[string]json.RawMessage{}
+		for dec.More() {
+			t, err := dec.Token()
+			if err != nil {
+				return xerrors.Errorf("JSON token error: %w", err)
+			}
+			key, _ := t.(string)
+			if key != "vulnerabilities" {
+				var raw json.RawMessage
+				if err = dec.Decode(&raw); err != nil {
+					return xerrors.Errorf("JSON decode error (%s): %w", key, err)
+				}
+				fields[key] = raw
+				continue
+			}
+
+			response = true
+			err = utils.DecodeJSONArray(dec, func(dec *json.Decoder, _ int) error {
+				var v struct {
+					Cve CVE `json:"cve"`
+				}
+				if err := dec.Decode(&v); err != nil {
+					return xerrors.Errorf("failed to decode the CVE API response: %w", err)
+				}
+				if v.Cve.VulnStatus == vulnStatusRejected {
+					return nil
+				}
+				return fn(v.Cve.item())
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if response {
+			return nil
+		}
+
+		// A CVE record or an item of the legacy feed holds a single CVE
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return xerrors.Errorf("JSON marshal error: %w", err)
+		}
+		if fields["vulnStatus"] == nil {
+			var item Item
+			if err = json.Unmarshal(b, &item); err != nil {
+				return xerrors.Errorf("failed to decode NVD JSON: %w", err)
+			}
+			return fn(item)
+		}
+
+		var cve CVE
+		if err = json.Unmarshal(b, &cve); err != nil {
+			return xerrors.Errorf("failed to decode the CVE API record: %w", err)
+		} else if cve.VulnStatus == vulnStatusRejected {
+			return nil
+		}
+		return fn(cve.item())
+	})
+}
+
+// item converts the CVE record into the legacy feed item, preferring the metrics given by NVD
+func (c CVE) item() Item {
+	item := Item{
+		Cve: Cve{
+			Meta: Meta{ID: c.ID},
+		},
+		PublishedDate:    c.Published,
+		LastModifiedDate: c.LastModified,
+		CveTags:          c.CveTags,
+	}
+
+	for _, d := range c.Descriptions {
+		item.Cve.Description.DescriptionDataList = append(item.Cve.Description.DescriptionDataList,
+			DescriptionData{Lang: d.Lang, Value: d.Value})
+	}
+	for _, ref := range c.References {
+		item.Cve.References.ReferenceDataList = append(item.Cve.References.ReferenceDataList,
+			ReferenceData{URL: ref.URL, Refsource: ref.Source})
+	}
+	for _, w := range c.Weaknesses {
+		var descs []ProblemTypeDataDescription
+		for _, d := range w.Description {
+			descs = append(descs, ProblemTypeDataDescription{Lang: d.Lang, Value: d.Value})
+		}
+		item.Cve.ProblemType.ProblemTypeData = append(item.Cve.ProblemType.ProblemTypeData,
+			ProblemTypeData{Description: descs})
+	}
+	for _, conf := range c.Configurations {
+		for _, n := range conf.Nodes {
+			node := Node{Operator: n.Operator}
+			for _, m := range n.CpeMatch {
+				node.CpeMatch = append(node.CpeMatch, CpeMatch{Vulnerable: m.Vulnerable, Cpe23URI: m.Criteria})
+			}
+			item.Configurations.Nodes = append(item.Configurations.Nodes, node)
+		}
+	}
+
+	// CVSS v3.0 is given only for old CVEs
+	metricsV3 := c.Metrics.CvssMetricV31
+	if len(metricsV3) == 0 {
+		metricsV3 = c.Metrics.CvssMetricV30
+	}
+	if m, ok := primaryV3(metricsV3); ok {
+		item.Impact.BaseMetricV3.CvssV3 = CvssV3{
+			BaseScore:    m.CvssData.BaseScore,
+			BaseSeverity: m.CvssData.BaseSeverity,
+			VectorString: m.CvssData.VectorString,
+		}
+	}
+	if m, ok := primaryV2(c.Metrics.CvssMetricV2); ok {
+		item.Impact.BaseMetricV2 = BaseMetricV2{
+			CvssV2: CvssV2{
+				BaseScore:    m.CvssData.BaseScore,
+				VectorString: m.CvssData.VectorString,
+			},
+			Severity: m.BaseSeverity,
+		}
+	}
+	return item
+}
+
+// primaryV3 returns the metric given by NVD, or the first one if NVD gives none
+func primaryV3(metrics []CvssMetricV3) (CvssMetricV3, bool) {
+	if len(metrics) == 0 {
+		return CvssMetricV3{}, false
+	}
+	for _, m := range metrics {
+		if m.Type == metricTypePrimary {
+			return m, true
+		}
+	}
+	return metrics[0], true
+}
+
+// primaryV2 returns the metric given by NVD, or the first one if NVD gives none
+func primaryV2(metrics []CvssMetricV2) (CvssMetricV2, bool) {
+	if len(metrics) == 0 {
+		return CvssMetricV2{}, false
+	}
+	for _, m := range metrics {
+		if m.Type == metricTypePrimary {
+			return m, true
+		}
+	}
+	return metrics[0], true
+}
@@ -1,8 +1,7 @@
 package nvd
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"io"
 	"log"
 	"path/filepath"
@@ -20,112 +19,194 @@ import (
 
 const (
 	nvdDir = "nvd"
+
+	// e.g. "** DISPUTED ** An issue was discovered in ..."
+	disputedPrefix = "** DISPUTED **"
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return vulnerability.NVD
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
-	rootDir := filepath.Join(dir, "vuln-list", nvdDir)
+	log.Println("NVD batch update")
+	err := vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return load(dir, func(item Item) error {
+			return vs.commit(tx, []Item{item})
+		})
+	})
+	if err != nil {
+		return xerrors.Errorf("error in NVD batch update: %w", err)
+	}
 
-	var items []Item
-	buffer := &bytes.Buffer{}
-	err := utils.FileWalk(rootDir, func(r io.Reader, _ string) error {
-		item := Item{}
-		if _, err := buffer.ReadFrom(r); err != nil {
-			return xerrors.Errorf("failed to read file: %w", err)
-		}
-		if err := json.Unmarshal(buffer.Bytes(), &item); err != nil {
-			return xerrors.Errorf("failed to decode NVD JSON: %w", err)
-		}
-		buffer.Reset()
-		items = append(items, item)
+	return nil
+}
+
+// Load reads the NVD feed under the directory without storing it, e.g. to enrich vulnerabilities at build time.
+func Load(dir string) (map[string]types.VulnerabilityDetail, error) {
+	details := map[string]types.VulnerabilityDetail{}
+	err := load(dir, func(item Item) error {
+		details[item.Cve.Meta.ID] = convert(item)
 		return nil
 	})
 	if err != nil {
-		return xerrors.Errorf("error in NVD walk: %w", err)
+		return nil, err
 	}
+	return details, nil
+}
 
-	if err = vs.save(items); err != nil {
-		return xerrors.Errorf("error in NVD save: %w", err)
+// load passes the items of the NVD feed under the directory to fn one by one
+func load(dir string, fn func(item Item) error) error {
+	rootDir := filepath.Join(dir, "vuln-list", nvdDir)
+	err := utils.FileWalk(rootDir, func(r io.Reader, path string) error {
+		if err := decodeItems(r, fn); err != nil {
+			return xerrors.Errorf("%s: %w", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("error in NVD walk: %w", err)
 	}
-
 	return nil
 }
 
 func (vs VulnSrc) commit(tx *bolt.Tx, items []Item) error {
 	for _, item := range items {
 		cveID := item.Cve.Meta.ID
-		severity, _ := types.NewSeverity(item.Impact.BaseMetricV2.Severity)
-		severityV3, _ := types.NewSeverity(item.Impact.BaseMetricV3.CvssV3.BaseSeverity)
-
-		var references []string
-		for _, ref := range item.Cve.References.ReferenceDataList {
-			references = append(references, ref.URL)
+		vuln := convert(item)
+		if err := vs.dbc.PutVulnerabilityDetail(tx, cveID, vulnerability.NVD, vuln); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		var (
-			description string
-		)
-		for _, d := range item.Cve.Description.DescriptionDataList {
-			if d.Value != "" {
-				description = d.Value
-				break
-			}
+func convert(item Item) types.VulnerabilityDetail {
+	severity, _ := types.NewSeverity(item.Impact.BaseMetricV2.Severity)
+	severityV3, _ := types.NewSeverity(item.Impact.BaseMetricV3.CvssV3.BaseSeverity)
+
+	var references []string
+	for _, ref := range item.Cve.References.ReferenceDataList {
+		references = append(references, ref.URL)
+	}
+
+	var (
+		description string
+	)
+	for _, d := range item.Cve.Description.DescriptionDataList {
+		if d.Value != "" {
+			description = d.Value
+			break
 		}
-		var cweIDs []string
-		for _, data := range item.Cve.ProblemType.ProblemTypeData {
-			for _, desc := range data.Description {
-				if !strings.HasPrefix(desc.Value, "CWE") {
-					continue
-				}
-				cweIDs = append(cweIDs, desc.Value)
+	}
+	var cweIDs []string
+	for _, data := range item.Cve.ProblemType.ProblemTypeData {
+		for _, desc := range data.Description {
+			if !strings.HasPrefix(desc.Value, "CWE") {
+				continue
 			}
+			cweIDs = append(cweIDs, desc.Value)
 		}
+	}
+
+	publishedDate := parseDate(item.PublishedDate)
+	lastModifiedDate := parseDate(item.LastModifiedDate)
+
+	return types.VulnerabilityDetail{
+		CvssScore:        item.Impact.BaseMetricV2.CvssV2.BaseScore,
+		CvssVector:       item.Impact.BaseMetricV2.CvssV2.VectorString,
+		CvssScoreV3:      item.Impact.BaseMetricV3.CvssV3.BaseScore,
+		CvssVectorV3:     item.Impact.BaseMetricV3.CvssV3.VectorString,
+		Severity:         severity,
+		SeverityV3:       severityV3,
+		CweIDs:           cweIDs,
+		References:       references,
+		CPEs:             vulnerableCPEs(item.Configurations.Nodes),
+		Title:            "",
+		Description:      description,
+		PublishedDate:    &publishedDate,
+		LastModifiedDate: &lastModifiedDate,
+		Disputed:         isDisputed(item, description),
+	}
+}
 
-		publishedDate, _ := time.Parse("2006-01-02T15:04Z", item.PublishedDate)
-		lastModifiedDate, _ := time.Parse("2006-01-02T15:04Z", item.LastModifiedDate)
-
-		vuln := types.VulnerabilityDetail{
-			CvssScore:        item.Impact.BaseMetricV2.CvssV2.BaseScore,
-			CvssVector:       item.Impact.BaseMetricV2.CvssV2.VectorString,
-			CvssScoreV3:      item.Impact.BaseMetricV3.CvssV3.BaseScore,
-			CvssVectorV3:     item.Impact.BaseMetricV3.CvssV3.VectorString,
-			Severity:         severity,
-			SeverityV3:       severityV3,
-			CweIDs:           cweIDs,
-			References:       references,
-			Title:            "",
-			Description:      description,
-			PublishedDate:    &publishedDate,
-			LastModifiedDate: &lastModifiedDate,
+// isDisputed returns true if the CVE is tagged as disputed or the description says so as the legacy feed does
+func isDisputed(item Item, description string) bool {
+	if strings.HasPrefix(description, disputedPrefix) {
+		return true
+	}
+	for _, t := range item.CveTags {
+		for _, tag := range t.Tags {
+			if tag == cveTagDisputed {
+				return true
+			}
 		}
+	}
+	return false
+}
 
-		if err := vs.dbc.PutVulnerabilityDetail(tx, cveID, vulnerability.NVD, vuln); err != nil {
-			return err
+// vulnerableCPEs returns the unique CPEs marked as vulnerable in the nodes and their children, in order of appearance.
+// CPEs of platforms the product runs on, e.g. in "AND" nodes, are not vulnerable themselves and skipped.
+func vulnerableCPEs(nodes []Node) []string {
+	var cpes []string
+	uniq := map[string]struct{}{}
+	var walk func(nodes []Node)
+	walk = func(nodes []Node) {
+		for _, n := range nodes {
+			for _, m := range n.CpeMatch {
+				if _, ok := uniq[m.Cpe23URI]; !m.Vulnerable || m.Cpe23URI == "" || ok {
+					continue
+				}
+				uniq[m.Cpe23URI] = struct{}{}
+				cpes = append(cpes, m.Cpe23URI)
+			}
+			walk(n.Children)
 		}
 	}
-	return nil
+	walk(nodes)
+	return cpes
 }
 
-func (vs VulnSrc) save(items []Item) error {
-	log.Println("NVD batch update")
-	err := vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
-		return vs.commit(tx, items)
-	})
-	if err != nil {
-		return xerrors.Errorf("error in batch update: %w", err)
+// parseDate parses the date of the legacy feed, e.g. "2020-01-01T01:01Z", or the CVE API 2.0,
+// e.g. "2021-12-10T10:15:09.143" in UTC. The zero time is returned if it cannot be parsed.
+func parseDate(s string) time.Time {
+	for _, layout := range []string{"2006-01-02T15:04Z", "2006-01-02T15:04:05.999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
 	}
-	return nil
+	return time.Time{}
 }
@@ -1,8 +1,11 @@
 package nvd
 
 import (
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/aquasecurity/trivy-db/pkg/utils"
@@ -41,6 +44,35 @@ func TestVulnSrc_Update(t *testing.T) {
 				References:       []string{"https://source.android.com/security/bulletin/2020-01-01"},
 				LastModifiedDate: utils.MustTimeParse("2020-01-01T01:01:00Z"),
 				PublishedDate:    utils.MustTimeParse("2001-01-01T01:01:00Z"),
+				CPEs: []string{
+					"cpe:2.3:o:google:android:8.0:*:*:*:*:*:*:*",
+					"cpe:2.3:o:google:android:8.1:*:*:*:*:*:*:*",
+					"cpe:2.3:o:google:android:9.0:*:*:*:*:*:*:*",
+					"cpe:2.3:o:google:android:10.0:*:*:*:*:*:*:*",
+				},
+			},
+		},
+		{
+			name:  "CVE API 2.0",
+			dir:   "./testdata/api",
+			cveID: "CVE-2021-44228",
+			want: types.VulnerabilityDetail{
+				Description:      "Apache Log4j2 2.0-beta9 through 2.15.0 (excluding security releases 2.12.2, 2.12.3, and 2.3.1) JNDI features used in configuration, log messages, and parameters do not protect against attacker controlled LDAP and other JNDI related endpoints.",
+				CvssScore:        9.3,
+				CvssVector:       "AV:N/AC:M/Au:N/C:C/I:C/A:C",
+				CvssScoreV3:      10.0,
+				CvssVectorV3:     "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H",
+				Severity:         types.SeverityHigh,
+				SeverityV3:       types.SeverityCritical,
+				CweIDs:           []string{"CWE-502", "CWE-917"},
+				References:       []string{"https://logging.apache.org/log4j/2.x/security.html"},
+				LastModifiedDate: utils.MustTimeParse("2023-04-03T20:15:08.053Z"),
+				PublishedDate:    utils.MustTimeParse("2021-12-10T10:15:09.143Z"),
+				// The hardware the firmware runs on is not vulnerable
+				CPEs: []string{
+					"cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*",
+					"cpe:2.3:a:siemens:sppa-t3000_ses3000_firmware:*:*:*:*:*:*:*:*",
+				},
 			},
 		},
 		{
@@ -220,6 +252,86 @@ func TestVulnSrc_Commit(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "happy path with disputed tag",
+			cves: []Item{
+				{
+					Cve: Cve{
+						Meta: Meta{
+							ID: "CVE-2020-19909",
+						},
+						Description: Description{
+							DescriptionDataList: []DescriptionData{
+								{
+									Lang:  "en",
+									Value: "Integer overflow vulnerability in tool_operate.c in curl 7.65.2",
+								},
+							},
+						},
+					},
+					CveTags: []CveTag{
+						{
+							SourceIdentifier: "cve@mitre.org",
+							Tags:             []string{"disputed"},
+						},
+					},
+					PublishedDate:    "2023-08-25T20:15Z",
+					LastModifiedDate: "2023-09-01T13:15Z",
+				},
+			},
+			putVulnerabilityDetail: []db.OperationPutVulnerabilityDetailExpectation{
+				{
+					Args: db.OperationPutVulnerabilityDetailArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2020-19909",
+						Source:          vulnerability.NVD,
+						Vulnerability: types.VulnerabilityDetail{
+							Description:      "Integer overflow vulnerability in tool_operate.c in curl 7.65.2",
+							PublishedDate:    utils.MustTimeParse("2023-08-25T20:15:00Z"),
+							LastModifiedDate: utils.MustTimeParse("2023-09-01T13:15:00Z"),
+							Disputed:         true,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "happy path with ** DISPUTED ** in description",
+			cves: []Item{
+				{
+					Cve: Cve{
+						Meta: Meta{
+							ID: "CVE-2018-1000620",
+						},
+						Description: Description{
+							DescriptionDataList: []DescriptionData{
+								{
+									Lang:  "en",
+									Value: "** DISPUTED ** Eran Hammer cryptiles version 4.1.1 earlier contains a CWE-331",
+								},
+							},
+						},
+					},
+					PublishedDate:    "2018-07-09T20:29Z",
+					LastModifiedDate: "2019-10-03T00:03Z",
+				},
+			},
+			putVulnerabilityDetail: []db.OperationPutVulnerabilityDetailExpectation{
+				{
+					Args: db.OperationPutVulnerabilityDetailArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2018-1000620",
+						Source:          vulnerability.NVD,
+						Vulnerability: types.VulnerabilityDetail{
+							Description:      "** DISPUTED ** Eran Hammer cryptiles version 4.1.1 earlier contains a CWE-331",
+							PublishedDate:    utils.MustTimeParse("2018-07-09T20:29:00Z"),
+							LastModifiedDate: utils.MustTimeParse("2019-10-03T00:03:00Z"),
+							Disputed:         true,
+						},
+					},
+				},
+			},
+		},
 
 		// TODO: Add sad paths
 	}
@@ -245,3 +357,74 @@ func TestVulnSrc_Commit(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad(t *testing.T) {
+	details, err := Load("testdata")
+	require.NoError(t, err)
+	require.Len(t, details, 1)
+
+	detail := details["CVE-2020-0001"]
+	assert.Equal(t, []string{"CWE-269"}, detail.CweIDs)
+	assert.Empty(t, detail.Title)
+	assert.Contains(t, detail.Description, "In getProcessRecordLocked of ActivityManagerService.java")
+
+	_, err = Load("testdata/missing")
+	assert.Error(t, err)
+}
+
+func TestLoad_API(t *testing.T) {
+	details, err := Load("testdata/api")
+	require.NoError(t, err)
+
+	// The rejected CVE is skipped
+	assert.Len(t, details, 1)
+	assert.Contains(t, details, "CVE-2021-44228")
+	assert.NotContains(t, details, "CVE-2021-0001")
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestDecodeItems(t *testing.T) {
+	// A full dump of the CVE API 2.0
+	const records = 10000
+	var sb strings.Builder
+	sb.WriteString(`{"resultsPerPage": 10000, "format": "NVD_CVE", "vulnerabilities": [`)
+	for i := 0; i < records; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		status := "Analyzed"
+		if i%2 == 1 {
+			status = vulnStatusRejected
+		}
+		fmt.Fprintf(&sb, `{"cve": {"id": "CVE-2021-%04d", "vulnStatus": %q}}`, i, status)
+	}
+	sb.WriteString(`], "totalResults": 10000}`)
+
+	r := &countingReader{r: strings.NewReader(sb.String())}
+	var got []string
+	var firstRead int
+	err := decodeItems(r, func(item Item) error {
+		if len(got) == 0 {
+			firstRead = r.n
+		}
+		got = append(got, item.Cve.Meta.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, records/2)
+	assert.Equal(t, "CVE-2021-0000", got[0])
+	assert.Equal(t, "CVE-2021-0002", got[1])
+
+	// The first CVE is passed before the whole response is read
+	assert.Less(t, firstRead, sb.Len()/10)
+}
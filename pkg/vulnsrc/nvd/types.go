@@ -6,9 +6,13 @@ type NVD struct {
 
 type Item struct {
 	Cve              Cve
+	Configurations   Configurations `json:"configurations"`
 	Impact           Impact
 	LastModifiedDate string `json:"lastModifiedDate"`
 	PublishedDate    string `json:"publishedDate"`
+
+	// CveTags are given only by the CVE API 2.0
+	CveTags []CveTag `json:"cveTags,omitempty"`
 }
 
 type Cve struct {
@@ -77,3 +81,19 @@ type ProblemTypeDataDescription struct {
 	Lang  string
 	Value string
 }
+
+type Configurations struct {
+	Nodes []Node `json:"nodes"`
+}
+
+// Node combines CPE matches and child nodes with the operator, e.g. "OR"
+type Node struct {
+	Operator string     `json:"operator"`
+	Children []Node     `json:"children"`
+	CpeMatch []CpeMatch `json:"cpe_match"`
+}
+
+type CpeMatch struct {
+	Vulnerable bool   `json:"vulnerable"`
+	Cpe23URI   string `json:"cpe23Uri"`
+}
@@ -0,0 +1,198 @@
+package openssl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/bucket"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+const (
+	opensslDir = "openssl"
+	pkgName    = "openssl"
+)
+
+var (
+	source = types.DataSource{
+		ID:   vulnerability.OpenSSL,
+		Name: "OpenSSL Security Advisories",
+		URL:  "https://www.openssl.org/news/vulnerabilities.html",
+	}
+
+	bucketName = bucket.Name(string(vulnerability.OpenSSLVersion), source.Name)
+)
+
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
+type VulnSrc struct {
+	dbc db.Operation
+}
+
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
+		dbc: db.Config{},
+	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
+}
+
+func (vs VulnSrc) Name() types.SourceID {
+	return source.ID
+}
+
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
+func (vs VulnSrc) Update(dir string) error {
+	filePath := filepath.Join(dir, "vuln-list", opensslDir, "vulnerabilities.json")
+	f, err := os.Open(filePath)
+	if err != nil {
+		return xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	if err = json.NewDecoder(f).Decode(&records); err != nil {
+		return xerrors.Errorf("JSON decode error (%s): %w", filePath, err)
+	}
+
+	if err = vs.save(records); err != nil {
+		return xerrors.Errorf("save error: %w", err)
+	}
+	return nil
+}
+
+func (vs VulnSrc) save(records []Record) error {
+	log.Println("Saving OpenSSL Security Advisories")
+	err := vs.dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		if err := vs.dbc.PutDataSource(tx, bucketName, source); err != nil {
+			return xerrors.Errorf("failed to put data source: %w", err)
+		}
+
+		for _, record := range records {
+			if err := vs.commit(tx, record); err != nil {
+				return xerrors.Errorf("commit error (%s): %w", record.CveMetadata.CveID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("batch update error: %w", err)
+	}
+	return nil
+}
+
+func (vs VulnSrc) commit(tx *bolt.Tx, record Record) error {
+	vulnID := record.CveMetadata.CveID
+	cna := record.Containers.Cna
+
+	advisory := convertToAdvisory(cna.Affected)
+	if len(advisory.VulnerableVersions) == 0 {
+		return nil
+	}
+
+	if err := vs.dbc.PutAdvisoryDetail(tx, vulnID, pkgName, []string{bucketName}, advisory); err != nil {
+		return xerrors.Errorf("failed to save OpenSSL advisory: %w", err)
+	}
+
+	var references []string
+	for _, ref := range cna.References {
+		references = append(references, ref.URL)
+	}
+
+	vuln := types.VulnerabilityDetail{
+		Title:       cna.Title,
+		Description: description(cna.Descriptions),
+		Severity:    severity(cna.Metrics),
+		References:  references,
+	}
+	if t, err := time.Parse(time.RFC3339, record.CveMetadata.DatePublished); err == nil {
+		vuln.PublishedDate = &t
+	}
+	if err := vs.dbc.PutVulnerabilityDetail(tx, vulnID, source.ID, vuln); err != nil {
+		return xerrors.Errorf("failed to save OpenSSL vulnerability detail: %w", err)
+	}
+
+	// for optimization
+	if err := vs.dbc.PutVulnerabilityID(tx, vulnID); err != nil {
+		return xerrors.Errorf("failed to save the vulnerability ID: %w", err)
+	}
+	return nil
+}
+
+// convertToAdvisory converts the affected ranges of OpenSSL,
+// e.g. {"version": "1.0.2", "lessThan": "1.0.2h"} => ">=1.0.2, <1.0.2h"
+func convertToAdvisory(affected []Affected) types.Advisory {
+	var advisory types.Advisory
+	for _, a := range affected {
+		if !strings.EqualFold(a.Product, "OpenSSL") {
+			continue
+		}
+		for _, v := range a.Versions {
+			if v.Status != "affected" || v.Version == "" {
+				continue
+			}
+			switch {
+			case v.LessThan != "":
+				advisory.VulnerableVersions = append(advisory.VulnerableVersions, fmt.Sprintf(">=%s, <%s", v.Version, v.LessThan))
+				advisory.PatchedVersions = append(advisory.PatchedVersions, v.LessThan)
+			case v.LessThanOrEqual != "":
+				advisory.VulnerableVersions = append(advisory.VulnerableVersions, fmt.Sprintf(">=%s, <=%s", v.Version, v.LessThanOrEqual))
+			default:
+				advisory.VulnerableVersions = append(advisory.VulnerableVersions, fmt.Sprintf("=%s", v.Version))
+			}
+		}
+	}
+	return advisory
+}
+
+func description(descriptions []Description) string {
+	for _, d := range descriptions {
+		if d.Lang == "en" {
+			return d.Value
+		}
+	}
+	return ""
+}
+
+// severity returns the OpenSSL severity, i.e. Low, Moderate, High or Critical
+func severity(metrics []Metric) types.Severity {
+	for _, m := range metrics {
+		text := strings.ToUpper(m.Other.Content.Text)
+		if text == "MODERATE" {
+			text = "MEDIUM"
+		}
+		if s, err := types.NewSeverity(text); err == nil {
+			return s
+		}
+	}
+	return types.SeverityUnknown
+}
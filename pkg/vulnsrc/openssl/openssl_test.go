@@ -0,0 +1,156 @@
+package openssl_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/openssl"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+func TestVulnSrc_Update(t *testing.T) {
+	type wantKV struct {
+		key   []string
+		value interface{}
+	}
+	tests := []struct {
+		name    string
+		dir     string
+		want    []wantKV
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			dir:  filepath.Join("testdata", "happy"),
+			want: []wantKV{
+				{
+					key: []string{"data-source", "openssl::OpenSSL Security Advisories"},
+					value: types.DataSource{
+						ID:   vulnerability.OpenSSL,
+						Name: "OpenSSL Security Advisories",
+						URL:  "https://www.openssl.org/news/vulnerabilities.html",
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2016-2105", "openssl::OpenSSL Security Advisories", "openssl"},
+					value: types.Advisory{
+						VulnerableVersions: []string{">=1.0.1, <1.0.1t", ">=1.0.2, <1.0.2h"},
+						PatchedVersions:    []string{"1.0.1t", "1.0.2h"},
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2022-3602", "openssl::OpenSSL Security Advisories", "openssl"},
+					value: types.Advisory{
+						VulnerableVersions: []string{">=3.0.0, <3.0.7"},
+						PatchedVersions:    []string{"3.0.7"},
+					},
+				},
+				{
+					key: []string{"vulnerability-detail", "CVE-2016-2105", string(vulnerability.OpenSSL)},
+					value: types.VulnerabilityDetail{
+						Title:         "EVP_EncodeUpdate overflow",
+						Description:   "An overflow can occur in the EVP_EncodeUpdate() function which is used for Base64 encoding of binary data.",
+						Severity:      types.SeverityLow,
+						References:    []string{"https://www.openssl.org/news/secadv/20160503.txt"},
+						PublishedDate: timePtr(time.Date(2016, 5, 3, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+				{
+					key:   []string{"vulnerability-id", "CVE-2022-3602"},
+					value: map[string]interface{}{},
+				},
+			},
+		},
+		{
+			name:    "sad path",
+			dir:     filepath.Join("testdata", "broken"),
+			wantErr: "JSON decode error",
+		},
+		{
+			name:    "no such file",
+			dir:     filepath.Join("testdata", "unknown"),
+			wantErr: "file open error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			err := db.Init(tempDir)
+			require.NoError(t, err)
+			defer db.Close()
+
+			vs := openssl.NewVulnSrc()
+			err = vs.Update(tt.dir)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, db.Close())
+
+			for _, w := range tt.want {
+				dbtest.JSONEq(t, db.Path(tempDir), w.key, w.value, w.key)
+			}
+		})
+	}
+}
+
+func TestVulnSrc_Bounds(t *testing.T) {
+	require.NoError(t, db.Init(t.TempDir()))
+	defer db.Close()
+
+	require.NoError(t, openssl.NewVulnSrc().Update(filepath.Join("testdata", "happy")))
+
+	// Copy the advisory into the namespace bucket as the build does
+	dbc := db.Config{}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.SaveAdvisoryDetails(tx, "CVE-2016-2105")
+	})
+	require.NoError(t, err)
+
+	advisories, err := dbc.GetAdvisories("openssl::OpenSSL Security Advisories", "openssl")
+	require.NoError(t, err)
+
+	var adv types.Advisory
+	for _, a := range advisories {
+		if a.VulnerabilityID == "CVE-2016-2105" {
+			adv = a
+		}
+	}
+	require.NotEmpty(t, adv.VulnerableVersions)
+
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "1.0.2", want: true},
+		{version: "1.0.2g", want: true},
+		{version: "1.0.2h", want: false},
+		{version: "1.0.2za", want: false},
+		{version: "1.0.1s", want: true},
+		{version: "1.0.1t", want: false},
+		{version: "1.0.0z", want: false},
+		{version: "1.1.0", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got := vulnerability.IsVulnerable(vulnerability.OpenSSLVersion, adv, tt.version)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,60 @@
+package openssl
+
+// Record is a CVE JSON 5.0 record in vulnerabilities.json, holding the fields we use
+// Ref. https://github.com/CVEProject/cve-schema/blob/master/schema/v5.0/CVE_JSON_5.0_schema.json
+type Record struct {
+	CveMetadata CveMetadata `json:"cveMetadata"`
+	Containers  Containers  `json:"containers"`
+}
+
+type CveMetadata struct {
+	CveID         string `json:"cveId"`
+	DatePublished string `json:"datePublished"`
+}
+
+type Containers struct {
+	Cna Cna `json:"cna"`
+}
+
+type Cna struct {
+	Title        string        `json:"title"`
+	Descriptions []Description `json:"descriptions"`
+	Metrics      []Metric      `json:"metrics"`
+	References   []Reference   `json:"references"`
+	Affected     []Affected    `json:"affected"`
+}
+
+type Description struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+// Metric holds the OpenSSL severity, e.g. {"format": "other", "other": {"content": {"text": "Low"}}}
+type Metric struct {
+	Format string `json:"format"`
+	Other  struct {
+		Type    string `json:"type"`
+		Content struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"other"`
+}
+
+type Reference struct {
+	URL string `json:"url"`
+}
+
+type Affected struct {
+	Vendor   string    `json:"vendor"`
+	Product  string    `json:"product"`
+	Versions []Version `json:"versions"`
+}
+
+// Version is a range such as {"version": "1.0.2", "lessThan": "1.0.2h", "status": "affected"}
+type Version struct {
+	Version         string `json:"version"`
+	LessThan        string `json:"lessThan"`
+	LessThanOrEqual string `json:"lessThanOrEqual"`
+	Status          string `json:"status"`
+	VersionType     string `json:"versionType"`
+}
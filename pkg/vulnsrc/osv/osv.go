@@ -1,6 +1,7 @@
 package osv
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -56,20 +57,46 @@ type ecosystem struct {
 	dataSource types.DataSource
 }
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config, e.g. to cap the number of references.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+		src.config = dbc
+	}
+}
+
 type VulnSrc struct {
-	dbc db.Operation
+	dbc    db.Operation
+	config db.Config
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := &VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(src)
+	}
+
+	return *src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return sourceID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	vs.config = vs.config.WithContext(ctx)
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	for _, eco := range ecosystems {
 		log.Printf("    Updating Open Source Vulnerability %s", eco.name)
@@ -132,16 +159,20 @@ func (vs VulnSrc) commit(tx *bolt.Tx, eco ecosystem, entry Entry) error {
 		vulnIDs = []string{entry.ID}
 	}
 
-	var references []string
+	var refs []db.Reference
 	for _, ref := range entry.References {
-		references = append(references, ref.URL)
+		refs = append(refs, db.Reference{Type: ref.Type, URL: ref.URL})
 	}
+	references := vs.config.SelectReferences(refs)
 
 	for _, affected := range entry.Affected {
 		pkgName := vulnerability.NormalizePkgName(eco.name, affected.Package.Name)
-		var patchedVersions, vulnerableVersions []string
+		var patchedVersions, vulnerableVersions, introducedVersions []string
+		var gitRanges []types.GitRange
 		for _, affects := range affected.Ranges {
+			// Commits are kept apart from versions
 			if affects.Type == osv.TypeGit {
+				gitRanges = append(gitRanges, toGitRanges(affects.Events)...)
 				continue
 			}
 
@@ -154,6 +185,7 @@ func (vs VulnSrc) commit(tx *bolt.Tx, eco ecosystem, entry Entry) error {
 						vulnerableVersions = append(vulnerableVersions, vulnerable)
 					}
 					vulnerable = fmt.Sprintf(">=%s", event.Introduced)
+					introducedVersions = append(introducedVersions, event.Introduced)
 				case event.Fixed != "":
 					// patched versions
 					patchedVersions = append(patchedVersions, event.Fixed)
@@ -167,9 +199,23 @@ func (vs VulnSrc) commit(tx *bolt.Tx, eco ecosystem, entry Entry) error {
 			}
 		}
 
+		// e.g. {"introduced": "0"} without any fixed version
+		if len(patchedVersions) == 0 && allZeroVersions(introducedVersions) {
+			vulnerableVersions = []string{types.AllVersions}
+		}
+
 		advisory := types.Advisory{
 			VulnerableVersions: vulnerableVersions,
 			PatchedVersions:    patchedVersions,
+			GitRanges:          gitRanges,
+			AffectedSymbols:    affected.EcosystemSpecific.AffectedSymbols(),
+			FetchedAt:          vs.config.FetchedAt(),
+		}
+
+		// The introduced version is unambiguous only when it is the only one.
+		// "0" means all versions before the fixed version are affected.
+		if len(introducedVersions) == 1 && !isZeroVersion(introducedVersions[0]) {
+			advisory.IntroducedVersion = introducedVersions[0]
 		}
 
 		for _, vulnID := range vulnIDs {
@@ -194,9 +240,50 @@ func (vs VulnSrc) commit(tx *bolt.Tx, eco ecosystem, entry Entry) error {
 			return xerrors.Errorf("failed to put vulnerability id (%s): %w", vulnID, err)
 		}
 	}
+
+	// e.g. PYSEC-2021-63 => CVE-2021-28957 and GHSA-jq4v-f5q6-mjqq
+	for _, alias := range entry.Aliases {
+		if err := vs.dbc.PutVulnerabilityAlias(tx, entry.ID, alias); err != nil {
+			return xerrors.Errorf("failed to put vulnerability alias (%s): %w", alias, err)
+		}
+	}
 	return nil
 }
 
+// toGitRanges pairs the introduced and fixed commits of the events,
+// e.g. {"introduced": "0"}, {"fixed": "43e58ac"} => [{Introduced: "0", Fixed: "43e58ac"}]
+func toGitRanges(events []osv.RangeEvent) []types.GitRange {
+	var ranges []types.GitRange
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			ranges = append(ranges, types.GitRange{Introduced: event.Introduced})
+		case event.Fixed != "":
+			// A fixed commit without the introduced one affects all commits before it
+			if len(ranges) == 0 || ranges[len(ranges)-1].Fixed != "" {
+				ranges = append(ranges, types.GitRange{Introduced: "0"})
+			}
+			ranges[len(ranges)-1].Fixed = event.Fixed
+		}
+	}
+	return ranges
+}
+
+func isZeroVersion(ver string) bool {
+	// e.g. "0", "0.0.0-0"
+	return ver == "0" || ver == "0.0.0-0"
+}
+
+// allZeroVersions returns true if there is at least one version and all of them are zero
+func allZeroVersions(vers []string) bool {
+	for _, ver := range vers {
+		if !isZeroVersion(ver) {
+			return false
+		}
+	}
+	return len(vers) > 0
+}
+
 func filterCveIDs(aliases []string) []string {
 	var cveIDs []string
 	for _, a := range aliases {
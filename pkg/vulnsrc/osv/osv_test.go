@@ -1,6 +1,7 @@
 package osv
 
 import (
+	"encoding/json"
 	"path/filepath"
 	"testing"
 
@@ -49,6 +50,12 @@ func TestVulnSrc_Update(t *testing.T) {
 					value: types.Advisory{
 						VulnerableVersions: []string{">=0, <1.4.1"},
 						PatchedVersions:    []string{"1.4.1"},
+						GitRanges: []types.GitRange{
+							{
+								Introduced: "0",
+								Fixed:      "43e58ac865ff862c2008c510fc5f7627e10b4660",
+							},
+						},
 					},
 				},
 				{
@@ -82,6 +89,40 @@ func TestVulnSrc_Update(t *testing.T) {
 						},
 					},
 				},
+				{
+					key: []string{"advisory-detail", "CVE-2019-15551", "cargo::Open Source Vulnerability", "smallvec"},
+					value: types.Advisory{
+						VulnerableVersions: []string{">=0.6.5, <0.6.10"},
+						PatchedVersions:    []string{"0.6.10"},
+						IntroducedVersion:  "0.6.5",
+						AffectedSymbols:    []string{"smallvec::SmallVec::grow"},
+					},
+				},
+				{
+					key: []string{"vulnerability-detail", "CVE-2019-15551", string(vulnerability.OSV)},
+					value: types.VulnerabilityDetail{
+						Title:       "Double-free and use-after-free in SmallVec::grow()",
+						Description: "Attempting to call `grow` on a spilled SmallVec with a value equal to the current capacity causes it to free the existing data.",
+						References: []string{
+							"https://crates.io/crates/smallvec",
+							"https://rustsec.org/advisories/RUSTSEC-2019-0009.html",
+						},
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2021-28363", "pip::Open Source Vulnerability", "urllib3"},
+					value: types.Advisory{
+						VulnerableVersions: []string{">=1.26.0, <1.26.4"},
+						PatchedVersions:    []string{"1.26.4"},
+						IntroducedVersion:  "1.26.0",
+					},
+				},
+				{
+					key: []string{"advisory-detail", "CVE-2022-0845", "pip::Open Source Vulnerability", "pytorch-lightning"},
+					value: types.Advisory{
+						VulnerableVersions: []string{types.AllVersions},
+					},
+				},
 				{
 					key:   []string{"advisory-detail", "CVE-2021-40829"}, // skip GHSA-id
 					value: nil,
@@ -102,6 +143,18 @@ func TestVulnSrc_Update(t *testing.T) {
 					key:   []string{"vulnerability-id", "CVE-2021-40829"}, // skip GHSA-id
 					value: nil,
 				},
+				{
+					key:   []string{"vulnerability-alias", "RUSTSEC-2019-0009"},
+					value: "CVE-2019-15551",
+				},
+				{
+					key:   []string{"vulnerability-alias", "PYSEC-2018-27"},
+					value: "CVE-2018-10895",
+				},
+				{
+					key:   []string{"vulnerability-alias", "GHSA-wgmx-52ph-qqcw"},
+					value: "CVE-2018-10895",
+				},
 			},
 		},
 		{
@@ -142,3 +195,34 @@ func TestVulnSrc_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestEcosystemSpecific_AffectedSymbols(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "RustSec functions",
+			input: `{"affects": {"functions": ["smallvec::SmallVec::grow"], "arch": [], "os": []}}`,
+			want:  []string{"smallvec::SmallVec::grow"},
+		},
+		{
+			name:  "Go imports",
+			input: `{"imports": [{"path": "golang.org/x/text/language", "symbols": ["Parse", "MatchStrings"]}]}`,
+			want:  []string{"golang.org/x/text/language.Parse", "golang.org/x/text/language.MatchStrings"},
+		},
+		{
+			name:  "no symbols",
+			input: `{"affects": {"functions": []}}`,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e EcosystemSpecific
+			require.NoError(t, json.Unmarshal([]byte(tt.input), &e))
+			assert.Equal(t, tt.want, e.AffectedSymbols())
+		})
+	}
+}
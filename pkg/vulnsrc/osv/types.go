@@ -8,5 +8,47 @@ type Entry struct {
 	// https://ossf.github.io/osv-schema/
 	Summary string `json:"summary"`
 
+	// Affected shadows the one of osv.Entry to decode the symbols in the formats of the ecosystems
+	Affected []Affected `json:"affected"`
+
 	osv.Entry
 }
+
+type Affected struct {
+	EcosystemSpecific EcosystemSpecific `json:"ecosystem_specific"`
+
+	osv.Affected
+}
+
+// EcosystemSpecific holds the affected functions and methods, which are given in different formats per ecosystem.
+// https://ossf.github.io/osv-schema/#affectedecosystem_specific-field
+type EcosystemSpecific struct {
+	// e.g. {"affects": {"functions": ["smallvec::SmallVec::grow"]}} in RustSec
+	Affects struct {
+		Functions []string `json:"functions"`
+	} `json:"affects"`
+
+	// e.g. {"imports": [{"path": "golang.org/x/text/language", "symbols": ["Parse"]}]} in the Go vulnerability database
+	Imports []Import `json:"imports"`
+
+	// e.g. {"symbols": ["Parse"]} in the former format of the Go vulnerability database
+	Symbols []string `json:"symbols"`
+}
+
+type Import struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols"`
+}
+
+// AffectedSymbols returns the affected symbols. Symbols of imports are qualified by the import path,
+// e.g. "golang.org/x/text/language.Parse".
+func (e EcosystemSpecific) AffectedSymbols() []string {
+	var symbols []string
+	symbols = append(symbols, e.Affects.Functions...)
+	for _, imp := range e.Imports {
+		for _, symbol := range imp.Symbols {
+			symbols = append(symbols, imp.Path+"."+symbol)
+		}
+	}
+	return append(symbols, e.Symbols...)
+}
@@ -0,0 +1,57 @@
+package pluginref
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
+)
+
+// Load reads the CSV file mapping vulnerability IDs to the plugin IDs of vulnerability scanners such as
+// Tenable and Qualys, and returns the plugin IDs keyed by the vulnerability ID and the scanner name.
+// Each row is "<vulnerability ID>,<scanner>,<plugin ID>", e.g. "CVE-2014-7205,tenable,78888".
+// The header row starting with "cve" is skipped.
+func Load(path string) (map[string]map[string][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 3
+	r.TrimLeadingSpace = true
+
+	refs := map[string]map[string][]string{}
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, xerrors.Errorf("CSV read error: %w", err)
+		}
+
+		vulnID, scanner, pluginID := record[0], strings.ToLower(record[1]), record[2]
+		if line == 1 && strings.EqualFold(vulnID, "cve") {
+			continue
+		} else if vulnID == "" || scanner == "" || pluginID == "" {
+			return nil, xerrors.Errorf("empty field in line %d of %s", line, path)
+		}
+
+		if refs[vulnID] == nil {
+			refs[vulnID] = map[string][]string{}
+		}
+		refs[vulnID][scanner] = append(refs[vulnID][scanner], pluginID)
+	}
+
+	for _, scanners := range refs {
+		for scanner, pluginIDs := range scanners {
+			scanners[scanner] = ustrings.Unique(pluginIDs)
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,55 @@
+package pluginref_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/pluginref"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    map[string]map[string][]string
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			path: "testdata/plugins.csv",
+			want: map[string]map[string][]string{
+				"CVE-2014-7205": {
+					"qualys":  {"370115"},
+					"tenable": {"78888"},
+				},
+				"CVE-2018-16487": {
+					"tenable": {"119880", "125012"},
+				},
+			},
+		},
+		{
+			name:    "wrong number of fields",
+			path:    "testdata/invalid.csv",
+			wantErr: "wrong number of fields",
+		},
+		{
+			name:    "missing file",
+			path:    "testdata/missing.csv",
+			wantErr: "file open error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pluginref.Load(tt.path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
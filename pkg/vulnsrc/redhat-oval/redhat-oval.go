@@ -1,6 +1,7 @@
 package redhatoval
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -38,20 +39,43 @@ var (
 	}
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return vulnerability.RedHatOVAL
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	uniqCPEs := CPEMap{}
 
@@ -250,6 +274,12 @@ func (vs VulnSrc) Get(pkgName string, repositories, nvrs []string) ([]types.Advi
 		return nil, xerrors.Errorf("unable to iterate advisories: %w", err)
 	}
 
+	// e.g. "nodejs:12::npm" => "nodejs:12"
+	var module string
+	if i := strings.Index(pkgName, "::"); i > 0 {
+		module = pkgName[:i]
+	}
+
 	var advisories []types.Advisory
 	for vulnID, v := range rawAdvisories {
 		if len(v.Content) == 0 {
@@ -270,6 +300,7 @@ func (vs VulnSrc) Get(pkgName string, repositories, nvrs []string) ([]types.Advi
 				advisory := types.Advisory{
 					Severity:     cve.Severity,
 					FixedVersion: entry.FixedVersion,
+					Module:       module,
 				}
 
 				if strings.HasPrefix(vulnID, "CVE-") {
@@ -287,6 +287,32 @@ func TestVulnSrc_Get(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "modular package",
+			args: args{
+				pkgName: "nodejs:12::nodejs",
+				nvrs:    []string{"ubi8-init-container-8.0-7-x86_64"},
+			},
+			fixtures: []string{"testdata/fixtures/happy.yaml", "testdata/fixtures/cpe.yaml"},
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2020-8265",
+					VendorIDs:       []string{"RHSA-2021:0548"},
+					Severity:        types.SeverityHigh,
+					FixedVersion:    "1:12.20.1-1.module+el8.3.0+9503+19cb079c",
+					Module:          "nodejs:12",
+				},
+			},
+		},
+		{
+			name: "non-modular package",
+			args: args{
+				pkgName: "nodejs",
+				nvrs:    []string{"ubi8-init-container-8.0-7-x86_64"},
+			},
+			fixtures: []string{"testdata/fixtures/happy.yaml", "testdata/fixtures/cpe.yaml"},
+			want:     []types.Advisory(nil),
+		},
 		{
 			name: "no CPE match",
 			args: args{
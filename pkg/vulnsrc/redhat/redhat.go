@@ -1,6 +1,7 @@
 package redhat
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -23,22 +24,47 @@ const (
 	redhatDir = "redhat"
 
 	resourceURL = "https://access.redhat.com/security/cve/%s"
+
+	notAffected = "Not affected"
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return vulnerability.RedHat
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", redhatDir)
 
@@ -117,6 +143,9 @@ func (vs VulnSrc) commit(tx *bolt.Tx, cves []RedhatCVE) error {
 		if err := vs.putVulnerabilityDetail(tx, cve); err != nil {
 			return err
 		}
+		if err := vs.putNotAffected(tx, cve); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -149,6 +178,32 @@ func (vs VulnSrc) putVulnerabilityDetail(tx *bolt.Tx, cve RedhatCVE) error {
 	return nil
 }
 
+// putNotAffected stores "Not affected" package states per product, e.g. "Red Hat Enterprise Linux 8",
+// so that scanners can suppress the vulnerability for the package.
+func (vs VulnSrc) putNotAffected(tx *bolt.Tx, cve RedhatCVE) error {
+	for _, ps := range cve.PackageState {
+		if ps.PackageName == "" || ps.ProductName == "" || ps.FixState != notAffected {
+			continue
+		}
+		advisory := types.Advisory{
+			Status: types.StatusNotAffected,
+		}
+		if err := vs.dbc.PutAdvisoryDetail(tx, cve.Name, ps.PackageName, []string{ps.ProductName}, advisory); err != nil {
+			return xerrors.Errorf("failed to save Red Hat not-affected statement: %w", err)
+		}
+	}
+	return nil
+}
+
+// Get returns advisories stored for the product, including not-affected statements.
+func (vs VulnSrc) Get(productName, pkgName string) ([]types.Advisory, error) {
+	advisories, err := vs.dbc.GetAdvisories(productName, pkgName)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get Red Hat advisories: %w", err)
+	}
+	return advisories, nil
+}
+
 func severityFromThreat(sev string) types.Severity {
 	switch strings.Title(sev) {
 	case "Low":
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	bolt "go.etcd.io/bbolt"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
@@ -154,6 +155,7 @@ func TestVulnSrc_Commit(t *testing.T) {
 	testCases := []struct {
 		name                   string
 		cves                   []RedhatCVE
+		putAdvisoryDetail      []db.OperationPutAdvisoryDetailExpectation
 		putVulnerabilityDetail []db.OperationPutVulnerabilityDetailExpectation
 		putVulnerabilityID     []db.OperationPutVulnerabilityIDExpectation
 		expectedErrorMsg       string
@@ -211,6 +213,65 @@ func TestVulnSrc_Commit(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "not affected",
+			cves: []RedhatCVE{
+				{
+					Name: "CVE-2019-8559",
+					PackageState: []RedhatPackageState{
+						{
+							PackageName: "webkitgtk3",
+							ProductName: "Red Hat Enterprise Linux 7",
+							FixState:    "Affected",
+						},
+						{
+							PackageName: "webkit2gtk3",
+							ProductName: "Red Hat Enterprise Linux 8",
+							FixState:    "Not affected",
+						},
+					},
+					ThreatSeverity: "Moderate",
+					Bugzilla:       RedhatBugzilla{Description: "CVE-2019-8559 webkitgtk: title"},
+				},
+			},
+			putAdvisoryDetail: []db.OperationPutAdvisoryDetailExpectation{
+				{
+					Args: db.OperationPutAdvisoryDetailArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2019-8559",
+						PkgName:         "webkit2gtk3",
+						NestedBktNames:  []string{"Red Hat Enterprise Linux 8"},
+						Advisory: types.Advisory{
+							Status: types.StatusNotAffected,
+						},
+					},
+				},
+			},
+			putVulnerabilityDetail: []db.OperationPutVulnerabilityDetailExpectation{
+				{
+					Args: db.OperationPutVulnerabilityDetailArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2019-8559",
+						Source:          vulnerability.RedHat,
+						Vulnerability: types.VulnerabilityDetail{
+							Severity: types.SeverityMedium,
+							Title:    "webkitgtk: title",
+							References: []string{
+								"https://access.redhat.com/security/cve/CVE-2019-8559",
+							},
+						},
+					},
+				},
+			},
+			putVulnerabilityID: []db.OperationPutVulnerabilityIDExpectation{
+				{
+					Args: db.OperationPutVulnerabilityIDArgs{
+						TxAnything:      true,
+						VulnerabilityID: "CVE-2019-8559",
+					},
+				},
+			},
+		},
 		{
 			name: "empty package name",
 			cves: []RedhatCVE{
@@ -445,6 +506,7 @@ func TestVulnSrc_Commit(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tx := &bolt.Tx{}
 			mockDBConfig := new(db.MockOperation)
+			mockDBConfig.ApplyPutAdvisoryDetailExpectations(tc.putAdvisoryDetail)
 			mockDBConfig.ApplyPutVulnerabilityDetailExpectations(tc.putVulnerabilityDetail)
 			mockDBConfig.ApplyPutVulnerabilityIDExpectations(tc.putVulnerabilityID)
 
@@ -461,3 +523,31 @@ func TestVulnSrc_Commit(t *testing.T) {
 		})
 	}
 }
+
+func TestVulnSrc_Get(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, db.Init(dir))
+	defer db.Close()
+
+	vs := NewVulnSrc()
+	require.NoError(t, vs.Update(filepath.Join("testdata", "happy3")))
+	err := db.Config{}.BatchUpdate(func(tx *bolt.Tx) error {
+		return db.Config{}.SaveAdvisoryDetails(tx, "CVE-2019-8559")
+	})
+	require.NoError(t, err)
+
+	got, err := vs.Get("Red Hat Enterprise Linux 8", "webkit2gtk3")
+	require.NoError(t, err)
+	want := []types.Advisory{
+		{
+			VulnerabilityID: "CVE-2019-8559",
+			Status:          types.StatusNotAffected,
+		},
+	}
+	assert.Equal(t, want, got)
+
+	// "Affected" is not a not-affected statement
+	got, err = vs.Get("Red Hat Enterprise Linux 7", "webkitgtk3")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
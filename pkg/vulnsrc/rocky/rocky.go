@@ -1,6 +1,7 @@
 package rocky
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -33,20 +34,43 @@ var (
 	}
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dbc db.Operation
 }
 
-func NewVulnSrc() VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dbc: db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	rootDir := filepath.Join(dir, "vuln-list", rockyDir)
 	errata := map[string][]RLSA{}
@@ -133,15 +157,18 @@ func (vs VulnSrc) commit(tx *bolt.Tx, platformName string, errata []RLSA) error
 
 			if putAdvisoryCount > 0 {
 				var references []string
+				var refs []db.Reference
 				for _, ref := range erratum.References {
 					references = append(references, ref.Href)
+					refs = append(refs, db.Reference{Type: ref.Type, URL: ref.Href, Title: ref.Title})
 				}
 
 				vuln := types.VulnerabilityDetail{
-					Severity:    generalizeSeverity(erratum.Severity),
-					References:  references,
-					Title:       erratum.Title,
-					Description: erratum.Description,
+					Severity:        generalizeSeverity(erratum.Severity),
+					References:      references,
+					ReferenceTitles: db.ReferenceTitles(refs),
+					Title:           erratum.Title,
+					Description:     erratum.Description,
 				}
 				if err := vs.dbc.PutVulnerabilityDetail(tx, cveID, source.ID, vuln); err != nil {
 					return xerrors.Errorf("failed to save Rocky vulnerability: %w", err)
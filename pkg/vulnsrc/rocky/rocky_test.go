@@ -55,6 +55,9 @@ func TestVulnSrc_Update(t *testing.T) {
 						References: []string{
 							"https://access.redhat.com/hydra/rest/securitydata/cve/CVE-2021-25215.json",
 						},
+						ReferenceTitles: map[string]string{
+							"https://access.redhat.com/hydra/rest/securitydata/cve/CVE-2021-25215.json": "Update information for CVE-2021-25215 is retrieved from Red Hat",
+						},
 						Title:       "Important: bind security update",
 						Description: "For more information visit https://errata.rockylinux.org/RLSA-2021:1989",
 					},
@@ -1,6 +1,7 @@
 package susecvrf
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -42,22 +43,45 @@ var (
 	}
 )
 
+type Option func(src *VulnSrc)
+
+// WithDBConfig replaces the default DB config.
+func WithDBConfig(dbc db.Config) Option {
+	return func(src *VulnSrc) {
+		src.dbc = dbc
+	}
+}
+
 type VulnSrc struct {
 	dist Distribution
 	dbc  db.Operation
 }
 
-func NewVulnSrc(dist Distribution) VulnSrc {
-	return VulnSrc{
+func NewVulnSrc(dist Distribution, opts ...Option) VulnSrc {
+	src := VulnSrc{
 		dist: dist,
 		dbc:  db.Config{},
 	}
+
+	for _, o := range opts {
+		o(&src)
+	}
+
+	return src
 }
 
 func (vs VulnSrc) Name() types.SourceID {
 	return source.ID
 }
 
+// UpdateContext is Update whose writes fail once the context is done.
+func (vs VulnSrc) UpdateContext(ctx context.Context, dir string) error {
+	if dbc, ok := vs.dbc.(db.Config); ok {
+		vs.dbc = dbc.WithContext(ctx)
+	}
+	return vs.Update(dir)
+}
+
 func (vs VulnSrc) Update(dir string) error {
 	log.Println("Saving SUSE CVRF")
 
@@ -124,8 +148,10 @@ func (vs VulnSrc) commit(tx *bolt.Tx, cvrfs []SuseCvrf) error {
 		}
 
 		var references []string
+		var refs []db.Reference
 		for _, ref := range cvrf.References {
 			references = append(references, ref.URL)
+			refs = append(refs, db.Reference{URL: ref.URL, Title: ref.Description})
 		}
 
 		severity := types.SeverityUnknown
@@ -139,10 +165,11 @@ func (vs VulnSrc) commit(tx *bolt.Tx, cvrfs []SuseCvrf) error {
 		}
 
 		vuln := types.VulnerabilityDetail{
-			References:  references,
-			Title:       cvrf.Title,
-			Description: getDetail(cvrf.Notes),
-			Severity:    severity,
+			References:      references,
+			ReferenceTitles: db.ReferenceTitles(refs),
+			Title:           cvrf.Title,
+			Description:     getDetail(cvrf.Notes),
+			Severity:        severity,
 		}
 
 		if err := vs.dbc.PutVulnerabilityDetail(tx, cvrf.Tracking.ID, source.ID, vuln); err != nil {
@@ -197,6 +197,10 @@ func TestVulnSrc_Commit(t *testing.T) {
 								"https://www.suse.com/support/update/announcement/2019/suse-su-20190048-2/",
 								"http://lists.suse.com/pipermail/sle-security-updates/2019-July/005660.html",
 							},
+							ReferenceTitles: map[string]string{
+								"https://www.suse.com/support/update/announcement/2019/suse-su-20190048-2/":  "Link for SUSE-SU-2019:0048-2",
+								"http://lists.suse.com/pipermail/sle-security-updates/2019-July/005660.html": "E-Mail link for SUSE-SU-2019:0048-2",
+							},
 							Severity: types.SeverityHigh,
 						},
 					},
@@ -338,6 +342,10 @@ func TestVulnSrc_Commit(t *testing.T) {
 								"http://lists.opensuse.org/opensuse-security-announce/2019-12/msg00001.html",
 								"https://www.suse.com/support/security/rating/",
 							},
+							ReferenceTitles: map[string]string{
+								"http://lists.opensuse.org/opensuse-security-announce/2019-12/msg00001.html": "E-Mail link for openSUSE-SU-2019:2598-1",
+								"https://www.suse.com/support/security/rating/":                              "SUSE Security Ratings",
+							},
 							Severity: types.SeverityHigh,
 						},
 					},
@@ -460,6 +468,10 @@ func TestVulnSrc_Commit(t *testing.T) {
 								"http://lists.opensuse.org/opensuse-security-announce/2019-01/msg00001.html",
 								"https://www.suse.com/support/security/rating/",
 							},
+							ReferenceTitles: map[string]string{
+								"http://lists.opensuse.org/opensuse-security-announce/2019-01/msg00001.html": "E-Mail link for openSUSE-SU-2019:0003-1",
+								"https://www.suse.com/support/security/rating/":                              "SUSE Security Ratings",
+							},
 							Severity: types.SeverityMedium,
 						},
 					},
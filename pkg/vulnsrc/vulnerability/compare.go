@@ -0,0 +1,279 @@
+package vulnerability
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	debversion "github.com/knqyf263/go-deb-version"
+	rpmversion "github.com/knqyf263/go-rpm-version"
+	"golang.org/x/mod/semver"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+var (
+	// Ref. https://peps.python.org/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions
+	// e.g. "1.0.2", "1.0.2za", "3.0.0-alpha1"
+	openSSLRegexp = regexp.MustCompile(`^(\d+(?:\.\d+)*)([a-z]*)(?:-([0-9a-z.]+))?$`)
+
+	pep440Regexp = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+		`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?` +
+		`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` +
+		`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+		`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+)
+
+// Compare compares two versions in the ecosystem and returns -1, 0 or 1.
+// npm and Go versions follow semver, pip versions follow PEP 440,
+// Rpm versions are "[epoch:]version-release" and Dpkg versions are "[epoch:]upstream-revision".
+// OpenSSL versions may have letter suffixes, e.g. 1.0.2 < 1.0.2a < 1.0.2z < 1.0.2za.
+// The other ecosystems and versions that cannot be parsed fall back to a lexical comparison.
+func Compare(ecosystem types.Ecosystem, a, b string) int {
+	switch ecosystem {
+	case Npm, Go:
+		if c, ok := compareSemver(a, b); ok {
+			return c
+		}
+	case Pip:
+		if c, ok := comparePEP440(a, b); ok {
+			return c
+		}
+	case Rpm:
+		return sign(rpmversion.NewVersion(a).Compare(rpmversion.NewVersion(b)))
+	case OpenSSLVersion:
+		if c, ok := compareOpenSSL(a, b); ok {
+			return c
+		}
+	case Dpkg:
+		v1, err1 := debversion.NewVersion(a)
+		v2, err2 := debversion.NewVersion(b)
+		if err1 == nil && err2 == nil {
+			return sign(v1.Compare(v2))
+		}
+	default:
+		v1, err1 := version.NewVersion(a)
+		v2, err2 := version.NewVersion(b)
+		if err1 == nil && err2 == nil {
+			return v1.Compare(v2)
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// CompareInNamespace compares two versions of a package in the namespace. See NamespaceEcosystem.
+func CompareInNamespace(namespace, a, b string) int {
+	return Compare(NamespaceEcosystem(namespace), a, b)
+}
+
+// NamespaceEcosystem returns the ecosystem of the versions in the namespace,
+// e.g. Npm for "npm::Node.js Ecosystem Security Working Group" and Dpkg for "debian 10".
+// It is empty for the other OS namespaces such as "alpine 3.15".
+func NamespaceEcosystem(namespace string) types.Ecosystem {
+	if i := strings.Index(namespace, "::"); i > 0 {
+		return types.Ecosystem(namespace[:i])
+	}
+	ns := strings.ToLower(namespace)
+	for _, prefix := range []string{"debian ", "ubuntu "} {
+		if strings.HasPrefix(ns, prefix) {
+			return Dpkg
+		}
+	}
+	for _, prefix := range []string{"red hat", "centos", "rocky ", "alma ", "oracle linux ", "amazon linux ",
+		"suse linux enterprise", "opensuse", "cbl-mariner ", "photon os ", "fedora"} {
+		if strings.HasPrefix(ns, prefix) {
+			return Rpm
+		}
+	}
+	return ""
+}
+
+func compareSemver(a, b string) (int, bool) {
+	a, b = canonicalSemver(a), canonicalSemver(b)
+	if !semver.IsValid(a) || !semver.IsValid(b) {
+		return 0, false
+	}
+	return semver.Compare(a, b), true
+}
+
+// canonicalSemver trims the prefixes npm allows, e.g. "=v1.2.3" => "v1.2.3"
+func canonicalSemver(ver string) string {
+	ver = strings.TrimSpace(ver)
+	ver = strings.TrimPrefix(ver, "=")
+	return "v" + strings.TrimPrefix(ver, "v")
+}
+
+type pep440Version struct {
+	epoch   int64
+	release []int64
+	pre     [2]int64 // phase and number
+	post    int64
+	dev     int64
+	local   string
+}
+
+const (
+	pep440DevOnly int64 = iota // e.g. 1.0.dev1, sorted before any pre-release
+	pep440Alpha
+	pep440Beta
+	pep440RC
+	pep440Final
+
+	pep440None int64 = -1
+	pep440Max  int64 = 1<<63 - 1
+)
+
+func parsePEP440(ver string) (pep440Version, bool) {
+	m := pep440Regexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(ver)))
+	if m == nil {
+		return pep440Version{}, false
+	}
+
+	v := pep440Version{
+		pre:   [2]int64{pep440Final, 0},
+		post:  pep440None,
+		dev:   pep440Max,
+		local: m[10],
+	}
+
+	var ok bool
+	if v.epoch, ok = parseInt(m[1]); !ok {
+		return pep440Version{}, false
+	}
+	for _, s := range strings.Split(m[2], ".") {
+		n, ok := parseInt(s)
+		if !ok {
+			return pep440Version{}, false
+		}
+		v.release = append(v.release, n)
+	}
+
+	if m[3] != "" {
+		switch m[3] {
+		case "a", "alpha":
+			v.pre[0] = pep440Alpha
+		case "b", "beta":
+			v.pre[0] = pep440Beta
+		default:
+			v.pre[0] = pep440RC
+		}
+		if v.pre[1], ok = parseInt(m[4]); !ok {
+			return pep440Version{}, false
+		}
+	}
+
+	// "1.0-1" and "1.0.post1" are the same
+	if m[5] != "" || m[6] != "" {
+		if v.post, ok = parseInt(m[5] + m[7]); !ok {
+			return pep440Version{}, false
+		}
+	}
+
+	if m[8] != "" {
+		if v.dev, ok = parseInt(m[9]); !ok {
+			return pep440Version{}, false
+		}
+		if m[3] == "" && v.post == pep440None {
+			v.pre[0] = pep440DevOnly
+		}
+	}
+	return v, true
+}
+
+func parseInt(s string) (int64, bool) {
+	if s == "" {
+		return 0, true
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+func comparePEP440(a, b string) (int, bool) {
+	v1, ok1 := parsePEP440(a)
+	v2, ok2 := parsePEP440(b)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+
+	if c := compareInt(v1.epoch, v2.epoch); c != 0 {
+		return c, true
+	}
+	// Missing segments are zeros, e.g. 1.0 == 1.0.0
+	for i := 0; i < len(v1.release) || i < len(v2.release); i++ {
+		var n1, n2 int64
+		if i < len(v1.release) {
+			n1 = v1.release[i]
+		}
+		if i < len(v2.release) {
+			n2 = v2.release[i]
+		}
+		if c := compareInt(n1, n2); c != 0 {
+			return c, true
+		}
+	}
+	for _, pair := range [][2]int64{{v1.pre[0], v2.pre[0]}, {v1.pre[1], v2.pre[1]}, {v1.post, v2.post}, {v1.dev, v2.dev}} {
+		if c := compareInt(pair[0], pair[1]); c != 0 {
+			return c, true
+		}
+	}
+	// A local version is newer than the public one, e.g. 1.0+ubuntu1 > 1.0
+	return strings.Compare(v1.local, v2.local), true
+}
+
+func compareOpenSSL(a, b string) (int, bool) {
+	m1 := openSSLRegexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(a)))
+	m2 := openSSLRegexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(b)))
+	if m1 == nil || m2 == nil {
+		return 0, false
+	}
+
+	// Missing segments are zeros, e.g. 3.0 == 3.0.0
+	r1, r2 := strings.Split(m1[1], "."), strings.Split(m2[1], ".")
+	for i := 0; i < len(r1) || i < len(r2); i++ {
+		var n1, n2 int64
+		if i < len(r1) {
+			n1, _ = parseInt(r1[i])
+		}
+		if i < len(r2) {
+			n2, _ = parseInt(r2[i])
+		}
+		if c := compareInt(n1, n2); c != 0 {
+			return c, true
+		}
+	}
+
+	// Letters run from "a" to "z", then "za" to "zz"
+	if c := compareInt(int64(len(m1[2])), int64(len(m2[2]))); c != 0 {
+		return c, true
+	}
+	if c := strings.Compare(m1[2], m2[2]); c != 0 {
+		return c, true
+	}
+
+	// A pre-release is older than the release, e.g. 3.0.0-alpha1 < 3.0.0
+	switch {
+	case m1[3] == m2[3]:
+		return 0, true
+	case m1[3] == "":
+		return 1, true
+	case m2[3] == "":
+		return -1, true
+	}
+	return strings.Compare(m1[3], m2[3]), true
+}
+
+// sign normalizes the result of the version libraries, e.g. go-deb-version returns the difference
+func sign(c int) int {
+	return compareInt(int64(c), 0)
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package vulnerability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		ecosystem types.Ecosystem
+		a         string
+		b         string
+		want      int
+	}{
+		// npm
+		{name: "npm less", ecosystem: Npm, a: "1.2.3", b: "1.10.0", want: -1},
+		{name: "npm equal with prefix", ecosystem: Npm, a: "v1.2.3", b: "=1.2.3", want: 0},
+		{name: "npm pre-release", ecosystem: Npm, a: "1.0.0-beta.2", b: "1.0.0", want: -1},
+		{name: "npm build metadata", ecosystem: Npm, a: "1.0.0+build.1", b: "1.0.0", want: 0},
+		{name: "npm all versions", ecosystem: Npm, a: "99.999.99999", b: "5.2.1", want: 1},
+		{name: "npm all versions equal", ecosystem: Npm, a: "99.999.99999", b: "99.999.99999", want: 0},
+		{name: "npm invalid", ecosystem: Npm, a: "latest", b: "next", want: -1},
+
+		// pip
+		{name: "pip less", ecosystem: Pip, a: "1.9", b: "1.10", want: -1},
+		{name: "pip trailing zeros", ecosystem: Pip, a: "1.0", b: "1.0.0", want: 0},
+		{name: "pip pre-release", ecosystem: Pip, a: "1.0rc1", b: "1.0", want: -1},
+		{name: "pip alpha < beta", ecosystem: Pip, a: "1.0a2", b: "1.0b1", want: -1},
+		{name: "pip dev < alpha", ecosystem: Pip, a: "1.0.dev1", b: "1.0a1", want: -1},
+		{name: "pip post release", ecosystem: Pip, a: "1.0.post1", b: "1.0", want: 1},
+		{name: "pip implicit post release", ecosystem: Pip, a: "1.0-1", b: "1.0.post1", want: 0},
+		{name: "pip epoch", ecosystem: Pip, a: "1!0.1", b: "2.0", want: 1},
+		{name: "pip local version", ecosystem: Pip, a: "1.0+local.1", b: "1.0", want: 1},
+		{name: "pip normalized spelling", ecosystem: Pip, a: "1.0-Alpha.1", b: "1.0a1", want: 0},
+
+		// RPM
+		{name: "rpm release", ecosystem: Rpm, a: "2.17-317.el7", b: "2.17-322.el7_9", want: -1},
+		{name: "rpm epoch", ecosystem: Rpm, a: "1:1.0-1", b: "2.0-1", want: 1},
+		{name: "rpm tilde", ecosystem: Rpm, a: "1.0~rc1-1", b: "1.0-1", want: -1},
+
+		// dpkg
+		{name: "dpkg revision", ecosystem: Dpkg, a: "1.8.4-5+deb10u1", b: "1.8.4-5", want: 1},
+		{name: "dpkg epoch", ecosystem: Dpkg, a: "2:8.39-12", b: "8.44-1", want: 1},
+		{name: "dpkg tilde", ecosystem: Dpkg, a: "1.0~rc1-1", b: "1.0-1", want: -1},
+
+		// OpenSSL
+		{name: "openssl letter", ecosystem: OpenSSLVersion, a: "1.0.2", b: "1.0.2a", want: -1},
+		{name: "openssl letters", ecosystem: OpenSSLVersion, a: "1.0.2g", b: "1.0.2h", want: -1},
+		{name: "openssl double letters", ecosystem: OpenSSLVersion, a: "1.0.2z", b: "1.0.2za", want: -1},
+		{name: "openssl letter and minor", ecosystem: OpenSSLVersion, a: "1.0.2zh", b: "1.1.0", want: -1},
+		{name: "openssl 3", ecosystem: OpenSSLVersion, a: "3.0.10", b: "3.0.7", want: 1},
+		{name: "openssl pre-release", ecosystem: OpenSSLVersion, a: "3.0.0-alpha1", b: "3.0.0", want: -1},
+		{name: "openssl equal", ecosystem: OpenSSLVersion, a: "1.1.1W", b: "1.1.1w", want: 0},
+
+		// others
+		{name: "rubygems", ecosystem: RubyGems, a: "2.0.0", b: "10.0.0", want: -1},
+		{name: "unknown format", ecosystem: Maven, a: "abc", b: "abd", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Compare(tt.ecosystem, tt.a, tt.b))
+			assert.Equal(t, -tt.want, Compare(tt.ecosystem, tt.b, tt.a))
+		})
+	}
+}
+
+func TestNamespaceEcosystem(t *testing.T) {
+	tests := []struct {
+		namespace string
+		want      types.Ecosystem
+	}{
+		{namespace: "npm::Node.js Ecosystem Security Working Group", want: Npm},
+		{namespace: "pip::GitHub Security Advisory Pip", want: Pip},
+		{namespace: "debian 10", want: Dpkg},
+		{namespace: "ubuntu 20.04", want: Dpkg},
+		{namespace: "Red Hat Enterprise Linux 8", want: Rpm},
+		{namespace: "amazon linux 2", want: Rpm},
+		{namespace: "alpine 3.15", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			assert.Equal(t, tt.want, NamespaceEcosystem(tt.namespace))
+		})
+	}
+}
@@ -27,6 +27,9 @@ const (
 	GLAD                  types.SourceID = "glad"
 	GoVulnDB              types.SourceID = "go-vulndb"
 	OSV                   types.SourceID = "osv"
+	OpenSSL               types.SourceID = "openssl"
+	AppSec                types.SourceID = "appsec"
+	PluginRefs            types.SourceID = "plugin-refs" // Not a data source, but plugin IDs mapped by users
 
 	// Ecosystem
 	Npm      types.Ecosystem = "npm"
@@ -38,4 +41,14 @@ const (
 	Maven    types.Ecosystem = "maven"
 	Go       types.Ecosystem = "go"
 	Conan    types.Ecosystem = "conan"
+
+	// Package format of OS packages, only for Compare
+	Rpm  types.Ecosystem = "rpm"
+	Dpkg types.Ecosystem = "dpkg"
+
+	// OpenSSLVersion is the version format of OpenSSL with letter suffixes, e.g. "1.0.2za"
+	OpenSSLVersion types.Ecosystem = "openssl"
+
+	// App is the prefix of namespaces of applications such as PostgreSQL, e.g. "app::postgresql"
+	App types.Ecosystem = "app"
 )
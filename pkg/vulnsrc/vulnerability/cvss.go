@@ -0,0 +1,69 @@
+package vulnerability
+
+import (
+	"math"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// CVSS v2 base metric weights
+// Ref. https://www.first.org/cvss/v2/guide#3-2-1-Base-Equation
+var cvssV2Weights = map[string]map[string]float64{
+	"AV": {"L": 0.395, "A": 0.646, "N": 1.0},
+	"AC": {"H": 0.35, "M": 0.61, "L": 0.71},
+	"Au": {"M": 0.45, "S": 0.56, "N": 0.704},
+	"C":  {"N": 0, "P": 0.275, "C": 0.660},
+	"I":  {"N": 0, "P": 0.275, "C": 0.660},
+	"A":  {"N": 0, "P": 0.275, "C": 0.660},
+}
+
+// ParseCVSSv2 returns the base score of the CVSS v2 vector, e.g. "AV:N/AC:L/Au:N/C:P/I:P/A:P" => 7.5.
+// The vector may be wrapped in parentheses. Temporal and environmental metrics are ignored.
+func ParseCVSSv2(vector string) (float64, error) {
+	v := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(vector), "("), ")")
+	if strings.HasPrefix(v, "CVSS:") {
+		// e.g. "CVSS:3.1/AV:N/..."
+		return 0, xerrors.Errorf("not a CVSS v2 vector: %s", vector)
+	}
+
+	metrics := map[string]float64{}
+	for _, m := range strings.Split(v, "/") {
+		key, value, ok := cut(m, ":")
+		if !ok {
+			return 0, xerrors.Errorf("invalid CVSS v2 metric %q in %s", m, vector)
+		}
+		weights, ok := cvssV2Weights[key]
+		if !ok {
+			// e.g. "E:F" and "RL:OF"
+			continue
+		}
+		weight, ok := weights[value]
+		if !ok {
+			return 0, xerrors.Errorf("invalid CVSS v2 value %q in %s", m, vector)
+		}
+		metrics[key] = weight
+	}
+	for key := range cvssV2Weights {
+		if _, ok := metrics[key]; !ok {
+			return 0, xerrors.Errorf("CVSS v2 metric %s is missing in %s", key, vector)
+		}
+	}
+
+	impact := 10.41 * (1 - (1-metrics["C"])*(1-metrics["I"])*(1-metrics["A"]))
+	exploitability := 20 * metrics["AV"] * metrics["AC"] * metrics["Au"]
+	f := 1.176
+	if impact == 0 {
+		f = 0
+	}
+	score := (0.6*impact + 0.4*exploitability - 1.5) * f
+	return math.Round(score*10) / 10, nil
+}
+
+// cut is strings.Cut, which is not available in Go 1.17
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,39 @@
+package vulnerability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCVSSv2(t *testing.T) {
+	tests := []struct {
+		name    string
+		vector  string
+		want    float64
+		wantErr string
+	}{
+		{name: "network", vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P", want: 7.5},
+		{name: "complete", vector: "AV:N/AC:L/Au:N/C:C/I:C/A:C", want: 10.0},
+		{name: "local", vector: "AV:L/AC:M/Au:S/C:N/I:P/A:N", want: 1.5},
+		{name: "no impact", vector: "AV:N/AC:L/Au:N/C:N/I:N/A:N", want: 0},
+		{name: "parentheses and temporal metrics", vector: "(AV:N/AC:M/Au:N/C:P/I:N/A:N/E:F/RL:OF)", want: 4.3},
+		{name: "invalid value", vector: "AV:X/AC:L/Au:N/C:P/I:P/A:P", wantErr: "invalid CVSS v2 value"},
+		{name: "invalid metric", vector: "AV:N/AC", wantErr: "invalid CVSS v2 metric"},
+		{name: "missing metric", vector: "AV:N/AC:L/Au:N/C:P/I:P", wantErr: "CVSS v2 metric A is missing"},
+		{name: "v3", vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:L/I:L/A:N", wantErr: "not a CVSS v2 vector"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCVSSv2(tt.vector)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
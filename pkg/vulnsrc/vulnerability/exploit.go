@@ -0,0 +1,60 @@
+package vulnerability
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// exploitRefPrefixes are the hosts and paths of public exploits, e.g. "https://www.exploit-db.com/exploits/46148"
+var exploitRefPrefixes = []string{
+	"exploit-db.com/exploits/",
+	"www.exploit-db.com/exploits/",
+	"rapid7.com/db/modules/",
+	"www.rapid7.com/db/modules/",
+	"github.com/rapid7/metasploit-framework/",
+	"packetstormsecurity.com/files/",
+}
+
+// IsExploitRef returns true if the reference points to a public exploit in Exploit-DB, Metasploit or Packet Storm
+func IsExploitRef(ref string) bool {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	hostPath := strings.ToLower(u.Host) + u.Path
+	for _, prefix := range exploitRefPrefixes {
+		if strings.HasPrefix(hostPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getExploitRefs returns the sorted exploit references given by the sources in ExploitRefs
+// and those found in References
+func getExploitRefs(details map[types.SourceID]types.VulnerabilityDetail) []string {
+	uniq := map[string]struct{}{}
+	for _, d := range details {
+		for _, ref := range d.ExploitRefs {
+			uniq[strings.TrimSpace(ref)] = struct{}{}
+		}
+		for _, ref := range d.References {
+			if IsExploitRef(ref) {
+				uniq[strings.TrimSpace(ref)] = struct{}{}
+			}
+		}
+	}
+	if len(uniq) == 0 {
+		return nil
+	}
+
+	refs := make([]string, 0, len(uniq))
+	for ref := range uniq {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
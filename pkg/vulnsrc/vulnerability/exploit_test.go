@@ -0,0 +1,66 @@
+package vulnerability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestIsExploitRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{ref: "https://www.exploit-db.com/exploits/46148", want: true},
+		{ref: "http://exploit-db.com/exploits/46148/", want: true},
+		{ref: "https://www.rapid7.com/db/modules/exploit/multi/http/struts2_content_type_ognl", want: true},
+		{ref: "https://github.com/rapid7/metasploit-framework/blob/master/modules/exploits/linux/http/x.rb", want: true},
+		{ref: "http://packetstormsecurity.com/files/154257/example.html", want: true},
+		{ref: "https://www.exploit-db.com/papers/12345"},
+		{ref: "https://github.com/rapid7/other"},
+		{ref: "https://example.com/exploit-db.com/exploits/1"},
+		{ref: "not a url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsExploitRef(tt.ref))
+		})
+	}
+}
+
+func TestNormalizeExploitRefs(t *testing.T) {
+	_ = dbtest.InitDB(t, nil)
+	defer db.Close()
+
+	details := map[types.SourceID]types.VulnerabilityDetail{
+		NVD: {
+			References: []string{
+				"https://www.exploit-db.com/exploits/46148",
+				"https://nvd.nist.gov/vuln/detail/CVE-2019-5736",
+			},
+		},
+		RedHat: {
+			ExploitRefs: []string{"https://github.com/rapid7/metasploit-framework/pull/11321"},
+			References:  []string{"https://www.exploit-db.com/exploits/46148"},
+		},
+	}
+
+	dbc := db.Config{}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.PutVulnerability(tx, "CVE-2019-5736", New(dbc).Normalize(details))
+	})
+	require.NoError(t, err)
+
+	got, err := dbc.GetVulnerability("CVE-2019-5736")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://github.com/rapid7/metasploit-framework/pull/11321",
+		"https://www.exploit-db.com/exploits/46148",
+	}, got.ExploitRefs)
+}
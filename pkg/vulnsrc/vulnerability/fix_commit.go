@@ -0,0 +1,28 @@
+package vulnerability
+
+import (
+	"regexp"
+	"strings"
+)
+
+// fixCommitRegexp matches the URLs of single commits on the common forges, e.g.
+// "https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4",
+// "https://gitlab.com/gitlab-org/gitlab/-/commit/5f9d6e3a" and "https://bitbucket.org/owner/repo/commits/5f9d6e3a"
+var fixCommitRegexp = regexp.MustCompile(
+	`(?i)^https?://(?:www\.)?(?:github\.com/[^/]+/[^/]+/commit|gitlab\.com/.+/-/commit|bitbucket\.org/[^/]+/[^/]+/commits)/[0-9a-f]{7,40}/?(?:[?#].*)?$`)
+
+// IsFixCommitRef returns true if the reference points to a single commit, which usually fixes the vulnerability
+func IsFixCommitRef(ref string) bool {
+	return fixCommitRegexp.MatchString(strings.TrimSpace(ref))
+}
+
+// FixCommits returns the references pointing to commits in the given order
+func FixCommits(refs []string) []string {
+	var commits []string
+	for _, ref := range refs {
+		if IsFixCommitRef(ref) {
+			commits = append(commits, strings.TrimSpace(ref))
+		}
+	}
+	return commits
+}
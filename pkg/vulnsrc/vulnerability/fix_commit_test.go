@@ -0,0 +1,37 @@
+package vulnerability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsFixCommitRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{ref: "https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4", want: true},
+		{ref: "https://github.com/hapijs/bassmaster/commit/b751602/", want: true},
+		{ref: "https://gitlab.com/gitlab-org/gitlab/-/commit/5f9d6e3a0c", want: true},
+		{ref: "https://bitbucket.org/owner/repo/commits/5f9d6e3a0c", want: true},
+		{ref: "https://github.com/hapijs/bassmaster/pull/12"},
+		{ref: "https://github.com/hapijs/bassmaster/commits/master"},
+		{ref: "https://www.npmjs.org/package/bassmaster"},
+		{ref: "https://example.com/github.com/a/b/commit/b751602"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsFixCommitRef(tt.ref))
+		})
+	}
+}
+
+func TestFixCommits(t *testing.T) {
+	got := FixCommits([]string{
+		"https://www.npmjs.org/package/bassmaster",
+		" https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4\n",
+	})
+	assert.Equal(t, []string{"https://github.com/hapijs/bassmaster/commit/b751602d8cb7194ee62a61e085069679525138c4"}, got)
+	assert.Nil(t, FixCommits([]string{"https://www.npmjs.org/package/bassmaster"}))
+}
@@ -0,0 +1,83 @@
+package vulnerability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+var (
+	// e.g. ">=1.2.0, <1.2.5", ">= 1.2.0 < 1.2.5" and "1.2.3"
+	constraintRegexp = regexp.MustCompile(`(>=|<=|!=|==|>|<|=|~>|\^|~)?\s*([^\s,|<>=!~^]+)`)
+)
+
+// IsVulnerable returns true if the installed version is affected by the advisory.
+// An advisory with AllVersions matches any version. Otherwise, VulnerableVersions are used if present,
+// then FixedVersion with IntroducedVersion for OS packages, then PatchedVersions and UnaffectedVersions.
+// An OS advisory without FixedVersion means the vulnerability is not fixed yet, so it matches any version.
+// Constraints with operators other than comparisons, e.g. "~> 1.2", never match.
+// GitRanges are ignored since commits cannot be compared with versions.
+func IsVulnerable(ecosystem types.Ecosystem, adv types.Advisory, installed string) bool {
+	switch {
+	case adv.AffectsAllVersions():
+		return true
+	case len(adv.VulnerableVersions) > 0:
+		return satisfiesAny(ecosystem, installed, adv.VulnerableVersions)
+	case adv.FixedVersion != "":
+		if adv.IntroducedVersion != "" && Compare(ecosystem, installed, adv.IntroducedVersion) < 0 {
+			return false
+		}
+		return Compare(ecosystem, installed, adv.FixedVersion) < 0
+	case len(adv.PatchedVersions) > 0 || len(adv.UnaffectedVersions) > 0:
+		constraints := append(append([]string{}, adv.PatchedVersions...), adv.UnaffectedVersions...)
+		return !satisfiesAny(ecosystem, installed, constraints)
+	}
+	return true
+}
+
+func satisfiesAny(ecosystem types.Ecosystem, ver string, constraints []string) bool {
+	for _, constraint := range constraints {
+		// e.g. "<1.2.0 || >=2.0.0, <2.1.0"
+		for _, c := range strings.Split(constraint, "||") {
+			if satisfies(ecosystem, ver, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// satisfies returns true if the version satisfies all the conditions of the constraint
+func satisfies(ecosystem types.Ecosystem, ver, constraint string) bool {
+	matches := constraintRegexp.FindAllStringSubmatch(constraint, -1)
+	if len(matches) == 0 {
+		return false
+	}
+	for _, m := range matches {
+		op, v := m[1], m[2]
+		if v == types.AllVersions {
+			continue
+		}
+		c := Compare(ecosystem, ver, v)
+		var ok bool
+		switch op {
+		case ">=":
+			ok = c >= 0
+		case ">":
+			ok = c > 0
+		case "<=":
+			ok = c <= 0
+		case "<":
+			ok = c < 0
+		case "", "=", "==":
+			ok = c == 0
+		case "!=":
+			ok = c != 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
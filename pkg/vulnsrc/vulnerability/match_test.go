@@ -0,0 +1,99 @@
+package vulnerability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestIsVulnerable(t *testing.T) {
+	tests := []struct {
+		name      string
+		ecosystem types.Ecosystem
+		adv       types.Advisory
+		installed string
+		want      bool
+	}{
+		{
+			name:      "all versions",
+			ecosystem: Npm,
+			adv:       types.Advisory{VulnerableVersions: []string{types.AllVersions}},
+			installed: "99.999.99999",
+			want:      true,
+		},
+		{
+			name:      "all versions with a non-semver version",
+			ecosystem: Npm,
+			adv:       types.Advisory{VulnerableVersions: []string{types.AllVersions}},
+			installed: "latest",
+			want:      true,
+		},
+		{
+			name:      "vulnerable versions",
+			ecosystem: Npm,
+			adv:       types.Advisory{VulnerableVersions: []string{"<=1.5.1"}},
+			installed: "1.5.0",
+			want:      true,
+		},
+		{
+			name:      "outside vulnerable versions",
+			ecosystem: Npm,
+			adv:       types.Advisory{VulnerableVersions: []string{">=1.2.0, <1.2.5", ">= 2.0.0 < 2.0.3"}},
+			installed: "1.10.0",
+			want:      false,
+		},
+		{
+			name:      "or",
+			ecosystem: Pip,
+			adv:       types.Advisory{VulnerableVersions: []string{"<1.0 || >=2.0, <2.1"}},
+			installed: "2.0.1",
+			want:      true,
+		},
+		{
+			name:      "git ranges ignored",
+			ecosystem: Pip,
+			adv: types.Advisory{
+				VulnerableVersions: []string{">=0, <1.4.1"},
+				GitRanges:          []types.GitRange{{Introduced: "0", Fixed: "43e58ac865ff862c2008c510fc5f7627e10b4660"}},
+			},
+			installed: "1.4.1",
+			want:      false,
+		},
+		{
+			name:      "fixed version",
+			ecosystem: Dpkg,
+			adv:       types.Advisory{FixedVersion: "1.5.2-1"},
+			installed: "1.5.1-3",
+			want:      true,
+		},
+		{
+			name:      "before the introduced version",
+			ecosystem: Dpkg,
+			adv:       types.Advisory{FixedVersion: "1.5.2-1", IntroducedVersion: "1.4.0-1"},
+			installed: "1.3.0-1",
+			want:      false,
+		},
+		{
+			name:      "patched versions",
+			ecosystem: Npm,
+			adv:       types.Advisory{PatchedVersions: []string{">=1.5.2"}},
+			installed: "1.5.2",
+			want:      false,
+		},
+		{
+			name:      "no fix for OS packages",
+			ecosystem: Dpkg,
+			adv:       types.Advisory{},
+			installed: "1.0-1",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsVulnerable(tt.ecosystem, tt.adv, tt.installed)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
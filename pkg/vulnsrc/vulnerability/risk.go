@@ -0,0 +1,57 @@
+package vulnerability
+
+import (
+	"math"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// Scores used when a vendor gives only the severity
+var severityScores = map[types.Severity]float64{
+	types.SeverityCritical: 9.0,
+	types.SeverityHigh:     7.0,
+	types.SeverityMedium:   5.0,
+	types.SeverityLow:      3.0,
+}
+
+// RiskScore combines the CVSS base score, the EPSS probability and the KEV flag into a score from 0 to 100.
+//
+//	RiskScore = 60 * CVSS / 10 + 30 * EPSS + 10 * KEV
+//
+// CVSS v3 is preferred over v2. When no CVSS score is available, the score is derived from the severity,
+// e.g. 9.0 for CRITICAL and 0 for UNKNOWN. KEV is 1 if the vulnerability is known to be exploited.
+// The result is rounded to one decimal place.
+func RiskScore(detail types.VulnerabilityDetail) float64 {
+	cvss := detail.CvssScoreV3
+	if cvss <= 0 {
+		cvss = detail.CvssScore
+	}
+	if cvss <= 0 {
+		severity := detail.SeverityV3
+		if severity == types.SeverityUnknown {
+			severity = detail.Severity
+		}
+		cvss = severityScores[severity]
+	}
+
+	epss := math.Max(0, math.Min(detail.EPSS, 1))
+	var kev float64
+	if detail.KnownExploited {
+		kev = 1
+	}
+
+	score := 60*math.Min(cvss, 10)/10 + 30*epss + 10*kev
+	return math.Round(score*10) / 10
+}
+
+// getRiskScore returns the highest risk score among the details having EPSS or KEV.
+func getRiskScore(details map[types.SourceID]types.VulnerabilityDetail) float64 {
+	var score float64
+	for _, d := range details {
+		if d.EPSS <= 0 && !d.KnownExploited {
+			continue
+		}
+		score = math.Max(score, RiskScore(d))
+	}
+	return score
+}
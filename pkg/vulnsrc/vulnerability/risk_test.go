@@ -0,0 +1,70 @@
+package vulnerability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestRiskScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		detail types.VulnerabilityDetail
+		want   float64
+	}{
+		{
+			name: "KEV and high EPSS",
+			detail: types.VulnerabilityDetail{
+				CvssScoreV3:    10.0,
+				EPSS:           0.97,
+				KnownExploited: true,
+			},
+			want: 99.1,
+		},
+		{
+			name: "CVSS v2 only",
+			detail: types.VulnerabilityDetail{
+				CvssScore: 5.0,
+				EPSS:      0.1,
+			},
+			want: 33,
+		},
+		{
+			name: "severity only",
+			detail: types.VulnerabilityDetail{
+				Severity: types.SeverityHigh,
+			},
+			want: 42,
+		},
+		{
+			name: "unknown severity",
+			detail: types.VulnerabilityDetail{
+				EPSS: 0.01,
+			},
+			want: 0.3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RiskScore(tt.detail))
+		})
+	}
+}
+
+func TestGetRiskScore(t *testing.T) {
+	details := map[types.SourceID]types.VulnerabilityDetail{
+		NVD: {
+			CvssScoreV3: 9.8,
+		},
+		"kev": {
+			CvssScoreV3:    9.8,
+			KnownExploited: true,
+		},
+	}
+	assert.Equal(t, 68.8, getRiskScore(details))
+
+	// Neither EPSS nor KEV
+	assert.Zero(t, getRiskScore(map[types.SourceID]types.VulnerabilityDetail{NVD: {CvssScoreV3: 9.8}}))
+}
@@ -2,11 +2,15 @@ package vulnerability
 
 import (
 	"log"
+	"math"
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-version"
+
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/types"
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
 )
 
 const (
@@ -15,7 +19,7 @@ const (
 
 var (
 	sources = []types.SourceID{NVD, RedHat, Debian, Ubuntu, Alpine, Amazon, OracleOVAL, SuseCVRF, Photon,
-		ArchLinux, Alma, Rocky, CBLMariner, RubySec, PhpSecurityAdvisories, NodejsSecurityWg, GoVulnDB, GHSA, GLAD, OSV,
+		ArchLinux, Alma, Rocky, CBLMariner, RubySec, PhpSecurityAdvisories, NodejsSecurityWg, GoVulnDB, GHSA, GLAD, OSV, OpenSSL,
 	}
 )
 
@@ -43,30 +47,42 @@ func (Vulnerability) IsRejected(details map[types.SourceID]types.VulnerabilityDe
 }
 
 func (Vulnerability) Normalize(details map[types.SourceID]types.VulnerabilityDetail) types.Vulnerability {
+	severity, severitySource := getSeverity(details)
+	references := getReferences(details)
 	return types.Vulnerability{
 		Title:            getTitle(details),
 		Description:      getDescription(details),
-		Severity:         getSeverity(details).String(), // TODO: We have to keep this key until we deprecate
+		Severity:         severity.String(), // TODO: We have to keep this key until we deprecate
+		SeveritySource:   severitySource,
+		SeverityRank:     int(severity),
 		CweIDs:           getCweIDs(details),
 		VendorSeverity:   getVendorSeverity(details),
 		CVSS:             getCVSS(details),
-		References:       getReferences(details),
+		References:       references,
+		ReferenceTitles:  getReferenceTitles(details, references),
 		PublishedDate:    details[NVD].PublishedDate,
 		LastModifiedDate: details[NVD].LastModifiedDate,
+		RiskScore:        getRiskScore(details),
+		ExploitRefs:      getExploitRefs(details),
+		Disputed:         getDisputedStatus(details),
+		PluginRefs:       getPluginRefs(details),
+		ReplacedBy:       getReplacedBy(details),
 	}
 }
 
 func getCVSS(details map[types.SourceID]types.VulnerabilityDetail) types.VendorCVSS {
 	vc := make(types.VendorCVSS)
 	for vendor, detail := range details {
-		if (detail.CvssVector == "" || detail.CvssScore == 0) && (detail.CvssVectorV3 == "" || detail.CvssScoreV3 == 0) {
+		// types.NoCvssScore is left out as well as zero, so that it is never sorted as the lowest score
+		v2Score, v3Score := math.Max(detail.CvssScore, 0), math.Max(detail.CvssScoreV3, 0)
+		if (detail.CvssVector == "" || v2Score == 0) && (detail.CvssVectorV3 == "" || v3Score == 0) {
 			continue
 		}
 		vc[vendor] = types.CVSS{
 			V2Vector: detail.CvssVector,
 			V3Vector: detail.CvssVectorV3,
-			V2Score:  detail.CvssScore,
-			V3Score:  detail.CvssScoreV3,
+			V2Score:  v2Score,
+			V3Score:  v3Score,
 		}
 	}
 	return vc
@@ -89,22 +105,35 @@ func getVendorSeverity(details map[types.SourceID]types.VulnerabilityDetail) typ
 	return vs
 }
 
-func getSeverity(details map[types.SourceID]types.VulnerabilityDetail) types.Severity {
+// getSeverity returns the severity of the first source in priority order having one, and the name of the source.
+// SeveritySource in the detail takes precedence over the source ID.
+func getSeverity(details map[types.SourceID]types.VulnerabilityDetail) (types.Severity, string) {
 	for _, source := range sources {
-		switch d, ok := details[source]; {
-		case !ok:
+		d, ok := details[source]
+		if !ok {
 			continue
+		}
+
+		var severity types.Severity
+		switch {
 		case d.CvssScoreV3 > 0:
-			return scoreToSeverity(d.CvssScoreV3)
+			severity = scoreToSeverity(d.CvssScoreV3)
 		case d.CvssScore > 0:
-			return scoreToSeverity(d.CvssScore)
+			severity = scoreToSeverity(d.CvssScore)
 		case d.SeverityV3 != 0:
-			return d.SeverityV3
+			severity = d.SeverityV3
 		case d.Severity != 0:
-			return d.Severity
+			severity = d.Severity
+		default:
+			continue
+		}
+
+		if d.SeveritySource != "" {
+			return severity, d.SeveritySource
 		}
+		return severity, string(source)
 	}
-	return types.SeverityUnknown
+	return types.SeverityUnknown, ""
 }
 
 func getTitle(details map[types.SourceID]types.VulnerabilityDetail) string {
@@ -147,7 +176,8 @@ func getCweIDs(details map[types.SourceID]types.VulnerabilityDetail) []string {
 }
 
 func getReferences(details map[types.SourceID]types.VulnerabilityDetail) []string {
-	references := map[string]struct{}{}
+	// The key is the reference without the scheme so that "http://x/y" and "https://x/y" are merged.
+	references := map[string]string{}
 	for _, source := range sources {
 		// Amazon contains unrelated references
 		if source == Amazon {
@@ -161,12 +191,17 @@ func getReferences(details map[types.SourceID]types.VulnerabilityDetail) []strin
 			// e.g. "\nhttps://curl.haxx.se/docs/CVE-2019-5481.html\n    "
 			ref = strings.TrimSpace(ref)
 			for _, r := range strings.Split(ref, "\n") {
-				references[r] = struct{}{}
+				key := trimHTTPScheme(r)
+				// Prefer https
+				if existing, ok := references[key]; ok && hasScheme(existing, "https://") {
+					continue
+				}
+				references[key] = r
 			}
 		}
 	}
 	var refs []string
-	for ref := range references {
+	for _, ref := range references {
 		refs = append(refs, ref)
 	}
 	sort.Slice(refs, func(i, j int) bool {
@@ -175,6 +210,19 @@ func getReferences(details map[types.SourceID]types.VulnerabilityDetail) []strin
 	return refs
 }
 
+func trimHTTPScheme(ref string) string {
+	for _, scheme := range []string{"https://", "http://"} {
+		if hasScheme(ref, scheme) {
+			return ref[len(scheme):]
+		}
+	}
+	return ref
+}
+
+func hasScheme(ref, scheme string) bool {
+	return len(ref) >= len(scheme) && strings.EqualFold(ref[:len(scheme)], scheme)
+}
+
 func getRejectedStatus(details map[types.SourceID]types.VulnerabilityDetail) bool {
 	for _, source := range sources {
 		d, ok := details[source]
@@ -188,19 +236,82 @@ func getRejectedStatus(details map[types.SourceID]types.VulnerabilityDetail) boo
 	return false
 }
 
-func scoreToSeverity(score float64) types.Severity {
-	switch {
-	case score >= 9.0:
-		return types.SeverityCritical
-	case score >= 7.0:
-		return types.SeverityHigh
-	case score >= 4.0:
-		return types.SeverityMedium
-	case score > 0.0:
-		return types.SeverityLow
-	default:
-		return types.SeverityUnknown
+// getReferenceTitles returns the titles of the merged references, taken from the first source in priority order having one
+func getReferenceTitles(details map[types.SourceID]types.VulnerabilityDetail, references []string) map[string]string {
+	// The key is the reference without the scheme as getReferences does
+	merged := map[string]string{}
+	for _, ref := range references {
+		merged[trimHTTPScheme(ref)] = ref
 	}
+
+	var titles map[string]string
+	for _, source := range sources {
+		for url, title := range details[source].ReferenceTitles {
+			ref, ok := merged[trimHTTPScheme(strings.TrimSpace(url))]
+			if !ok || title == "" {
+				continue
+			}
+			if _, ok = titles[ref]; ok {
+				continue
+			}
+			if titles == nil {
+				titles = map[string]string{}
+			}
+			titles[ref] = title
+		}
+	}
+	return titles
+}
+
+// getPluginRefs merges the plugin IDs of all the details per scanner
+func getPluginRefs(details map[types.SourceID]types.VulnerabilityDetail) map[string][]string {
+	var refs map[string][]string
+	for _, d := range details {
+		for scanner, pluginIDs := range d.PluginRefs {
+			if refs == nil {
+				refs = map[string][]string{}
+			}
+			refs[scanner] = append(refs[scanner], pluginIDs...)
+		}
+	}
+	for scanner, pluginIDs := range refs {
+		refs[scanner] = ustrings.Unique(pluginIDs)
+	}
+	return refs
+}
+
+func getDisputedStatus(details map[types.SourceID]types.VulnerabilityDetail) bool {
+	for _, d := range details {
+		if d.Disputed {
+			return true
+		}
+	}
+	return false
+}
+
+// getReplacedBy returns the replacement ID of the first source in priority order having one.
+// Sources out of the priority order are taken in name order.
+func getReplacedBy(details map[types.SourceID]types.VulnerabilityDetail) string {
+	for _, source := range sources {
+		if d, ok := details[source]; ok && d.ReplacedBy != "" {
+			return d.ReplacedBy
+		}
+	}
+	var others []string
+	for source, d := range details {
+		if d.ReplacedBy != "" {
+			others = append(others, string(source))
+		}
+	}
+	if len(others) == 0 {
+		return ""
+	}
+	sort.Strings(others)
+	return details[types.SourceID(others[0])].ReplacedBy
+}
+
+func scoreToSeverity(score float64) types.Severity {
+	return types.CvssSeverity(score)
 }
 
 func NormalizePkgName(ecosystem types.Ecosystem, pkgName string) string {
@@ -215,3 +326,61 @@ func NormalizePkgName(ecosystem types.Ecosystem, pkgName string) string {
 	}
 	return pkgName
 }
+
+// NormalizeConstraint canonicalizes the separators of a compound version constraint.
+// e.g. ">=1.0.0 <2.0.0", ">=1.0.0, <2.0.0" and ">= 1.0.0,<2.0.0" become ">=1.0.0 <2.0.0" for npm
+// and ">=1.0.0, <2.0.0" for the other ecosystems.
+// Interval notations such as "[1.0,2.0)" are returned as is since the comma is a part of the range.
+func NormalizeConstraint(ecosystem types.Ecosystem, raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.ContainsAny(raw, "[]()") {
+		return raw
+	}
+
+	sep := ", "
+	if ecosystem == Npm {
+		sep = " "
+	}
+
+	var ors []string
+	for _, or := range strings.Split(raw, "||") {
+		var constraints []string
+		var operator string
+		for _, field := range strings.Fields(strings.ReplaceAll(or, ",", " ")) {
+			// Join an operator separated by spaces with the version, e.g. ">= 1.0.0"
+			if strings.Trim(field, "<>=!~^") == "" {
+				operator += field
+				continue
+			}
+			constraints = append(constraints, operator+field)
+			operator = ""
+		}
+		if operator != "" {
+			constraints = append(constraints, operator)
+		}
+		ors = append(ors, strings.Join(constraints, sep))
+	}
+	return strings.Join(ors, " || ")
+}
+
+// FilterIntroduced removes the advisories whose IntroducedVersion is newer than the package version,
+// since the vulnerability doesn't exist in the package yet.
+// Advisories are kept if the versions cannot be compared.
+func FilterIntroduced(advisories []types.Advisory, pkgVersion string) []types.Advisory {
+	v, err := version.NewVersion(pkgVersion)
+	if err != nil {
+		return advisories
+	}
+
+	var filtered []types.Advisory
+	for _, adv := range advisories {
+		if adv.IntroducedVersion != "" {
+			introduced, err := version.NewVersion(adv.IntroducedVersion)
+			if err == nil && v.LessThan(introduced) {
+				continue
+			}
+		}
+		filtered = append(filtered, adv)
+	}
+	return filtered
+}
@@ -198,6 +198,8 @@ func TestNormalize(t *testing.T) {
 				Title:          "test vulnerability",
 				Description:    "a test vulnerability where vendor rates it lower than NVD",
 				Severity:       types.SeverityMedium.String(),
+				SeveritySource: "nvd",
+				SeverityRank:   int(types.SeverityMedium),
 				VendorSeverity: types.VendorSeverity{"nvd": 2, "redhat": 3},
 				CVSS: types.VendorCVSS{
 					NVD: types.CVSS{
@@ -253,6 +255,8 @@ func TestNormalize(t *testing.T) {
 				Title:          "test vulnerability",
 				Description:    "a test vulnerability where vendor rates it lower than NVD",
 				Severity:       types.SeverityMedium.String(),
+				SeveritySource: "redhat",
+				SeverityRank:   int(types.SeverityMedium),
 				VendorSeverity: types.VendorSeverity{"redhat": 4, "ubuntu": 2},
 				CVSS: types.VendorCVSS{
 					RedHat: types.CVSS{
@@ -296,6 +300,8 @@ func TestNormalize(t *testing.T) {
 			},
 			want: types.Vulnerability{
 				Severity:       types.SeverityMedium.String(),
+				SeveritySource: "redhat",
+				SeverityRank:   int(types.SeverityMedium),
 				VendorSeverity: types.VendorSeverity{"redhat": 2, "ubuntu": 2, "nodejs-security-wg": 4},
 				CVSS:           types.VendorCVSS{},
 				Title:          "test vulnerability",
@@ -327,12 +333,101 @@ func TestNormalize(t *testing.T) {
 			},
 			want: types.Vulnerability{
 				Severity:       types.SeverityMedium.String(),
+				SeveritySource: "ubuntu",
+				SeverityRank:   int(types.SeverityMedium),
 				VendorSeverity: types.VendorSeverity{"ubuntu": 2},
 				CVSS:           types.VendorCVSS{},
 				Title:          "test vulnerability",
 				Description:    "a test vulnerability where vendor rates it lower than NVD",
 			},
 		},
+		{
+			name: "Red Hat severity wins over the unknown severity of nodejs",
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				RedHat: {
+					ID:       "CVE-2020-1234",
+					Severity: types.SeverityHigh,
+					Title:    "test vulnerability",
+				},
+				NodejsSecurityWg: {
+					ID:        "CVE-2020-1234",
+					CvssScore: -1,
+					Title:     "test vulnerability",
+				},
+			},
+			want: types.Vulnerability{
+				Title:          "test vulnerability",
+				Severity:       types.SeverityHigh.String(),
+				SeveritySource: "redhat",
+				SeverityRank:   int(types.SeverityHigh),
+				VendorSeverity: types.VendorSeverity{"redhat": 3},
+				CVSS:           types.VendorCVSS{},
+			},
+		},
+		{
+			name: "the severity source given by the detail",
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				NodejsSecurityWg: {
+					ID:             "CVE-2020-1234",
+					CvssScore:      9.1,
+					SeveritySource: "nodejs-security-wg",
+				},
+			},
+			want: types.Vulnerability{
+				Severity:       types.SeverityCritical.String(),
+				SeveritySource: "nodejs-security-wg",
+				SeverityRank:   int(types.SeverityCritical),
+				VendorSeverity: types.VendorSeverity{"nodejs-security-wg": 4},
+				CVSS:           types.VendorCVSS{},
+			},
+		},
+		{
+			name: "reference titles",
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				NVD: {
+					References: []string{"https://www.suse.com/security/cve/CVE-2018-16873/"},
+				},
+				SuseCVRF: {
+					References: []string{
+						"http://www.suse.com/security/cve/CVE-2018-16873/",
+						"https://www.suse.com/support/security/rating/",
+					},
+					ReferenceTitles: map[string]string{
+						"http://www.suse.com/security/cve/CVE-2018-16873/": "SUSE CVE CVE-2018-16873 page",
+						"https://www.suse.com/support/security/rating/":    "SUSE Security Ratings",
+					},
+				},
+			},
+			want: types.Vulnerability{
+				Severity:       types.SeverityUnknown.String(),
+				VendorSeverity: types.VendorSeverity{},
+				CVSS:           types.VendorCVSS{},
+				References: []string{
+					"https://www.suse.com/security/cve/CVE-2018-16873/",
+					"https://www.suse.com/support/security/rating/",
+				},
+				ReferenceTitles: map[string]string{
+					"https://www.suse.com/security/cve/CVE-2018-16873/": "SUSE CVE CVE-2018-16873 page",
+					"https://www.suse.com/support/security/rating/":     "SUSE Security Ratings",
+				},
+			},
+		},
+		{
+			name: "no score of the Node.js Security WG",
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				NodejsSecurityWg: {
+					ID:         "NSWG-ECO-334",
+					CvssScore:  types.NoCvssScore,
+					CvssVector: "AV:N/AC:L/Au:N/C:N/I:N/A:N",
+				},
+			},
+			want: types.Vulnerability{
+				Severity:       types.SeverityUnknown.String(),
+				SeverityRank:   int(types.SeverityUnknown),
+				VendorSeverity: types.VendorSeverity{},
+				CVSS:           types.VendorCVSS{},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -342,3 +437,199 @@ func TestNormalize(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeNoCvssScoreRank(t *testing.T) {
+	details := map[string]map[types.SourceID]types.VulnerabilityDetail{
+		"NSWG-ECO-334":   {NodejsSecurityWg: {CvssScore: types.NoCvssScore}},
+		"CVE-2020-1234":  {NodejsSecurityWg: {CvssScore: 2.0}},
+		"CVE-2020-12345": {NodejsSecurityWg: {}},
+	}
+	ranks := map[string]int{}
+	for id, d := range details {
+		ranks[id] = New(nil).Normalize(d).SeverityRank
+	}
+
+	// No score is ranked as UNKNOWN like a missing score, below the lowest score rather than being the lowest score
+	assert.False(t, details["NSWG-ECO-334"][NodejsSecurityWg].HasCvssScore())
+	assert.Equal(t, ranks["CVE-2020-12345"], ranks["NSWG-ECO-334"])
+	assert.Equal(t, int(types.SeverityUnknown), ranks["NSWG-ECO-334"])
+	assert.Less(t, ranks["NSWG-ECO-334"], ranks["CVE-2020-1234"])
+}
+
+func TestNormalizeConstraint(t *testing.T) {
+	tests := []struct {
+		name      string
+		ecosystem types.Ecosystem
+		raw       string
+		want      string
+	}{
+		{
+			name:      "space",
+			ecosystem: Pip,
+			raw:       ">=1.0.0 <2.0.0",
+			want:      ">=1.0.0, <2.0.0",
+		},
+		{
+			name:      "comma and space",
+			ecosystem: Pip,
+			raw:       ">=1.0.0, <2.0.0",
+			want:      ">=1.0.0, <2.0.0",
+		},
+		{
+			name:      "comma",
+			ecosystem: Pip,
+			raw:       ">=1.0.0,<2.0.0",
+			want:      ">=1.0.0, <2.0.0",
+		},
+		{
+			name:      "npm space",
+			ecosystem: Npm,
+			raw:       ">=1.0.0 <2.0.0",
+			want:      ">=1.0.0 <2.0.0",
+		},
+		{
+			name:      "npm comma and space",
+			ecosystem: Npm,
+			raw:       ">=1.0.0, <2.0.0",
+			want:      ">=1.0.0 <2.0.0",
+		},
+		{
+			name:      "npm comma",
+			ecosystem: Npm,
+			raw:       ">=1.0.0,<2.0.0",
+			want:      ">=1.0.0 <2.0.0",
+		},
+		{
+			name:      "spaces after operators",
+			ecosystem: Composer,
+			raw:       " >= 1.0.0 ,  < 2.0.0 ",
+			want:      ">=1.0.0, <2.0.0",
+		},
+		{
+			name:      "or",
+			ecosystem: Npm,
+			raw:       "<1.2.3||>=2.0.0,<2.0.5",
+			want:      "<1.2.3 || >=2.0.0 <2.0.5",
+		},
+		{
+			name:      "single",
+			ecosystem: RubyGems,
+			raw:       "< 5.2.4.3",
+			want:      "<5.2.4.3",
+		},
+		{
+			name:      "interval",
+			ecosystem: Maven,
+			raw:       "[1.0,2.0)",
+			want:      "[1.0,2.0)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeConstraint(tt.ecosystem, tt.raw)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		details map[types.SourceID]types.VulnerabilityDetail
+		want    []string
+	}{
+		{
+			name: "http and https variants",
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				NVD: {
+					References: []string{
+						"http://www.openwall.com/lists/oss-security/2021/08/24/1",
+						"https://curl.se/docs/CVE-2021-22945.html",
+					},
+				},
+				Debian: {
+					References: []string{
+						"https://www.openwall.com/lists/oss-security/2021/08/24/1",
+						"HTTP://curl.se/docs/CVE-2021-22945.html",
+					},
+				},
+			},
+			want: []string{
+				"https://curl.se/docs/CVE-2021-22945.html",
+				"https://www.openwall.com/lists/oss-security/2021/08/24/1",
+			},
+		},
+		{
+			name: "http only",
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				NVD: {
+					References: []string{"http://foo-bar.com/baz", "http://foo-bar.com/baz"},
+				},
+			},
+			want: []string{"http://foo-bar.com/baz"},
+		},
+		{
+			name: "different paths",
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				NVD: {
+					References: []string{"http://foo-bar.com/baz", "https://foo-bar.com/qux"},
+				},
+			},
+			want: []string{"http://foo-bar.com/baz", "https://foo-bar.com/qux"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getReferences(tt.details))
+		})
+	}
+}
+
+func TestFilterIntroduced(t *testing.T) {
+	advisories := []types.Advisory{
+		{
+			VulnerabilityID:   "CVE-2021-28363",
+			PatchedVersions:   []string{"1.26.4"},
+			IntroducedVersion: "1.26.0",
+		},
+		{
+			VulnerabilityID: "CVE-2020-26137",
+			PatchedVersions: []string{"1.25.9"},
+		},
+	}
+	tests := []struct {
+		name       string
+		pkgVersion string
+		want       []string
+	}{
+		{
+			name:       "older than the introduced version",
+			pkgVersion: "1.25.11",
+			want:       []string{"CVE-2020-26137"},
+		},
+		{
+			name:       "introduced version",
+			pkgVersion: "1.26.0",
+			want:       []string{"CVE-2021-28363", "CVE-2020-26137"},
+		},
+		{
+			name:       "newer than the introduced version",
+			pkgVersion: "1.26.3",
+			want:       []string{"CVE-2021-28363", "CVE-2020-26137"},
+		},
+		{
+			name:       "invalid version",
+			pkgVersion: "invalid",
+			want:       []string{"CVE-2021-28363", "CVE-2020-26137"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, adv := range FilterIntroduced(advisories, tt.pkgVersion) {
+				got = append(got, adv.VulnerabilityID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package vulnsrc
 
 import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/alma"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/alpine"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/amazon"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/appsec"
 	archlinux "github.com/aquasecurity/trivy-db/pkg/vulnsrc/arch-linux"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/bundler"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/composer"
@@ -15,6 +21,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/mariner"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/node"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/nvd"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/openssl"
 	oracleoval "github.com/aquasecurity/trivy-db/pkg/vulnsrc/oracle-oval"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/osv"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/photon"
@@ -25,40 +32,127 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/ubuntu"
 )
 
+// VulnSrc is a data source of the DB. It is a stable contract that sources maintained outside this repository
+// can implement and pass to Register.
+//
+// Name returns the unique ID of the source, which is used as the build target, e.g. "nvd".
+// Update reads the data under dir, the cache directory containing e.g. "vuln-list", and writes it to the DB,
+// typically in db.Config.BatchUpdate. The built-in sources write to the DB of the config given to NewAll,
+// e.g. the one returned by db.Config.Open, and the others to the DB opened with db.Init.
 type VulnSrc interface {
 	Name() types.SourceID
 	Update(dir string) (err error)
 }
 
+// NamespaceReporter is implemented by sources knowing the namespaces they write before updating.
+// It is separate from VulnSrc since most OS sources create namespaces per release found in the data.
+type NamespaceReporter interface {
+	Namespaces() []string
+}
+
+// Namespaces returns the namespaces of the source, or nil if the source doesn't report them.
+func Namespaces(src VulnSrc) []string {
+	if r, ok := src.(NamespaceReporter); ok {
+		return r.Namespaces()
+	}
+	return nil
+}
+
+// ContextUpdater is implemented by sources able to stop updating when the context is done,
+// e.g. on db.Config.SourceTimeout. The built-in sources stop writing to the DB, so that their transaction is rolled back.
+type ContextUpdater interface {
+	UpdateContext(ctx context.Context, dir string) (err error)
+}
+
+// Update updates the source and returns the error of the context if it is done first.
+// The source keeps running in the background after that until it notices the context or finishes.
+// Sources not implementing ContextUpdater can't notice it, so their writes may still land in the DB.
+func Update(ctx context.Context, src VulnSrc, dir string) error {
+	errCh := make(chan error, 1)
+	go func() {
+		if u, ok := src.(ContextUpdater); ok {
+			errCh <- u.UpdateContext(ctx, dir)
+			return
+		}
+		errCh <- src.Update(dir)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 var (
-	// All holds all data sources
-	All = []VulnSrc{
+	// All holds all data sources built into this repository
+	All = NewAll(db.Config{})
+
+	registryMu sync.RWMutex
+	registry   []VulnSrc
+)
+
+// Register adds a data source maintained outside this repository, so that it is built along with the built-in ones.
+// It is intended to be called in init() of the package implementing the source.
+// Register panics if a source with the same name already exists.
+func Register(src VulnSrc) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, s := range append(builtin(db.Config{}), registry...) {
+		if s.Name() == src.Name() {
+			panic(fmt.Sprintf("vulnsrc: %s is already registered", src.Name()))
+		}
+	}
+	registry = append(registry, src)
+}
+
+// Registered returns the data sources added by Register in the registration order.
+func Registered() []VulnSrc {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]VulnSrc(nil), registry...)
+}
+
+// NewAll returns all data sources, followed by the registered ones. The built-in sources receive the given DB config.
+func NewAll(dbc db.Config) []VulnSrc {
+	return append(builtin(dbc), Registered()...)
+}
+
+func builtin(dbc db.Config) []VulnSrc {
+	return []VulnSrc{
 		// NVD
-		nvd.NewVulnSrc(),
+		nvd.NewVulnSrc(nvd.WithDBConfig(dbc)),
 
 		// OS packages
-		alma.NewVulnSrc(),
-		alpine.NewVulnSrc(),
-		archlinux.NewVulnSrc(),
-		redhat.NewVulnSrc(),
-		redhatoval.NewVulnSrc(),
-		debian.NewVulnSrc(),
-		ubuntu.NewVulnSrc(),
-		amazon.NewVulnSrc(),
-		oracleoval.NewVulnSrc(),
-		rocky.NewVulnSrc(),
-		susecvrf.NewVulnSrc(susecvrf.SUSEEnterpriseLinux),
-		susecvrf.NewVulnSrc(susecvrf.OpenSUSE),
-		photon.NewVulnSrc(),
-		mariner.NewVulnSrc(),
+		alma.NewVulnSrc(alma.WithDBConfig(dbc)),
+		alpine.NewVulnSrc(alpine.WithDBConfig(dbc)),
+		archlinux.NewVulnSrc(archlinux.WithDBConfig(dbc)),
+		redhat.NewVulnSrc(redhat.WithDBConfig(dbc)),
+		redhatoval.NewVulnSrc(redhatoval.WithDBConfig(dbc)),
+		debian.NewVulnSrc(debian.WithDBConfig(dbc)),
+		ubuntu.NewVulnSrc(ubuntu.WithDBConfig(dbc)),
+		amazon.NewVulnSrc(amazon.WithDBConfig(dbc)),
+		oracleoval.NewVulnSrc(oracleoval.WithDBConfig(dbc)),
+		rocky.NewVulnSrc(rocky.WithDBConfig(dbc)),
+		susecvrf.NewVulnSrc(susecvrf.SUSEEnterpriseLinux, susecvrf.WithDBConfig(dbc)),
+		susecvrf.NewVulnSrc(susecvrf.OpenSUSE, susecvrf.WithDBConfig(dbc)),
+		photon.NewVulnSrc(photon.WithDBConfig(dbc)),
+		mariner.NewVulnSrc(mariner.WithDBConfig(dbc)),
 
 		// Language-specific packages
-		bundler.NewVulnSrc(),
-		composer.NewVulnSrc(),
-		node.NewVulnSrc(),
-		ghsa.NewVulnSrc(),
-		glad.NewVulnSrc(),
-		govulndb.NewVulnSrc(),
-		osv.NewVulnSrc(),
+		bundler.NewVulnSrc(bundler.WithDBConfig(dbc)),
+		composer.NewVulnSrc(composer.WithDBConfig(dbc)),
+		node.NewVulnSrc(node.WithDBConfig(dbc)),
+		ghsa.NewVulnSrc(ghsa.WithDBConfig(dbc)),
+		glad.NewVulnSrc(glad.WithDBConfig(dbc)),
+		govulndb.NewVulnSrc(govulndb.WithDBConfig(dbc)),
+		osv.NewVulnSrc(osv.WithDBConfig(dbc)),
+
+		// Libraries built from source
+		openssl.NewVulnSrc(openssl.WithDBConfig(dbc)),
+
+		// Applications such as databases
+		appsec.NewVulnSrc(appsec.WithDBConfig(dbc)),
 	}
-)
+}